@@ -63,7 +63,7 @@ func main() {
 	util.ExitOnErr(err)
 
 	// Get app config object
-	appInfo, err := appinfo.New(pathDirCurr, pathDirHome, GetVersion())
+	appInfo, err := appinfo.New(pathDirCurr, pathDirHome, GetVersion(), appinfo.OverrideFromEnv())
 	util.ExitOnErr(err)
 
 	// Initialize the app then executes the designated command.
@@ -0,0 +1,189 @@
+/*
+Package cmdagenda defines the "agenda" command.
+*/
+package cmdagenda
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/agenda"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo    *appinfo.AppInfo
+	styleTable string // flag for "--style" option
+	top        int    // flag for "--top" option
+	staleDays  int    // flag for "--stale" option
+	isGlobal   bool   // flag for "--global" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "agenda" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdAgenda := new(Command)
+
+	// コマンドの割り当て
+	cmdAgenda.Command = &cobra.Command{
+		Use:   "agenda",
+		Short: "今日の見直し用に、期日や状態ごとにタスクを表示します",
+		Long: util.HereDoc(`
+				About:
+				  'agenda' コマンドは、日々の見直し（デイリー・レビュー）用に、以下の
+				  セクションごとにタスクを表示します。
+
+				    - 期限切れ: 期日（"due:"）を過ぎた未完了のタスク
+				    - 今日が期限: 期日が今日の未完了のタスク
+				    - 今週が期限: 期日が明日から 7 日以内の未完了のタスク
+				    - 上位のタスク: 一覧の上から '--top' 件の未完了のタスク
+				    - 昨日完了したタスク: 昨日完了にしたタスク
+				    - ながらく手を付けていないタスク: 最後に変更した日から '--stale' 日
+				      以上経った未完了のタスク
+
+				  上位のタスクは 'sort' で並べ替えた優先度の高い順で、"dep:" タグでブ
+				  ロックされているタスクは除かれます。最後に変更した日は "touched:" タグ
+				  の日付で、ない場合は作成日です。
+
+				  '--style' には text, color, markdown, html のいずれかを指定します。
+			`),
+		Example: util.HereDoc(`
+				qiitask agenda
+				qiitask agenda --top 10 --stale 14
+				qiitask agenda --style markdown > ./agenda.md
+				qiitask agenda --global
+			`, "  "),
+		Args: cobra.NoArgs,
+	}
+
+	// Set app info (conf and tasks)
+	cmdAgenda.AppInfo = appInfo
+
+	// RunE function
+	cmdAgenda.Command.RunE = cmdAgenda.Agenda
+
+	// Define flags for `agenda` command.
+	cmdAgenda.Flags().StringVarP(
+		&cmdAgenda.styleTable, "style", "s", "text", "表示スタイルを指定します。(text, color, markdown, html)",
+	)
+	cmdAgenda.Flags().IntVarP(
+		&cmdAgenda.top, "top", "n", agenda.TopDefault, "上位のタスクとして表示するタスク数を指定します",
+	)
+	cmdAgenda.Flags().IntVar(
+		&cmdAgenda.staleDays, "stale", agenda.StaleDaysDefault, "手を付けていないとみなすまでの日数を指定します",
+	)
+	cmdAgenda.Flags().BoolVarP(
+		&cmdAgenda.isGlobal, "global", "g", false, "グローバル・タスクを表示します",
+	)
+
+	return cmdAgenda.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Agenda は "agenda" コマンドの本体です。
+func (c *Command) Agenda(cmd *cobra.Command, args []string) error {
+	if c.top < 1 {
+		return errors.Errorf("上位のタスク数は 1 以上を指定してください: %v", c.top)
+	}
+
+	if c.staleDays < 1 {
+		return errors.Errorf("日数は 1 以上を指定してください: %v", c.staleDays)
+	}
+
+	style, err := tableStyle(c.styleTable)
+	if err != nil {
+		return err
+	}
+
+	taskList := c.getTaskList()
+
+	if taskList.Len() < 1 {
+		return errors.New("まだタスクはありません")
+	}
+
+	sections := agenda.Build(taskList, agenda.Options{Top: c.top, StaleDays: c.staleDays})
+
+	for i, section := range sections {
+		if i > 0 {
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+
+		drawSection(cmd.OutOrStdout(), section, style)
+	}
+
+	return nil
+}
+
+func (c *Command) getTaskList() *todo.Todo {
+	taskList := c.AppInfo.Tasks.Local
+
+	if c.isGlobal || taskList.FileUsed() == "" {
+		taskList = c.AppInfo.Tasks.Global
+	}
+
+	return taskList
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// drawSection は section の見出しとタスクの表を style で w に描画します。
+func drawSection(w io.Writer, section agenda.Section, style cui.TableStyle) {
+	ui := cui.New()
+	ui.MirrorIO = w
+
+	ui.DrawHeading(fmt.Sprintf("%v（%d 件）", section.Title, len(section.Items)), style)
+
+	if len(section.Items) == 0 {
+		ui.DrawNote("なし", style)
+
+		return
+	}
+
+	tableTmp := table.NewWriter()
+	tableTmp.AppendHeader(table.Row{"#", "title", section.Header})
+
+	for _, item := range section.Items {
+		tableTmp.AppendRow(table.Row{item.Task.ID, item.Task.Todo, item.Detail})
+	}
+
+	ui.DrawTable(tableTmp, style) // テーブルの描画
+}
+
+// tableStyle は "--style" オプションの値に対応するテーブルのスタイル ID を返しま
+// す。
+func tableStyle(name string) (cui.TableStyle, error) {
+	switch name {
+	case "text":
+		return cui.AsDefaultTable, nil
+	case "color":
+		return cui.AsColoredTable, nil
+	case "markdown":
+		return cui.AsMarkdownTable, nil
+	case "html":
+		return cui.AsHTMLTable, nil
+	}
+
+	return cui.AsDefaultTable, errors.Errorf("未対応のスタイルです: %v（text, color, markdown, html のいずれかを指定してください）", name)
+}
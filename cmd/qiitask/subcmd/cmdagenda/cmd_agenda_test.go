@@ -0,0 +1,115 @@
+package cmdagenda_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdagenda"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/kami-zh/go-capturer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dataTasks = "" +
+	"2020-11-01 old task\n" +
+	"overdue <task> due:2021-01-05\n" +
+	"x 2021-01-05 done yesterday\n"
+
+// runAgenda はローカルに data のタスクを持つアプリで、今日を 2021-01-06 として
+// "agenda" コマンドを実行し、出力とエラーを返します。
+func runAgenda(t *testing.T, data string, args ...string) (string, error) {
+	t.Helper()
+
+	testutil.MockTimeNow(t, time.Date(2021, 1, 6, 9, 0, 0, 0, time.Local))
+
+	pathDirLocal := t.TempDir()
+
+	if data != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, todo.NameFile), []byte(data), 0o600))
+	}
+
+	appInfo, err := appinfo.New(pathDirLocal, t.TempDir(), "")
+	require.NoError(t, err)
+
+	app := cmdroot.New(appInfo)
+	app.SetArgs(append([]string{"agenda"}, args...))
+
+	var errRun error
+
+	out := capturer.CaptureOutput(func() {
+		errRun = app.Execute()
+	})
+
+	return out, errRun
+}
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj1 := cmdagenda.New(appInfo)
+	obj2 := cmdagenda.New(appInfo)
+
+	assert.NotSame(t, obj1, obj2, "it should not reference the same object")
+	assert.Equal(t, "agenda", obj1.Name())
+}
+
+func TestAgenda_text(t *testing.T) {
+	out, err := runAgenda(t, dataTasks)
+	require.NoError(t, err)
+
+	for _, expect := range []string{
+		"■ 期限切れ（1 件）",
+		"2021-01-05（1 日超過）",
+		"■ 今日が期限（0 件）\n  なし\n",
+		"■ 上位 5 件のタスク（2 件）",
+		"■ 昨日完了したタスク（1 件）",
+		"2021-01-05（火）",
+		"■ ながらく手を付けていないタスク（1 件）",
+		"66 日",
+	} {
+		assert.Contains(t, out, expect)
+	}
+}
+
+func TestAgenda_markdown(t *testing.T) {
+	out, err := runAgenda(t, dataTasks, "--style", "markdown", "--top", "1", "--stale", "100")
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "## 期限切れ（1 件）\n\n| # | title | due |")
+	assert.Contains(t, out, "## 上位 1 件のタスク（1 件）\n\n| # | title | pri |\n| ---:| --- | --- |\n| 1 | old task |  |\n")
+	assert.Contains(t, out, "## ながらく手を付けていないタスク（0 件）\n\nなし\n")
+}
+
+func TestAgenda_html(t *testing.T) {
+	out, err := runAgenda(t, dataTasks, "--style", "html")
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "<h2>期限切れ（1 件）</h2>\n<table class=\"go-pretty-table\">")
+	assert.Contains(t, out, "overdue &lt;task&gt;")
+	assert.Contains(t, out, "<h2>今日が期限（0 件）</h2>\n<p>なし</p>\n")
+}
+
+func TestAgenda_errors(t *testing.T) {
+	for _, test := range []struct {
+		data   string
+		args   []string
+		expect string
+	}{
+		{dataTasks, []string{"--style", "csv"}, "未対応のスタイルです: csv"},
+		{dataTasks, []string{"--top", "0"}, "上位のタスク数は 1 以上を指定してください: 0"},
+		{dataTasks, []string{"--stale", "-1"}, "日数は 1 以上を指定してください: -1"},
+		{"", []string{}, "まだタスクはありません"},
+	} {
+		out, err := runAgenda(t, test.data, test.args...)
+
+		require.Error(t, err, "args: %v", test.args)
+		assert.Contains(t, out, test.expect, "args: %v", test.args)
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Package cmddedupe defines the "dedupe" command.
+*/
+package cmddedupe
+
+import (
+	"fmt"
+
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// 重複の候補に対する選択肢。
+const (
+	answerMerge  = "統合する（2 つ目を 1 つ目に統合）"
+	answerKeep   = "両方残す"
+	answerDelete = "2 つ目を削除する"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo   *appinfo.AppInfo
+	CUI       *cui.UI
+	threshold float64 // flag for "--threshold" option
+	isGlobal  bool    // flag for "--global" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "dedupe" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdDedupe := new(Command)
+
+	// コマンドの割り当て
+	cmdDedupe.Command = &cobra.Command{
+		Use:   "dedupe",
+		Short: "重複したタスクを探して統合します",
+		Long: util.HereDoc(`
+				About:
+				  'dedupe' コマンドは、未完了のタスクから内容の似ているタスクの組を探
+				  し、組ごとに「統合する」「両方残す」「削除する」を選択します。
+
+				  タスクの本文は、全角・半角、ひらがな・カタカナ、英字の大文字・小文
+				  字、空白や記号の違いを無視して比較されます。'--threshold' で重複と
+				  みなす類似度（0 より大きく 1 以下、1 は完全一致）を指定できま
+				  す。
+
+				  統合すると、2 つ目のタスクのプロジェクト・コンテキスト・タグが 1 つ
+				  目のタスクに追加され、2 つ目のタスクは削除されます。2 つ目のタスク
+				  を "parent:" や "dep:" で参照しているタスクは、1 つ目のタスクを参照
+				  するように変更されます。
+			`),
+		Example: util.HereDoc(`
+				qiitask dedupe
+				qiitask dedupe --threshold 0.6
+				qiitask dedupe --global
+			`, "  "),
+		Args: cobra.NoArgs,
+	}
+
+	// Set app info (conf and tasks)
+	cmdDedupe.AppInfo = appInfo
+
+	// Add CUI object
+	cmdDedupe.CUI = cui.New()
+
+	// RunE function
+	cmdDedupe.Command.RunE = cmdDedupe.Dedupe
+
+	// Define flags for `dedupe` command.
+	cmdDedupe.Flags().Float64VarP(
+		&cmdDedupe.threshold, "threshold", "t", todo.SimilarityDefault, "重複とみなす類似度（0 より大きく 1 以下）を指定します",
+	)
+	cmdDedupe.Flags().BoolVarP(
+		&cmdDedupe.isGlobal, "global", "g", false, "グローバル・タスクを対象にします",
+	)
+
+	return cmdDedupe.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Dedupe は "dedupe" コマンドの本体です。
+func (c *Command) Dedupe(cmd *cobra.Command, args []string) error {
+	// フラグを持たないコマンドから呼び出された場合のみデフォルト値を使う
+	if c.threshold == 0 && !cmd.Flags().Changed("threshold") {
+		c.threshold = todo.SimilarityDefault
+	}
+
+	if c.threshold <= 0 || c.threshold > 1 {
+		return errors.Errorf("類似度は 0 より大きく 1 以下で指定してください: %v", c.threshold)
+	}
+
+	taskList := c.getTaskList()
+
+	duplicates := taskList.FindDuplicates(c.threshold)
+	if len(duplicates) == 0 {
+		cmd.Println("重複の候補は見つかりませんでした")
+
+		return nil
+	}
+
+	// 統合や削除でタスクのポインタが無効になるため、ID で扱う
+	pairs := make([][2]int, len(duplicates))
+	for i, duplicate := range duplicates {
+		pairs[i] = [2]int{duplicate.A.ID, duplicate.B.ID}
+	}
+
+	removed := map[int]bool{}
+	countMerged, countDeleted := 0, 0
+
+	for i, pair := range pairs {
+		if removed[pair[0]] || removed[pair[1]] {
+			continue
+		}
+
+		answer, err := c.askAction(taskList, pair, duplicates[i].Similarity)
+		if err != nil {
+			return errors.Wrap(err, "重複の確認を中断しました（タスクに変更はありません）")
+		}
+
+		switch answer {
+		case answerMerge:
+			if err := c.merge(taskList, pair); err != nil {
+				return err
+			}
+
+			countMerged++
+		case answerDelete:
+			if err := taskList.RemoveTaskByID(pair[1]); err != nil {
+				return errors.Wrap(err, "failed to remove task")
+			}
+
+			countDeleted++
+		default:
+			continue
+		}
+
+		removed[pair[1]] = true
+	}
+
+	if countMerged+countDeleted == 0 {
+		cmd.Println("タスクは変更されませんでした")
+
+		return nil
+	}
+
+	if err := taskList.OverWrite(c.CUI); err != nil {
+		return err
+	}
+
+	cmd.Println(fmt.Sprintf("%d 件のタスクを統合、%d 件のタスクを削除しました", countMerged, countDeleted))
+
+	return nil
+}
+
+// askAction は pair のタスクの扱いをユーザに問い合わせます。
+func (c *Command) askAction(taskList *todo.Todo, pair [2]int, similarity float64) (string, error) {
+	a, err := taskList.GetTask(pair[0])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get task")
+	}
+
+	b, err := taskList.GetTask(pair[1])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get task")
+	}
+
+	c.CUI.DrawHR()
+
+	return c.CUI.Select(
+		fmt.Sprintf(
+			"重複の候補です（類似度 %.0f%%）\n    1: %v\n    2: %v\n  どうしますか",
+			similarity*100, a.String(), b.String(),
+		),
+		[]string{answerMerge, answerKeep, answerDelete},
+		answerKeep,
+		"統合すると、2 つ目のプロジェクト・コンテキスト・タグを 1 つ目に追加して 2 つ目を削除します。",
+	)
+}
+
+// merge は pair の 2 つ目のタスクを 1 つ目のタスクに統合します。
+func (c *Command) merge(taskList *todo.Todo, pair [2]int) error {
+	keep, err := taskList.GetTask(pair[0])
+	if err != nil {
+		return errors.Wrap(err, "failed to get task")
+	}
+
+	drop, err := taskList.GetTask(pair[1])
+	if err != nil {
+		return errors.Wrap(err, "failed to get task")
+	}
+
+	return taskList.MergeTask(keep, drop)
+}
+
+func (c *Command) getTaskList() *todo.Todo {
+	taskList := c.AppInfo.Tasks.Local
+
+	if c.isGlobal || taskList.FileUsed() == "" {
+		taskList = c.AppInfo.Tasks.Global
+	}
+
+	return taskList
+}
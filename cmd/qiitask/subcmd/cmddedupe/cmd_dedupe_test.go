@@ -0,0 +1,99 @@
+package cmddedupe_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmddedupe"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dataTasks = "ﾚﾋﾞｭｰする +a\nfix bug\nれびゅーする +b @home\nＦｉｘ　Ｂｕｇ！ due:2021-02-01\nother\n"
+
+// ----------------------------------------------------------------------------
+//  Helper Functions
+// ----------------------------------------------------------------------------
+
+// dedupe はローカルに data のタスクを持つアプリで "dedupe" コマンドを実行し、出
+// 力とタスク・ファイルの内容を返します。
+func dedupe(t *testing.T, data string) (string, string) {
+	t.Helper()
+
+	appInfo := testutil.NewAppInfo(t, data)
+
+	out, err := testutil.Execute(cmddedupe.New(appInfo))
+	require.NoError(t, err)
+
+	return out, testutil.ReadFile(t, appInfo.Tasks.Local.FileUsed())
+}
+
+// ----------------------------------------------------------------------------
+//  Tests
+// ----------------------------------------------------------------------------
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj1 := cmddedupe.New(appInfo)
+	obj2 := cmddedupe.New(appInfo)
+
+	assert.NotSame(t, obj1, obj2, "it should not reference the same object")
+	assert.Equal(t, "dedupe", obj1.Name())
+}
+
+func TestDedupe_merge_and_delete(t *testing.T) {
+	testutil.MockAnswers(t, "統合する（2 つ目を 1 つ目に統合）", "2 つ目を削除する")
+
+	out, data := dedupe(t, dataTasks)
+
+	assert.Contains(t, out, "1 件のタスクを統合、1 件のタスクを削除しました")
+	assert.Equal(t, "ﾚﾋﾞｭｰする @home +a +b touched:2021-01-06\nfix bug\nother\n", data)
+}
+
+func TestDedupe_keep_both(t *testing.T) {
+	testutil.MockAnswers(t, "両方残す", "両方残す")
+
+	out, data := dedupe(t, dataTasks)
+
+	assert.Contains(t, out, "タスクは変更されませんでした")
+	assert.Equal(t, dataTasks, data)
+}
+
+func TestDedupe_no_duplicates(t *testing.T) {
+	out, _ := dedupe(t, "review\nfix bug\n")
+
+	assert.Contains(t, out, "重複の候補は見つかりませんでした")
+}
+
+func TestDedupe_cancel(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, dataTasks)
+
+	testutil.MockAskOne(t, func(p survey.Prompt) (interface{}, error) {
+		return nil, errors.New("interrupted")
+	})
+
+	_, err := testutil.Execute(cmddedupe.New(appInfo))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "重複の確認を中断しました")
+	assert.Equal(t, dataTasks, testutil.ReadFile(t, appInfo.Tasks.Local.FileUsed()),
+		"it should not change the file on cancel")
+}
+
+func TestDedupe_bad_threshold(t *testing.T) {
+	for _, threshold := range []string{"1.5", "0", "-0.1"} {
+		appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+		require.NoError(t, err)
+
+		out, err := testutil.Execute(cmdroot.New(appInfo), "dedupe", "--threshold", threshold)
+
+		require.Error(t, err, "threshold: %v", threshold)
+		assert.Contains(t, out, "類似度は 0 より大きく 1 以下で指定してください", "threshold: %v", threshold)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Package cmddone defines the "done" command.
+*/
+package cmddone
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/1set/todotxt"
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo  *appinfo.AppInfo
+	CUI      *cui.UI
+	isGlobal bool // flag for "--global" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "done" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdDone := new(Command)
+
+	// コマンドの割り当て
+	cmdDone.Command = &cobra.Command{
+		Use:   "done ID",
+		Short: "タスクを完了にします",
+		Long: util.HereDoc(`
+				About:
+				  'done' コマンドは、指定した ID のタスクを完了（"x" 付き）にします。
+
+				  "id:" と "parent:" タグで子タスクを持つタスクの場合は、未完了の子タ
+				  スクもあわせて完了にするか確認します。
+			`),
+		Example: util.HereDoc(`
+				qiitask done 3
+				qiitask done G7           // グローバルの 7 番のタスクを完了にします
+				qiitask done 2 --global   // 同上（グローバルの 2 番）
+			`, "  "),
+		Args: cobra.ExactArgs(1),
+	}
+
+	// Set app info (conf and tasks)
+	cmdDone.AppInfo = appInfo
+
+	// Add CUI object
+	cmdDone.CUI = cui.New()
+
+	// RunE function
+	cmdDone.Command.RunE = cmdDone.Done
+
+	// Define flags for `done` command.
+	cmdDone.Flags().BoolVarP(
+		&cmdDone.isGlobal, "global", "g", false, "グローバル・タスクを対象にします",
+	)
+
+	return cmdDone.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Done は "done" コマンドの本体です。
+func (c *Command) Done(cmd *cobra.Command, args []string) error {
+	taskList, task, err := c.AppInfo.Tasks.Resolve(args[0], c.isGlobal)
+	if err != nil {
+		return err
+	}
+
+	if task.Completed {
+		return errors.Errorf("タスクはすでに完了しています: %v", task.Todo)
+	}
+
+	children := []*todotxt.Task{}
+
+	for _, child := range taskList.Descendants(task) {
+		if !child.Completed {
+			children = append(children, child)
+		}
+	}
+
+	if len(children) > 0 {
+		todos := make([]string, len(children))
+
+		for i, child := range children {
+			todos[i] = "    " + child.Todo
+		}
+
+		msg := fmt.Sprintf(
+			"未完了の子タスクが %d 件あります。あわせて完了にしますか？\n%v",
+			len(children), strings.Join(todos, "\n"),
+		)
+
+		isYes, err := c.CUI.Confirm(msg)
+		if err != nil {
+			return errors.Wrap(err, "error during confirmation")
+		}
+
+		if !isYes {
+			children = []*todotxt.Task{}
+		}
+	}
+
+	task.Complete()
+
+	for _, child := range children {
+		child.Complete()
+	}
+
+	if err := taskList.OverWrite(c.CUI); err != nil {
+		return err
+	}
+
+	cmd.Println(fmt.Sprintf("タスクを完了にしました: %v", task.Todo))
+
+	if len(children) > 0 {
+		cmd.Println(fmt.Sprintf("    子タスク %d 件も完了にしました", len(children)))
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package cmddone_test
+
+import (
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmddone"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dataTasks = "epic id:epic\nstep 1 parent:epic\nx 2021-01-01 step 2 parent:epic\nother\n"
+
+// isCompleted は ID のローカル・タスクが完了している場合に true を返します。
+func isCompleted(t *testing.T, appInfo *appinfo.AppInfo, id int) bool {
+	t.Helper()
+
+	task, err := appInfo.Tasks.Local.GetTask(id)
+	require.NoError(t, err)
+
+	return task.Completed
+}
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj1 := cmddone.New(appInfo)
+	obj2 := cmddone.New(appInfo)
+
+	assert.NotSame(t, obj1, obj2, "it should not reference the same object")
+	assert.Equal(t, "done", obj1.Name())
+}
+
+func TestDone(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, dataTasks)
+
+	out, err := testutil.Execute(cmddone.New(appInfo), "4")
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "タスクを完了にしました: other")
+	assert.True(t, isCompleted(t, appInfo, 4))
+	assert.False(t, isCompleted(t, appInfo, 1))
+}
+
+func TestDone_with_children(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, dataTasks)
+
+	testutil.MockAnswers(t, true)
+
+	out, err := testutil.Execute(cmddone.New(appInfo), "1")
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "タスクを完了にしました: epic")
+	assert.Contains(t, out, "子タスク 1 件も完了にしました")
+	assert.True(t, isCompleted(t, appInfo, 1))
+	assert.True(t, isCompleted(t, appInfo, 2))
+}
+
+func TestDone_without_children(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, dataTasks)
+
+	testutil.MockAnswers(t, false)
+
+	out, err := testutil.Execute(cmddone.New(appInfo), "1")
+	require.NoError(t, err)
+
+	assert.NotContains(t, out, "子タスク")
+	assert.True(t, isCompleted(t, appInfo, 1))
+	assert.False(t, isCompleted(t, appInfo, 2), "the child should stay undone if denied")
+}
+
+func TestDone_already_completed(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, dataTasks)
+
+	_, err := testutil.Execute(cmddone.New(appInfo), "3")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "タスクはすでに完了しています")
+}
+
+func TestDone_unknown_task(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, dataTasks)
+
+	_, err := testutil.Execute(cmddone.New(appInfo), "99")
+
+	require.Error(t, err)
+}
@@ -0,0 +1,181 @@
+/*
+Package cmdestimate defines the "estimate" command.
+*/
+package cmdestimate
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/1set/todotxt"
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/estimate"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo  *appinfo.AppInfo
+	CUI      *cui.UI
+	isGlobal bool // flag for "--global" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "estimate" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdEstimate := new(Command)
+
+	// コマンドの割り当て
+	cmdEstimate.Command = &cobra.Command{
+		Use:   "estimate",
+		Short: "タスクの作業量を対話式で見積ります",
+		Long: util.HereDoc(`
+				About:
+				  'estimate' コマンドは、未完了のタスクを作業量の質問（「作業量が少な
+				  いのはどちらですか」など）のみで対話式に並べ替え、その順位をフィボ
+				  ナッチ数列のポイント（1, 2, 3, 5, 8, 13, 21）に割り当てて "est:"
+				  タグに書き込みます。タスクの並び順は変更されません。
+
+				  見積りの合計は 'list --estimate' でプロジェクトごとに表示できます。
+				  質問は設定ファイルの "queries_effort" で変更できます。
+			`),
+		Example: util.HereDoc(`
+				qiitask estimate
+				qiitask estimate --global
+			`, "  "),
+		Args: cobra.NoArgs,
+	}
+
+	// Set app info (conf and tasks)
+	cmdEstimate.AppInfo = appInfo
+
+	// Add CUI object
+	cmdEstimate.CUI = cui.New()
+
+	// RunE function
+	cmdEstimate.Command.RunE = cmdEstimate.Estimate
+
+	// Define flags for `estimate` command.
+	cmdEstimate.Flags().BoolVarP(
+		&cmdEstimate.isGlobal, "global", "g", false, "グローバル・タスクを見積ります",
+	)
+
+	return cmdEstimate.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Estimate は "estimate" コマンドの本体です。
+func (c *Command) Estimate(cmd *cobra.Command, args []string) error {
+	taskList := c.getTaskList()
+	tasks := []*todotxt.Task{}
+
+	for i := range *taskList.TaskList {
+		if task := &(*taskList.TaskList)[i]; !task.Completed {
+			tasks = append(tasks, task)
+		}
+	}
+
+	if len(tasks) == 0 {
+		return errors.New("見積れる未完了のタスクはありません")
+	}
+
+	var errAsk error
+
+	// 作業量の少ない順に並べる
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if errAsk != nil {
+			return false
+		}
+
+		isLess, err := c.askIsLessEffort(tasks[i], tasks[j], 0)
+		if err != nil {
+			errAsk = err
+		}
+
+		return isLess
+	})
+
+	if errAsk != nil {
+		return errors.Wrap(errAsk, "見積りを中断しました（タスクに変更はありません）")
+	}
+
+	tableTmp := table.NewWriter()
+	tableTmp.AppendHeader(table.Row{"#", "title", "est"})
+
+	for rank, task := range tasks {
+		points := estimate.Points(rank, len(tasks))
+
+		estimate.Set(task, points)
+		tableTmp.AppendRow(table.Row{task.ID, task.Todo, points})
+	}
+
+	if err := taskList.OverWrite(c.CUI); err != nil {
+		return err
+	}
+
+	ui := cui.New()
+	ui.MirrorIO = cmd.OutOrStdout()
+
+	ui.DrawTable(tableTmp, cui.AsDefaultTable)
+
+	cmd.Println(fmt.Sprintf("%d 件のタスクの見積りを \"%v:\" タグに書き込みました", len(tasks), estimate.TagEstimate))
+
+	return nil
+}
+
+// askIsLessEffort は a の作業量が b より少ないかをユーザに問い合わせます。
+// indexQ は最初に使う質問のインデックスです。
+func (c *Command) askIsLessEffort(a, b *todotxt.Task, indexQ int) (bool, error) {
+	questions := c.AppInfo.Config.GetQueryEffort()
+
+	// Reset index
+	if len(questions) <= indexQ {
+		indexQ = 0
+	}
+
+	idontknow := "質問を変える"
+	selections := []string{
+		a.Todo, b.Todo, idontknow,
+	}
+
+	answer, err := c.CUI.Select(questions[indexQ], selections, idontknow, "")
+	if err != nil {
+		return false, err
+	}
+
+	if answer == idontknow {
+		c.CUI.DrawHR()
+
+		return c.askIsLessEffort(a, b, indexQ+1)
+	}
+
+	return answer == a.Todo, nil
+}
+
+func (c *Command) getTaskList() *todo.Todo {
+	taskList := c.AppInfo.Tasks.Local
+
+	if c.isGlobal || taskList.FileUsed() == "" {
+		taskList = c.AppInfo.Tasks.Global
+	}
+
+	return taskList
+}
@@ -0,0 +1,116 @@
+package cmdestimate_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdestimate"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockShorterIsLess は、選択肢のうち文字数の少ないタスクを作業量が少ないと回答
+// するように cui.SurveyAskOne を置き換えます。asked には質問が記録されます。
+func mockShorterIsLess(t *testing.T, asked *[]string) {
+	t.Helper()
+
+	testutil.MockAskOne(t, func(p survey.Prompt) (interface{}, error) {
+		prompt, ok := p.(*survey.Select)
+		require.True(t, ok)
+
+		*asked = append(*asked, prompt.Message)
+
+		answer := prompt.Options[0]
+		if len(prompt.Options[1]) < len(answer) {
+			answer = prompt.Options[1]
+		}
+
+		return answer, nil
+	})
+}
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "estimate", cmdestimate.New(appInfo).Name())
+}
+
+func TestEstimate(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, "medium task\nx done task\nthe longest task\nshort\n")
+
+	asked := []string{}
+	mockShorterIsLess(t, &asked)
+
+	out, err := testutil.Execute(cmdestimate.New(appInfo))
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "3 件のタスクの見積りを \"est:\" タグに書き込みました")
+
+	for _, question := range asked {
+		assert.Equal(t, appInfo.Config.GetQueryEffort()[0], question, "it should ask effort questions only")
+	}
+
+	expect := "medium task est:3 touched:2021-01-06\n" +
+		"x done task\n" +
+		"the longest task est:8 touched:2021-01-06\n" +
+		"short est:1 touched:2021-01-06\n"
+
+	assert.Equal(t, expect, testutil.ReadFile(t, appInfo.Tasks.Local.FileUsed()),
+		"the order of tasks should not be changed")
+}
+
+func TestEstimate_change_question(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, "task 1\ntask 2\n")
+
+	questions := []string{}
+
+	testutil.MockAskOne(t, func(p survey.Prompt) (interface{}, error) {
+		prompt, ok := p.(*survey.Select)
+		require.True(t, ok)
+
+		questions = append(questions, prompt.Message)
+
+		if len(questions) == 1 {
+			return "質問を変える", nil
+		}
+
+		return "task 2", nil
+	})
+
+	_, err := testutil.Execute(cmdestimate.New(appInfo))
+	require.NoError(t, err)
+
+	require.Len(t, questions, 2)
+	assert.Equal(t, appInfo.Config.GetQueryEffort()[:2], questions)
+
+	assert.Equal(t, "task 1 est:5 touched:2021-01-06\ntask 2 est:1 touched:2021-01-06\n",
+		testutil.ReadFile(t, appInfo.Tasks.Local.FileUsed()))
+}
+
+func TestEstimate_cancel(t *testing.T) {
+	data := "task 1\ntask 2\n"
+	appInfo := testutil.NewAppInfo(t, data)
+
+	testutil.MockAskOne(t, func(p survey.Prompt) (interface{}, error) {
+		return nil, errors.New("interrupted")
+	})
+
+	_, err := testutil.Execute(cmdestimate.New(appInfo))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "見積りを中断しました")
+	assert.Equal(t, data, testutil.ReadFile(t, appInfo.Tasks.Local.FileUsed()))
+}
+
+func TestEstimate_no_task(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, "x done\n")
+
+	_, err := testutil.Execute(cmdestimate.New(appInfo))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "見積れる未完了のタスクはありません")
+}
@@ -0,0 +1,164 @@
+/*
+Package cmdexport defines the "export" command.
+*/
+package cmdexport
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/exporter"
+	"github.com/Qithub-BOT/QiiTask/core/safefile"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo    *appinfo.AppInfo
+	CUI        *cui.UI
+	format     string // flag for "--format" option
+	pathOutput string // flag for "--output" option
+	isGlobal   bool   // flag for "--global" option
+	isAssignID bool   // flag for "--assign-ids" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "export" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdExport := new(Command)
+
+	// コマンドの割り当て
+	cmdExport.Command = &cobra.Command{
+		Use:   "export",
+		Short: "タスクを JSON や iCalendar などの形式で出力します",
+		Long: util.HereDoc(`
+				About:
+				  'export' コマンドは、完了済みを含むすべてのタスクを指定した形式で出
+				  力します。優先度・日付・プロジェクト・コンテキスト・タグのすべての
+				  項目が出力されます。
+
+				  形式（--format）:
+				    json  タスクの全項目の配列（"raw" は todo.txt 形式の元の行）
+				    yaml  json と同じ内容の YAML
+				    ics   iCalendar の VTODO（カレンダー・アプリ向け）
+				    org   Org-mode の見出し（TODO/DONE）
+
+				  形式を指定しない場合は、'--output' のファイルの拡張子から判断し、そ
+				  れ以外は json で出力します。'--output' を指定しない場合は標準出力に
+				  出力します。
+
+				  ics 形式では、カレンダー・アプリで同じタスクとして扱われるよう、
+				  "id:" タグを UID に使います。"id:" タグのないタスクは作成日と本文か
+				  ら UID を作るため、本文を変更すると別のタスクになります。
+				  '--assign-ids' を指定すると、"id:" タグのないタスクに id を付与して
+				  タスク・ファイルを保存してから出力します。
+			`),
+		Example: util.HereDoc(`
+				qiitask export
+				qiitask export --format yaml
+				qiitask export --output ./tasks.ics
+				qiitask export --output ./tasks.ics --assign-ids
+				qiitask export --format org --global > ./global.org
+			`, "  "),
+		Args: cobra.NoArgs,
+	}
+
+	// Set app info (conf and tasks)
+	cmdExport.AppInfo = appInfo
+
+	// Add CUI object
+	cmdExport.CUI = cui.New()
+
+	// RunE function
+	cmdExport.Command.RunE = cmdExport.Export
+
+	// Define flags for `export` command.
+	cmdExport.Flags().StringVarP(
+		&cmdExport.format, "format", "f", "",
+		"出力の形式（"+strings.Join(exporter.Formats(), ", ")+"）を指定します",
+	)
+	cmdExport.Flags().StringVarP(
+		&cmdExport.pathOutput, "output", "o", "", "出力先のファイルを指定します",
+	)
+	cmdExport.Flags().BoolVarP(
+		&cmdExport.isGlobal, "global", "g", false, "グローバル・タスクを出力します",
+	)
+	cmdExport.Flags().BoolVar(
+		&cmdExport.isAssignID, "assign-ids", false, "\"id:\" タグのないタスクに id を付与して保存します",
+	)
+
+	return cmdExport.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Export は "export" コマンドの本体です。
+func (c *Command) Export(cmd *cobra.Command, args []string) error {
+	taskList := c.getTaskList()
+
+	if taskList.Len() < 1 {
+		return errors.New("出力するタスクがありません")
+	}
+
+	format := c.format
+	if format == "" {
+		format = exporter.DetectFormat(c.pathOutput)
+	}
+
+	// 本文を変更しても UID が変わらないよう、"id:" タグを付与して保存する
+	if c.isAssignID && taskList.AssignTagIDs() > 0 {
+		if err := taskList.OverWrite(c.CUI); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+
+	if err := exporter.Write(format, &buf, *taskList.TaskList); err != nil {
+		return err
+	}
+
+	if c.pathOutput == "" {
+		_, err := cmd.OutOrStdout().Write(buf.Bytes())
+
+		return errors.Wrap(err, "failed to write exported tasks")
+	}
+
+	if err := safefile.WriteFile(c.pathOutput, buf.Bytes(), 0); err != nil {
+		return errors.Wrap(err, "failed to write exported tasks")
+	}
+
+	cmd.Println(fmt.Sprintf(
+		"%d 件のタスクを %v 形式で出力しました: %v", taskList.Len(), format, c.pathOutput,
+	))
+
+	return nil
+}
+
+func (c *Command) getTaskList() *todo.Todo {
+	taskList := c.AppInfo.Tasks.Local
+
+	if c.isGlobal || taskList.FileUsed() == "" {
+		taskList = c.AppInfo.Tasks.Global
+	}
+
+	return taskList
+}
@@ -0,0 +1,154 @@
+package cmdexport_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdexport"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/exporter"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/kami-zh/go-capturer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dataTasks = "(A) write docs +docs id:d1\nx 2021-01-03 review\n"
+
+// runExport はローカルに dataTasks のタスクを持つアプリで "export" コマンドを実
+// 行し、出力とエラーを返します。
+func runExport(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	return runExportIn(t, t.TempDir(), args...)
+}
+
+// runExportIn は runExport と同様ですが、pathDirLocal のディレクトリにタスク・
+// ファイルを作成します。
+func runExportIn(t *testing.T, pathDirLocal string, args ...string) (string, error) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, todo.NameFile), []byte(dataTasks), 0o600))
+
+	appInfo, err := appinfo.New(pathDirLocal, t.TempDir(), "")
+	require.NoError(t, err)
+
+	app := cmdroot.New(appInfo)
+	app.SetArgs(append([]string{"export"}, args...))
+
+	var errRun error
+
+	out := capturer.CaptureOutput(func() {
+		errRun = app.Execute()
+	})
+
+	return out, errRun
+}
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj1 := cmdexport.New(appInfo)
+	obj2 := cmdexport.New(appInfo)
+
+	assert.NotSame(t, obj1, obj2, "it should not reference the same object")
+	assert.Equal(t, "export", obj1.Name())
+}
+
+func TestExport_stdout(t *testing.T) {
+	out, err := runExport(t)
+	require.NoError(t, err)
+
+	records := []exporter.Record{}
+
+	require.NoError(t, json.Unmarshal([]byte(out), &records), "default format should be JSON")
+	require.Len(t, records, 2, "completed tasks should be exported too")
+
+	assert.Equal(t, "A", records[0].Priority)
+	assert.Equal(t, "d1", records[0].Tags["id"])
+	assert.True(t, records[1].Completed)
+
+	out, err = runExport(t, "--format", "org")
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "* TODO [#A] write docs :docs:\n")
+	assert.Contains(t, out, "* DONE review\n")
+}
+
+func TestExport_output_file(t *testing.T) {
+	pathFile := filepath.Join(t.TempDir(), "tasks.ics")
+
+	out, err := runExport(t, "--output", pathFile)
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "2 件のタスクを ics 形式で出力しました: "+pathFile)
+
+	data, err := os.ReadFile(pathFile)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), "BEGIN:VCALENDAR\r\n")
+	assert.Contains(t, string(data), "UID:d1@qiitask\r\n")
+}
+
+func TestExport_ics_keeps_file(t *testing.T) {
+	pathDirLocal := t.TempDir()
+	pathFile := filepath.Join(t.TempDir(), "tasks.ics")
+
+	_, err := runExportIn(t, pathDirLocal, "--output", pathFile)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(pathFile)
+	require.NoError(t, err)
+
+	assert.Regexp(t, `UID:task-[0-9a-f]{16}@qiitask\r\n`, string(data),
+		"task without id should get a UID from its content")
+
+	saved, err := os.ReadFile(filepath.Join(pathDirLocal, todo.NameFile))
+	require.NoError(t, err)
+
+	assert.Equal(t, dataTasks, string(saved), "export should not modify the task file by default")
+}
+
+func TestExport_assign_ids(t *testing.T) {
+	pathDirLocal := t.TempDir()
+	pathFile := filepath.Join(t.TempDir(), "tasks.ics")
+
+	_, err := runExportIn(t, pathDirLocal, "--output", pathFile, "--assign-ids")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(pathFile)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), "UID:t1@qiitask\r\n", "task without id should get an id")
+	assert.NotContains(t, string(data), "UID:task-")
+
+	saved, err := os.ReadFile(filepath.Join(pathDirLocal, todo.NameFile))
+	require.NoError(t, err)
+
+	assert.Equal(t, "(A) write docs +docs id:d1\nx 2021-01-03 review id:t1\n", string(saved),
+		"the id should be saved without touching the task")
+}
+
+func TestExport_errors(t *testing.T) {
+	out, err := runExport(t, "--format", "xml")
+
+	require.Error(t, err)
+	assert.Contains(t, out, "未対応の形式です: xml")
+
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	app := cmdroot.New(appInfo)
+	app.SetArgs([]string{"export"})
+
+	out = capturer.CaptureOutput(func() {
+		err = app.Execute()
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, out, "出力するタスクがありません")
+}
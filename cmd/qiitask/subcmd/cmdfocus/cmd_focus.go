@@ -0,0 +1,239 @@
+/*
+Package cmdfocus defines the "focus" command.
+*/
+package cmdfocus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/1set/todotxt"
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/timelog"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// MinutesDefault はポモドーロ 1 回分のデフォルトの時間（分）です。
+const MinutesDefault = 25
+
+// 作業後の質問の選択肢。
+const (
+	answerDone     = "完了した"
+	answerProgress = "まだ作業中"
+	answerSplit    = "分割する"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo  *appinfo.AppInfo
+	CUI      *cui.UI
+	minutes  int  // flag for "--minutes" option
+	isGlobal bool // flag for "--global" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "focus" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdFocus := new(Command)
+
+	// コマンドの割り当て
+	cmdFocus.Command = &cobra.Command{
+		Use:   "focus",
+		Short: "一番上のタスクにポモドーロで集中します",
+		Long: util.HereDoc(`
+				About:
+				  'focus' コマンドは、タスク一覧の一番上の未完了タスク（'sort' で一番
+				  優先度の高いタスク）に対して、ポモドーロ・タイマーを開始します。
+				  "dep:" タグでブロックされているタスクは飛ばされます。
+
+				  タイマーの終了時（もしくは ctrl+c での中断時）に作業時間が記録され、
+				  タスクの "spent:" タグに加算されます。その後、タスクが完了したか、
+				  まだ作業中か、子タスクに分割するかを選択します。
+			`),
+		Example: util.HereDoc(`
+				qiitask focus              // 25 分のタイマーを開始します
+				qiitask focus --minutes 50 // 50 分のタイマーを開始します
+				qiitask focus --global     // グローバル・タスクの一番上のタスクに集中します
+			`, "  "),
+		Args: cobra.NoArgs,
+	}
+
+	// Set app info (conf and tasks)
+	cmdFocus.AppInfo = appInfo
+
+	// Add CUI object
+	cmdFocus.CUI = cui.New()
+
+	// RunE function
+	cmdFocus.Command.RunE = cmdFocus.Focus
+
+	// Define flags for `focus` command.
+	cmdFocus.Flags().IntVarP(
+		&cmdFocus.minutes, "minutes", "m", MinutesDefault, "タイマーの時間（分）を指定します。（0 はデフォルト）",
+	)
+	cmdFocus.Flags().BoolVarP(
+		&cmdFocus.isGlobal, "global", "g", false, "グローバル・タスクを対象にします",
+	)
+
+	return cmdFocus.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Focus は "focus" コマンドの本体です。
+func (c *Command) Focus(cmd *cobra.Command, args []string) error {
+	if c.minutes < 0 {
+		return errors.Errorf("タイマーの時間は 1 分以上を指定してください: %v", c.minutes)
+	}
+
+	if c.minutes == 0 {
+		c.minutes = MinutesDefault
+	}
+
+	taskList := c.getTaskList()
+
+	task := topTask(taskList)
+	if task == nil {
+		return errors.New("集中できる未完了のタスクはありません")
+	}
+
+	if _, running, err := timelog.FindRunning(
+		c.AppInfo.Tasks.Local.PathSave(), c.AppInfo.Tasks.Global.PathSave(),
+	); err != nil {
+		return err
+	} else if running != nil {
+		return errors.Errorf("'start' で計測中のタスクがあります: %v（'stop' で終了してください）", running.Task)
+	}
+
+	log, err := timelog.Load(taskList.PathSave())
+	if err != nil {
+		return err
+	}
+
+	// タイマー
+	cmd.Println(fmt.Sprintf("集中するタスク: %v（%v 分）", task.Todo, c.minutes))
+
+	session, err := log.Start(task, taskList.PathSave())
+	if err != nil {
+		return err
+	}
+
+	elapsed := c.CUI.Countdown(task.Todo, time.Duration(c.minutes)*time.Minute)
+
+	timeEnd := session.Start.Add(elapsed)
+	session.End = &timeEnd
+
+	// 問い合わせの中断やタスク・ファイルの保存の失敗で作業時間が失われないよう、
+	// 先に記録を保存する
+	if err := log.Save(); err != nil {
+		return err
+	}
+
+	total, err := timelog.AddSpent(task, elapsed)
+	if err != nil {
+		return err
+	}
+
+	// 作業後の状態の確認
+	msgResult, err := c.askResult(taskList, task)
+	if err != nil {
+		return errors.Wrap(err, "作業時間は記録しましたが、タスクは更新していません")
+	}
+
+	if err := taskList.OverWrite(c.CUI); err != nil {
+		return errors.Wrap(err, "作業時間は記録しましたが、タスクは更新していません")
+	}
+
+	cmd.Println(fmt.Sprintf(
+		"作業時間を記録しました: %v（%v、合計 %v）\n%v",
+		session.Task, timelog.FormatSpent(elapsed), timelog.FormatSpent(total), msgResult,
+	))
+
+	return nil
+}
+
+// askResult はタスクの作業後の状態をユーザに問い合わせ、回答にあわせて task を
+// 更新します。戻り値は結果のメッセージです。
+func (c *Command) askResult(taskList *todo.Todo, task *todotxt.Task) (string, error) {
+	answer, err := c.CUI.Select(
+		fmt.Sprintf("タスクの状態を選択してください: %v", task.Todo),
+		[]string{answerDone, answerProgress, answerSplit},
+		answerProgress,
+		"「分割する」を選ぶと、タスクを子タスク（\"parent:\" タグ）に分割します。",
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "error during selection")
+	}
+
+	switch answer {
+	case answerDone:
+		task.Complete()
+
+		return "    タスクを完了にしました", nil
+	case answerSplit:
+		titles, err := c.CUI.InputLines(
+			"分割後のタスクを 1 行に 1 つずつ入力してください",
+			"入力したタスクは、優先度・プロジェクト・コンテキストを引き継いだ子タスクになります。",
+		)
+		if err != nil {
+			return "", errors.Wrap(err, "error during input")
+		}
+
+		children, err := taskList.Split(task, titles)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("    タスクを %d 件の子タスクに分割しました", len(children)), nil
+	default:
+		return "    タスクは作業中のままです", nil
+	}
+}
+
+func (c *Command) getTaskList() *todo.Todo {
+	taskList := c.AppInfo.Tasks.Local
+
+	if c.isGlobal || taskList.FileUsed() == "" {
+		taskList = c.AppInfo.Tasks.Global
+	}
+
+	return taskList
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// topTask は taskList の一番上の、未完了かつブロックされていないタスクを返しま
+// す。該当するタスクがない場合は nil を返します。
+func topTask(taskList *todo.Todo) *todotxt.Task {
+	for i := range *taskList.TaskList {
+		task := &(*taskList.TaskList)[i]
+
+		if !task.Completed && len(taskList.Blockers(task)) == 0 {
+			return task
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package cmdfocus_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdfocus"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/Qithub-BOT/QiiTask/core/timelog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------------------------------------
+//  Helper Functions
+// ----------------------------------------------------------------------------
+
+// prepareAppInfo はローカルに data のタスクを持つ AppInfo を返します。タイマーは
+// 待機せずに 5 分ずつ進みます。（デフォルトの 25 分で終了します）
+func prepareAppInfo(t *testing.T, data string) *appinfo.AppInfo {
+	t.Helper()
+
+	appInfo := testutil.NewAppInfo(t, data)
+
+	// Mock the clock of the timer
+	oldTimeAfter := cui.TimeAfter
+
+	t.Cleanup(func() {
+		cui.TimeAfter = oldTimeAfter
+	})
+
+	current := testutil.Now
+
+	testutil.MockTimeNowFunc(t, func() time.Time {
+		result := current
+		current = current.Add(5 * time.Minute)
+
+		return result
+	})
+
+	cui.TimeAfter = func(d time.Duration) <-chan time.Time {
+		ch := make(chan time.Time, 1)
+		ch <- current
+
+		return ch
+	}
+
+	return appInfo
+}
+
+// focus は appInfo の "focus" コマンドを実行し、出力とタスク・ファイルの内容を
+// 返します。
+func focus(t *testing.T, appInfo *appinfo.AppInfo) (string, string) {
+	t.Helper()
+
+	out, err := testutil.Execute(cmdfocus.New(appInfo))
+	require.NoError(t, err)
+
+	return out, testutil.ReadFile(t, appInfo.Tasks.Local.FileUsed())
+}
+
+// ----------------------------------------------------------------------------
+//  Tests
+// ----------------------------------------------------------------------------
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj := cmdfocus.New(appInfo)
+
+	assert.Equal(t, "focus", obj.Name())
+}
+
+func TestFocus_done(t *testing.T) {
+	appInfo := prepareAppInfo(t, "x finished\nblocked task dep:a\nfirst task id:a\nsecond task\n")
+
+	testutil.MockAnswers(t, "完了した")
+
+	out, data := focus(t, appInfo)
+
+	assert.Contains(t, out, "集中するタスク: first task（25 分）")
+	assert.Contains(t, out, "作業時間を記録しました: first task（25m、合計 25m）")
+	assert.Contains(t, out, "タスクを完了にしました")
+	assert.Contains(t, data, "x "+time.Now().Format("2006-01-02")+" first task id:a spent:25m touched:2021-01-06\n")
+
+	log, err := timelog.Load(appInfo.Tasks.Local.FileUsed())
+	require.NoError(t, err)
+	require.Len(t, log.Sessions, 1)
+	assert.Nil(t, log.Running(), "the session should be finished")
+	assert.Equal(t, 25*time.Minute, log.Sessions[0].Duration())
+}
+
+func TestFocus_in_progress(t *testing.T) {
+	appInfo := prepareAppInfo(t, "first task spent:1h\nsecond task\n")
+
+	testutil.MockAnswers(t, "まだ作業中")
+
+	out, data := focus(t, appInfo)
+
+	assert.Contains(t, out, "タスクは作業中のままです")
+	assert.Equal(t, "first task spent:1h25m touched:2021-01-06\nsecond task\n", data)
+}
+
+func TestFocus_split(t *testing.T) {
+	appInfo := prepareAppInfo(t, "first task +proj\n")
+
+	testutil.MockAnswers(t, "分割する", "step 1\nstep 2")
+
+	out, data := focus(t, appInfo)
+
+	assert.Contains(t, out, "タスクを 2 件の子タスクに分割しました")
+	assert.Equal(t, "first task +proj id:t1 spent:25m touched:2021-01-06\nstep 1 +proj parent:t1 touched:2021-01-06\nstep 2 +proj parent:t1 touched:2021-01-06\n", data)
+}
+
+func TestFocus_canceled(t *testing.T) {
+	appInfo := prepareAppInfo(t, "first task\n")
+
+	testutil.MockAskOne(t, func(p survey.Prompt) (interface{}, error) {
+		return nil, errors.New("interrupted")
+	})
+
+	_, err := testutil.Execute(cmdfocus.New(appInfo))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "作業時間は記録しましたが")
+
+	log, err := timelog.Load(appInfo.Tasks.Local.FileUsed())
+	require.NoError(t, err)
+	require.Len(t, log.Sessions, 1, "the session should be saved even if the question is canceled")
+	assert.Nil(t, log.Running())
+
+	assert.Equal(t, "first task\n", testutil.ReadFile(t, appInfo.Tasks.Local.FileUsed()),
+		"the task file should not be changed")
+}
+
+func TestFocus_no_task(t *testing.T) {
+	appInfo := prepareAppInfo(t, "x finished\n")
+
+	_, err := testutil.Execute(cmdfocus.New(appInfo))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "集中できる未完了のタスクはありません")
+}
+
+func TestFocus_bad_minutes(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	_, err = testutil.Execute(cmdroot.New(appInfo), "focus", "--minutes", "-5")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "タイマーの時間は 1 分以上")
+}
+
+func TestFocus_timer_running(t *testing.T) {
+	appInfo := prepareAppInfo(t, "task\n")
+
+	log, err := timelog.Load(appInfo.Tasks.Local.FileUsed())
+	require.NoError(t, err)
+
+	task, err := appInfo.Tasks.Local.GetTask(1)
+	require.NoError(t, err)
+
+	_, err = log.Start(task, appInfo.Tasks.Local.FileUsed())
+	require.NoError(t, err)
+	require.NoError(t, log.Save())
+
+	_, err = testutil.Execute(cmdfocus.New(appInfo))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'start' で計測中のタスクがあります")
+}
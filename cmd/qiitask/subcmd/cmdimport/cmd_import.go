@@ -0,0 +1,302 @@
+/*
+Package cmdimport defines the "import" command.
+*/
+package cmdimport
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/1set/todotxt"
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/importer"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo  *appinfo.AppInfo
+	CUI      *cui.UI
+	format   string // flag for "--format" option
+	isDryRun bool   // flag for "--dry-run" option
+	isGlobal bool   // flag for "--global" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "import" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdImport := new(Command)
+
+	// コマンドの割り当て
+	cmdImport.Command = &cobra.Command{
+		Use:   "import FILE",
+		Short: "外部のファイルからタスクを取り込みます",
+		Long: util.HereDoc(`
+				About:
+				  'import' コマンドは、Markdown のチェックリストや CSV、テキストのファ
+				  イルからタスクを取り込み、タスク一覧の最後に追加します。
+
+				  形式（--format）:
+				    md    "- [ ] item" と "- [x] item" の行（チェック済みは完了タスク）
+				    csv   ヘッダー行で列を対応付け（todo/task/title, priority, project,
+				          context, due, created, done）
+				    lines 1 行 1 タスク（todo.txt 形式として解釈）
+				    ics   iCalendar の VTODO（SUMMARY, DUE, PRIORITY, STATUS,
+				          CATEGORIES）
+				    taskwarrior
+				          Taskwarrior の 'task export' の JSON（description, due,
+				          priority, status, project, tags）
+
+				  形式を指定しない場合は、拡張子（.md, .csv, .ics, .json）から判断し、
+				  それ以外は lines として扱います。
+
+				  取り込み先のタスクや、取り込むファイル内に同じ本文のタスクがある場
+				  合は、重複としてスキップします。（全角・半角、ひらがな・カタカナ、
+				  大文字・小文字の違いは無視します）
+
+				  ics と taskwarrior で取り込んだタスクには、出典を表す 'src:' タグ
+				  （UID もしくは uuid）が付きます。同じファイルを再度取り込むと、
+				  'src:' が一致するタスクは追加ではなく更新されます。その際、取り込み
+				  元にない 'id:' や 'est:' などのタグは残ります。
+
+				  '--dry-run' を指定すると、取り込まれるタスクを表示するだけで、タス
+				  ク・ファイルは変更しません。
+			`),
+		Example: util.HereDoc(`
+				qiitask import ./README.md
+				qiitask import ./tasks.csv --dry-run
+				qiitask import ./memo.txt --format lines --global
+				qiitask import ./calendar.ics
+				qiitask import ./export.json --format taskwarrior
+			`, "  "),
+		Args: cobra.ExactArgs(1),
+	}
+
+	// Set app info (conf and tasks)
+	cmdImport.AppInfo = appInfo
+
+	// Add CUI object
+	cmdImport.CUI = cui.New()
+
+	// RunE function
+	cmdImport.Command.RunE = cmdImport.Import
+
+	// Define flags for `import` command.
+	cmdImport.Flags().StringVarP(
+		&cmdImport.format, "format", "f", "",
+		"ファイルの形式（"+strings.Join(importer.Formats(), ", ")+"）を指定します",
+	)
+	cmdImport.Flags().BoolVarP(
+		&cmdImport.isDryRun, "dry-run", "n", false, "取り込まれるタスクを表示するだけで保存しません",
+	)
+	cmdImport.Flags().BoolVarP(
+		&cmdImport.isGlobal, "global", "g", false, "グローバル・タスクに取り込みます",
+	)
+
+	return cmdImport.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Import は "import" コマンドの本体です。
+func (c *Command) Import(cmd *cobra.Command, args []string) error {
+	pathFile := args[0]
+
+	data, err := os.ReadFile(pathFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to read file to import")
+	}
+
+	format := c.format
+	if format == "" {
+		format = importer.DetectFormat(pathFile)
+	}
+
+	tasks, err := importer.Parse(format, data)
+	if err != nil {
+		return err
+	}
+
+	if len(tasks) == 0 {
+		return errors.Errorf("取り込めるタスクがありません（形式: %v）: %v", format, pathFile)
+	}
+
+	taskList := c.getTaskList()
+
+	added, updated, skipped := dedupe(taskList, tasks)
+
+	tableTmp := table.NewWriter()
+	tableTmp.AppendHeader(table.Row{"status", "task"})
+
+	for _, task := range added {
+		tableTmp.AppendRow(table.Row{"add", task.String()})
+	}
+
+	for _, task := range updated {
+		tableTmp.AppendRow(table.Row{"update", task.String()})
+	}
+
+	for _, task := range skipped {
+		tableTmp.AppendRow(table.Row{"skip", task.String()})
+	}
+
+	ui := cui.New()
+	ui.MirrorIO = cmd.OutOrStdout()
+
+	ui.DrawTable(tableTmp, cui.AsDefaultTable)
+
+	if c.isDryRun {
+		cmd.Println(fmt.Sprintf(
+			"%d 件のタスクが取り込まれます（更新 %d 件、重複 %d 件をスキップ）。'--dry-run' のため保存していません",
+			len(added), len(updated), len(skipped),
+		))
+
+		return nil
+	}
+
+	if len(added) == 0 && len(updated) == 0 {
+		cmd.Println(fmt.Sprintf("すべてのタスクが重複しているため、取り込みませんでした（%d 件）", len(skipped)))
+
+		return nil
+	}
+
+	for i := range updated {
+		task, err := taskList.GetTask(updated[i].ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get task to update")
+		}
+
+		*task = updated[i]
+	}
+
+	for i := range added {
+		taskList.AddTask(&added[i])
+	}
+
+	if err := taskList.OverWrite(c.CUI); err != nil {
+		return err
+	}
+
+	cmd.Println(fmt.Sprintf(
+		"%d 件のタスクを取り込みました（更新 %d 件、重複 %d 件をスキップ）\n    %v",
+		len(added), len(updated), len(skipped), taskList.PathSave(),
+	))
+
+	return nil
+}
+
+func (c *Command) getTaskList() *todo.Todo {
+	taskList := c.AppInfo.Tasks.Local
+
+	if c.isGlobal || taskList.FileUsed() == "" {
+		taskList = c.AppInfo.Tasks.Global
+	}
+
+	return taskList
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// dedupe は tasks を、追加するタスク・更新するタスク・重複するタスクに分けて返
+// します。
+//
+// 出典のタグ（importer.TagSource）が taskList のタスクと一致するタスクは、内容
+// が異なる場合に更新するタスクになります。更新するタスクは既存のタスクの ID を持
+// ち、取り込むタスクにないタグは既存のタスクから引き継ぎます。それ以外は、
+// taskList もしくは tasks 内の前のタスクと本文が重複するかで分けられます。
+func dedupe(taskList *todo.Todo, tasks []todotxt.Task) (added, updated, skipped []todotxt.Task) {
+	seen := map[string]bool{}
+	sources := map[string]*todotxt.Task{}
+
+	if taskList.TaskList != nil {
+		for i := range *taskList.TaskList {
+			task := &(*taskList.TaskList)[i]
+
+			seen[todo.NormalizeText(task.Todo)] = true
+
+			if source := task.AdditionalTags[importer.TagSource]; source != "" {
+				sources[source] = task
+			}
+		}
+	}
+
+	seenSources := map[string]bool{}
+
+	for _, task := range tasks {
+		source := task.AdditionalTags[importer.TagSource]
+
+		if source != "" {
+			if seenSources[source] {
+				skipped = append(skipped, task)
+
+				continue
+			}
+
+			seenSources[source] = true
+
+			if current, ok := sources[source]; ok {
+				if merged := mergeTask(current, task); merged.String() != current.String() {
+					updated = append(updated, merged)
+				} else {
+					skipped = append(skipped, task)
+				}
+
+				continue
+			}
+		}
+
+		key := todo.NormalizeText(task.Todo)
+
+		if seen[key] {
+			skipped = append(skipped, task)
+
+			continue
+		}
+
+		seen[key] = true
+
+		added = append(added, task)
+	}
+
+	return added, updated, skipped
+}
+
+// mergeTask は current を imported の内容で置き換えたタスクを返します。ID と、
+// imported にないタグは current のものを引き継ぎます。
+func mergeTask(current *todotxt.Task, imported todotxt.Task) todotxt.Task {
+	imported.ID = current.ID
+
+	tags := map[string]string{}
+
+	for key, value := range current.AdditionalTags {
+		tags[key] = value
+	}
+
+	for key, value := range imported.AdditionalTags {
+		tags[key] = value
+	}
+
+	imported.AdditionalTags = tags
+
+	return imported
+}
@@ -0,0 +1,179 @@
+package cmdimport_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdimport"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	dataTasks    = "レビューする\nother\n"
+	dataMarkdown = "# Checklist\n\n- [ ] ﾚﾋﾞｭｰする\n- [ ] write docs +docs\n- [x] deploy\n- [ ] Write Docs\n"
+)
+
+// runImport はローカルに dataTasks のタスクを持つアプリで "import" コマンドを実
+// 行し、出力とローカル・グローバルのタスク・ファイルの内容、エラーを返します。
+func runImport(t *testing.T, args ...string) (string, string, string, error) {
+	t.Helper()
+
+	return runImportWith(t, dataTasks, args...)
+}
+
+// runImportWith はローカルに data のタスクを持つアプリで "import" コマンドを実
+// 行します。戻り値は runImport と同じです。
+func runImportWith(t *testing.T, data string, args ...string) (string, string, string, error) {
+	t.Helper()
+
+	testutil.MockTimeNow(t, testutil.Now)
+
+	pathDirLocal := t.TempDir()
+	pathDirHome := t.TempDir()
+	pathFileLocal := filepath.Join(pathDirLocal, todo.NameFile)
+	pathFileGlobal := filepath.Join(pathDirHome, todo.NameFile)
+
+	require.NoError(t, os.WriteFile(pathFileLocal, []byte(data), 0o600))
+	require.NoError(t, os.WriteFile(pathFileGlobal, []byte{}, 0o600))
+
+	appInfo, err := appinfo.New(pathDirLocal, pathDirHome, "")
+	require.NoError(t, err)
+
+	out, errRun := testutil.Execute(cmdroot.New(appInfo), append([]string{"import"}, args...)...)
+
+	return out, testutil.ReadFile(t, pathFileLocal), testutil.ReadFile(t, pathFileGlobal), errRun
+}
+
+// writeFile は一時ディレクトリに name のファイルを data の内容で作成し、そのパ
+// スを返します。
+func writeFile(t *testing.T, name string, data string) string {
+	t.Helper()
+
+	pathFile := filepath.Join(t.TempDir(), name)
+
+	require.NoError(t, os.WriteFile(pathFile, []byte(data), 0o600))
+
+	return pathFile
+}
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj1 := cmdimport.New(appInfo)
+	obj2 := cmdimport.New(appInfo)
+
+	assert.NotSame(t, obj1, obj2, "it should not reference the same object")
+	assert.Equal(t, "import", obj1.Name())
+}
+
+func TestImport_markdown(t *testing.T) {
+	pathFile := writeFile(t, "README.md", dataMarkdown)
+
+	out, dataLocal, dataGlobal, err := runImport(t, pathFile)
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "2 件のタスクを取り込みました（更新 0 件、重複 2 件をスキップ）")
+	assert.Equal(t, dataTasks+"write docs +docs touched:2021-01-06\nx deploy touched:2021-01-06\n", dataLocal)
+	assert.Empty(t, dataGlobal)
+}
+
+func TestImport_dry_run(t *testing.T) {
+	pathFile := writeFile(t, "README.md", dataMarkdown)
+
+	out, dataLocal, _, err := runImport(t, pathFile, "--dry-run")
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "write docs +docs")
+	assert.Contains(t, out, "2 件のタスクが取り込まれます（更新 0 件、重複 2 件をスキップ）")
+	assert.Equal(t, dataTasks, dataLocal, "it should not change the task file")
+}
+
+func TestImport_global_with_format(t *testing.T) {
+	pathFile := writeFile(t, "tasks.txt", "task,priority\nfirst,A\n")
+
+	_, dataLocal, dataGlobal, err := runImport(t, pathFile, "--format", "csv", "--global")
+
+	require.NoError(t, err)
+	assert.Equal(t, dataTasks, dataLocal)
+	assert.Equal(t, "(A) first touched:2021-01-06\n", dataGlobal)
+}
+
+func TestImport_all_duplicated(t *testing.T) {
+	pathFile := writeFile(t, "memo.txt", "OTHER\n")
+
+	out, dataLocal, _, err := runImport(t, pathFile)
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "すべてのタスクが重複しているため、取り込みませんでした（1 件）")
+	assert.Equal(t, dataTasks, dataLocal)
+}
+
+func TestImport_reimport_updates_tasks(t *testing.T) {
+	dataLocal := "(B) write docs src:tw:a1 id:d1\n" +
+		"review src:tw:b2\n" +
+		"x local only\n"
+	dataExport := `[
+{"description":"write docs","priority":"H","due":"20210201T120000Z","status":"pending","uuid":"a1"},
+{"description":"review","status":"pending","uuid":"b2"},
+{"description":"review","status":"pending","uuid":"b2"},
+{"description":"deploy","status":"pending","uuid":"c3"}
+]`
+	pathFile := writeFile(t, "export.json", dataExport)
+
+	out, dataLocal, _, err := runImportWith(t, dataLocal, pathFile)
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "1 件のタスクを取り込みました（更新 1 件、重複 2 件をスキップ）")
+	assert.Equal(t,
+		"(A) write docs id:d1 src:tw:a1 touched:2021-01-06 due:2021-02-01\n"+
+			"review src:tw:b2\n"+
+			"x local only\n"+
+			"deploy src:tw:c3 touched:2021-01-06\n",
+		dataLocal,
+		"it should update the task with the same src: tag and keep its other tags",
+	)
+
+	// 同じファイルを再度取り込んでも変わらない
+	out, dataAgain, _, err := runImportWith(t, dataLocal, pathFile)
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "すべてのタスクが重複しているため、取り込みませんでした（4 件）")
+	assert.Equal(t, dataLocal, dataAgain)
+}
+
+func TestImport_ics_dry_run(t *testing.T) {
+	pathFile := writeFile(t, "calendar.ics",
+		"BEGIN:VTODO\r\nUID:u1\r\nSUMMARY:renamed\r\nEND:VTODO\r\n")
+
+	out, dataLocal, _, err := runImportWith(t, "old name src:ics:u1\n", pathFile, "--dry-run")
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "update")
+	assert.Contains(t, out, "renamed src:ics:u1")
+	assert.Contains(t, out, "0 件のタスクが取り込まれます（更新 1 件、重複 0 件をスキップ）")
+	assert.Equal(t, "old name src:ics:u1\n", dataLocal)
+}
+
+func TestImport_errors(t *testing.T) {
+	for _, test := range []struct {
+		args     []string
+		msgError string
+	}{
+		{[]string{filepath.Join(t.TempDir(), "missing.md")}, "failed to read file to import"},
+		{[]string{writeFile(t, "empty.md", "no checklist\n")}, "取り込めるタスクがありません（形式: md）"},
+		{[]string{writeFile(t, "memo.txt", "task\n"), "--format", "xml"}, "未対応の形式です: xml"},
+	} {
+		out, dataLocal, _, err := runImport(t, test.args...)
+
+		require.Error(t, err, "args: %v", test.args)
+		assert.Contains(t, out, test.msgError)
+		assert.Equal(t, dataTasks, dataLocal)
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/Qithub-BOT/QiiTask/core/appinfo"
 	"github.com/Qithub-BOT/QiiTask/core/config"
 	"github.com/Qithub-BOT/QiiTask/core/cui"
-	"github.com/Qithub-BOT/QiiTask/core/todo"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -100,7 +99,7 @@ func (c *Command) Init(cmd *cobra.Command, args []string) error {
 
 	/* ファイルのパス取得 */
 	pathFileConf := filepath.Join(pathDirConf, config.NameConf)
-	pathFileTask := filepath.Join(pathDirConf, todo.NameFile)
+	pathFileTask := filepath.Join(pathDirConf, c.AppInfo.Tasks.Global.File())
 
 	// 設定ファイルの確認
 	if util.IsFile(pathFileConf) {
@@ -141,13 +140,13 @@ func (c *Command) ForceInit(cmd *cobra.Command, pathDirConf string) error {
 
 	/* ファイルのパス取得 */
 	pathFileConf := filepath.Join(pathDirConf, config.NameConf)
-	pathFileTask := filepath.Join(pathDirConf, todo.NameFile)
+	pathFileTask := filepath.Join(pathDirConf, c.AppInfo.Tasks.Global.File())
 
 	if err := c.AppInfo.Config.SaveAs(pathFileConf); err != nil {
 		return errors.Wrap(err, "failed to save config file")
 	}
 
-	if err := c.AppInfo.Tasks.Global.WriteToPath(pathFileTask); err != nil {
+	if err := c.AppInfo.Tasks.Global.SaveAs(pathFileTask); err != nil {
 		return errors.Wrap(err, "failed to save task file")
 	}
 
@@ -0,0 +1,208 @@
+/*
+Package cmdlint defines the "lint" command.
+*/
+package cmdlint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/journal"
+	"github.com/Qithub-BOT/QiiTask/core/lint"
+	"github.com/Qithub-BOT/QiiTask/core/safefile"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo  *appinfo.AppInfo
+	CUI      *cui.UI
+	isFix    bool // flag for "--fix" option
+	isStrict bool // flag for "--strict" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "lint" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdLint := new(Command)
+
+	// コマンドの割り当て
+	cmdLint.Command = &cobra.Command{
+		Use:   "lint [FILE...]",
+		Short: "タスク・ファイルの書式を検査します",
+		Long: util.HereDoc(`
+				About:
+				  'lint' コマンドは、タスク・ファイルの書式を検査し、問題のある箇所を
+				  "ファイル:行番号" の形式で表示します。ファイルを指定しない場合は、
+				  ローカルとグローバルのタスク・ファイルを検査します。
+
+				  検査する項目:
+				    scanner    行が長すぎて読み込めない（改行の抜けなど）
+				    date       日付の書式が YYYY-MM-DD でない
+				    priority   優先度が小文字、もしくは日付の後にある
+				    date-order 完了日が作成日より前
+				    duplicate  同じ内容のタスクがある
+				    tag-format key:value の書式やタグの値が不正
+				    tag-name   QiiTask で使われていないタグ（警告）
+
+				  '--fix' を指定すると、優先度・日付の順序・重複を自動で修正します。
+				  エラーが残っている場合（'--strict' では警告も）、終了ステータスは 0
+				  以外になるため、CI での検査にも使えます。
+
+				  lint はファイルを行単位で読み込むため、他のコマンドで読み込めない
+				  タスク・ファイルも検査できます。
+			`),
+		Annotations: map[string]string{
+			// 読み込めないタスク・ファイルを検査するため
+			appinfo.AnnotationAllowLoadErr: "true",
+		},
+		Example: util.HereDoc(`
+				qiitask lint
+				qiitask lint --fix
+				qiitask lint --strict ./todo.txt ./done.txt
+			`, "  "),
+	}
+
+	// Set app info (conf and tasks)
+	cmdLint.AppInfo = appInfo
+
+	// Add CUI object
+	cmdLint.CUI = cui.New()
+
+	// RunE function
+	cmdLint.Command.RunE = cmdLint.Lint
+
+	// Define flags for `lint` command.
+	cmdLint.Flags().BoolVar(
+		&cmdLint.isFix, "fix", false, "自動で修正できる問題を修正します",
+	)
+	cmdLint.Flags().BoolVar(
+		&cmdLint.isStrict, "strict", false, "警告もエラーとして扱います",
+	)
+
+	return cmdLint.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Lint は "lint" コマンドの本体です。
+func (c *Command) Lint(cmd *cobra.Command, args []string) error {
+	pathFiles := args
+	if len(pathFiles) == 0 {
+		pathFiles = c.defaultFiles()
+	}
+
+	if len(pathFiles) == 0 {
+		return errors.New("検査するタスク・ファイルがありません")
+	}
+
+	countErr, countWarn := 0, 0
+
+	for _, pathFile := range pathFiles {
+		diagnostics, err := c.lintFile(cmd, pathFile)
+		if err != nil {
+			return err
+		}
+
+		for _, diagnostic := range diagnostics {
+			cmd.Println(diagnostic.String())
+		}
+
+		errs := lint.CountErrors(diagnostics)
+		countErr += errs
+		countWarn += len(diagnostics) - errs
+	}
+
+	if countErr == 0 && countWarn == 0 {
+		cmd.Println(fmt.Sprintf("問題は見つかりませんでした（%d ファイル）", len(pathFiles)))
+
+		return nil
+	}
+
+	msg := fmt.Sprintf("%d 件のエラーと %d 件の警告があります", countErr, countWarn)
+
+	if countErr > 0 || (c.isStrict && countWarn > 0) {
+		return errors.New(msg)
+	}
+
+	cmd.Println(msg)
+
+	return nil
+}
+
+// lintFile は pathFile を検査し、残っている問題の診断を返します。"--fix" が指定
+// されている場合は、修正してから検査した結果を返します。
+func (c *Command) lintFile(cmd *cobra.Command, pathFile string) ([]lint.Diagnostic, error) {
+	data, err := os.ReadFile(pathFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read file: %v", pathFile)
+	}
+
+	if !c.isFix {
+		return lint.Check(pathFile, data), nil
+	}
+
+	after, fixed := lint.Fix(pathFile, data)
+	if len(fixed) > 0 {
+		if err := safefile.WriteFile(pathFile, after, c.AppInfo.Config.GetInt("backup_count")); err != nil {
+			return nil, errors.Wrap(err, "failed to write fixed file")
+		}
+
+		if err := journal.Record(pathFile, data, after); err != nil {
+			return nil, errors.Wrap(err, "failed to record journal")
+		}
+
+		for _, diagnostic := range fixed {
+			cmd.Println(fmt.Sprintf("修正しました: %v", diagnostic))
+		}
+	}
+
+	return lint.Check(pathFile, after), nil
+}
+
+// defaultFiles は検査対象となる、既存のローカルおよびグローバルのタスク・ファイ
+// ルのパスを返します。
+func (c *Command) defaultFiles() []string {
+	result := []string{}
+	seen := map[string]bool{}
+
+	for _, pathFile := range []string{
+		c.AppInfo.Tasks.Local.FileUsed(),
+		c.AppInfo.Tasks.Global.FileUsed(),
+	} {
+		if pathFile == "" {
+			continue
+		}
+
+		if abs, err := filepath.Abs(pathFile); err == nil {
+			pathFile = abs
+		}
+
+		if seen[pathFile] {
+			continue
+		}
+
+		seen[pathFile] = true
+
+		result = append(result, pathFile)
+	}
+
+	return result
+}
@@ -0,0 +1,108 @@
+package cmdlint_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdlint"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/kami-zh/go-capturer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dataTasks = "(a) lower\ntask foo:bar\n(A) lower\n"
+
+// runLint は dataTasks をローカルのタスクに持つアプリで "lint" コマンドを実行し、
+// 出力とローカルのタスク・ファイルのパス、エラーを返します。
+func runLint(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+
+	pathDirLocal := t.TempDir()
+	pathFile := filepath.Join(pathDirLocal, todo.NameFile)
+
+	require.NoError(t, os.WriteFile(pathFile, []byte(dataTasks), 0o600))
+
+	appInfo, err := appinfo.New(pathDirLocal, t.TempDir(), "")
+	require.NoError(t, err)
+
+	app := cmdroot.New(appInfo)
+	app.SetArgs(append([]string{"lint"}, args...))
+
+	var errRun error
+
+	out := capturer.CaptureOutput(func() {
+		errRun = app.Execute()
+	})
+
+	return out, pathFile, errRun
+}
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj1 := cmdlint.New(appInfo)
+	obj2 := cmdlint.New(appInfo)
+
+	assert.NotSame(t, obj1, obj2, "it should not reference the same object")
+	assert.Equal(t, "lint", obj1.Name())
+}
+
+func TestLint(t *testing.T) {
+	out, pathFile, err := runLint(t)
+
+	require.Error(t, err, "errors in the file should fail the command")
+	assert.Contains(t, err.Error(), "1 件のエラーと 2 件の警告があります")
+	assert.Contains(t, out, pathFile+":1: error: 優先度は大文字で指定してください: (a) (priority)")
+	assert.Contains(t, out, pathFile+":2: warning:")
+	assert.Contains(t, out, pathFile+":3: warning: 1 行目と同じタスクです (duplicate)")
+
+	data, err := os.ReadFile(pathFile)
+	require.NoError(t, err)
+	assert.Equal(t, dataTasks, string(data), "it should not change the file without --fix")
+}
+
+func TestLint_fix(t *testing.T) {
+	out, pathFile, err := runLint(t, "--fix")
+
+	require.NoError(t, err, "only warnings should remain after fixing")
+	assert.Contains(t, out, "修正しました: "+pathFile+":1: error:")
+	assert.Contains(t, out, "0 件のエラーと 1 件の警告があります")
+
+	data, err := os.ReadFile(pathFile)
+	require.NoError(t, err)
+	assert.Equal(t, "(A) lower\ntask foo:bar\n", string(data))
+}
+
+func TestLint_strict(t *testing.T) {
+	_, _, err := runLint(t, "--fix", "--strict")
+
+	require.Error(t, err, "warnings should fail the command with --strict")
+	assert.Contains(t, err.Error(), "0 件のエラーと 1 件の警告があります")
+}
+
+func TestLint_args(t *testing.T) {
+	pathFileOK := filepath.Join(t.TempDir(), "ok.txt")
+	require.NoError(t, os.WriteFile(pathFileOK, []byte("(A) task\n"), 0o600))
+
+	out, _, err := runLint(t, pathFileOK)
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "問題は見つかりませんでした（1 ファイル）")
+
+	pathFileScanner := filepath.Join("..", "..", "..", "..", "testdata", "error", "scanner_error", "todo.txt")
+
+	out, _, err = runLint(t, pathFileScanner)
+
+	require.Error(t, err)
+	assert.Contains(t, out, pathFileScanner+":1: error: 行が長すぎるため読み込めません")
+
+	_, _, err = runLint(t, filepath.Join(t.TempDir(), "missing.txt"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read file")
+}
@@ -4,18 +4,49 @@ Package cmdlist defines the "list" command.
 package cmdlist
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/1set/todotxt"
 	"github.com/KEINOS/go-utiles/util"
 	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/clock"
 	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/estimate"
+	"github.com/Qithub-BOT/QiiTask/core/timelog"
 	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/Qithub-BOT/QiiTask/core/watcher"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// ansiClear はカーソルを左上に移動して画面を消去する ANSI エスケープ・シーケン
+// スです。
+const ansiClear = "\x1b[H\x1b[2J"
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// WatchContext は "--watch" オプションで監視を終了するまでのコンテキストを返しま
+// す。デフォルトは ctrl+c （SIGINT）で終了します。テスト時に監視を終了させる為
+// に変数に代入しています。
+var WatchContext = func() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
 // ----------------------------------------------------------------------------
 //  Commnad Struct
 // ----------------------------------------------------------------------------
@@ -25,9 +56,16 @@ type Command struct {
 	*cobra.Command
 	AppInfo    *appinfo.AppInfo
 	styleTable string // flag for "--style" option
+	stale      string // flag for "--stale" option
 	addNoEdit  bool   // flag for "--add-no-edit" option
+	isEstimate bool   // flag for "--estimate" option
 	isGlobal   bool   // flag for "--global" option
+	isMerged   bool   // flag for "--merged" option
+	isSpent    bool   // flag for "--spent" option
+	isTree     bool   // flag for "--tree" option
+	isWatch    bool   // flag for "--watch" option
 	showAll    bool   // flag for "--all" option
+	staleDays  int    // "--stale" で指定された日数
 }
 
 // ----------------------------------------------------------------------------
@@ -47,10 +85,41 @@ func New(appInfo *appinfo.AppInfo) *cobra.Command {
 				About:
 				  'list' は現在のタスク一覧を表示します。デフォルトで未完成のタスク
 				  のみが表示されます。
+
+				  '--merged' を指定すると、ローカルとグローバルのタスクを 1 つの表に
+				  まとめて表示します。ID はローカルが "L3"、グローバルが "G7" のよう
+				  に表示され、他のコマンドでもこの書式で ID を指定できます。
+
+				  '--tree' を指定すると、"id:" と "parent:" タグの親子関係にあるタス
+				  クを、インデントした木構造で表示します。
+
+				  "dep:" タグの依存先に未完了のタスクがあるタスクは、"[blocked by id]"
+				  付きで表示されます。
+
+				  '--spent' を指定すると、'start' と 'stop' コマンドで記録した作業時
+				  間（"spent:" タグ）の列を表示します。
+
+				  '--estimate' を指定すると、'estimate' コマンドで記録した見積り
+				  （"est:" タグ）の列と、プロジェクトごとの見積りの合計を表示します。
+
+				  '--stale' を指定すると、最後に変更してから指定の期間以上経った（なが
+				  らく手を付けていない）タスクのみを、経過日数の列付きで表示します。
+				  期間は "30d"（日）や "4w"（週）の形式で指定します。最後に変更した日
+				  は "touched:" タグの日付で、ない場合は作成日です。
+
+				  '--watch' を指定すると、タスク・ファイルと設定ファイルを監視し、変
+				  更されるたびに読み込み直して一覧を表示し直します。ctrl+c で終了し
+				  ます。（OS のファイル監視が使えない場合は、1 秒ごとに確認します）
 			`),
 		Example: util.HereDoc(`
 				qiitask list
 				qiitask list --all
+				qiitask list --merged
+				qiitask list --tree
+				qiitask list --spent
+				qiitask list --estimate
+				qiitask list --stale 30d
+				qiitask list --watch --merged
 			`, "  "),
 	}
 
@@ -64,12 +133,30 @@ func New(appInfo *appinfo.AppInfo) *cobra.Command {
 	cmdList.Flags().StringVarP(
 		&cmdList.styleTable, "style", "s", "text", "テーブルの表示スタイルを指定します。(text, color, markdown, html, csv)",
 	)
+	cmdList.Flags().StringVar(
+		&cmdList.stale, "stale", "", "指定の期間（例: 30d, 4w）以上手を付けていないタスクのみを表示します",
+	)
 	cmdList.Flags().BoolVar(
 		&cmdList.addNoEdit, "add-no-edit", false, "出力時に DO-NOT-EDIT を追加します。（自動生成された旨を加えます）",
 	)
+	cmdList.Flags().BoolVarP(
+		&cmdList.isEstimate, "estimate", "e", false, "見積り（\"est:\" タグ）の列と、プロジェクトごとの合計を表示します",
+	)
 	cmdList.Flags().BoolVarP(
 		&cmdList.isGlobal, "global", "g", false, "グローバル・タスクを表示します",
 	)
+	cmdList.Flags().BoolVarP(
+		&cmdList.isMerged, "merged", "m", false, "ローカルとグローバルのタスクをまとめて表示します",
+	)
+	cmdList.Flags().BoolVar(
+		&cmdList.isSpent, "spent", false, "作業時間（\"spent:\" タグ）の列を表示します",
+	)
+	cmdList.Flags().BoolVarP(
+		&cmdList.isTree, "tree", "t", false, "タスクの親子関係を木構造で表示します",
+	)
+	cmdList.Flags().BoolVarP(
+		&cmdList.isWatch, "watch", "w", false, "ファイルの変更を監視し、変更のたびに表示し直します",
+	)
 	cmdList.Flags().BoolVarP(
 		&cmdList.showAll, "all", "a", false, "完了済みのタスクも表示します",
 	)
@@ -91,7 +178,65 @@ func (c *Command) getTaskList() *todo.Todo {
 	return taskList
 }
 
-func (c *Command) drawTable(mirror io.Writer, taskList *todo.Todo) {
+// countTask は一覧の対象となるタスク・ファイルのタスク数を返します。"--merged"
+// が指定されている場合はローカルとグローバルの合計です。
+func (c *Command) countTask() int {
+	if !c.isMerged {
+		return c.getTaskList().Len()
+	}
+
+	tasks := c.AppInfo.Tasks
+	if tasks.IsShared() {
+		return tasks.Global.Len()
+	}
+
+	return tasks.Local.Len() + tasks.Global.Len()
+}
+
+// appendColumns はオプションで指定された task の列を row に追加して返します。
+func (c *Command) appendColumns(row table.Row, task todotxt.Task) table.Row {
+	if c.isSpent {
+		row = append(row, task.AdditionalTags[timelog.TagSpent])
+	}
+
+	if c.isEstimate {
+		row = append(row, task.AdditionalTags[estimate.TagEstimate])
+	}
+
+	if c.staleDays > 0 {
+		row = append(row, todo.FormatAge(&task, clock.TimeNow()))
+	}
+
+	return row
+}
+
+// drawEstimates は表示中の未完成タスクの見積りを、プロジェクトごとに集計した表
+// を描画します。
+func (c *Command) drawEstimates(mirror io.Writer) error {
+	tasks := listTask(c.getTaskList(), false)
+
+	if c.isMerged && !c.AppInfo.Tasks.IsShared() {
+		tasks = append(listTask(c.AppInfo.Tasks.Local, false), listTask(c.AppInfo.Tasks.Global, false)...)
+	}
+
+	sums, err := estimate.SumByProject(tasks)
+	if err != nil {
+		return err
+	}
+
+	rows := []table.Row{}
+
+	for _, sum := range sums {
+		rows = append(rows, table.Row{sum.Project, sum.Points, fmt.Sprintf("%d/%d", sum.Estimated, sum.Tasks)})
+	}
+
+	fmt.Fprintln(mirror)
+	c.drawTable(mirror, table.Row{"project", "est", "estimated"}, rows)
+
+	return nil
+}
+
+func (c *Command) drawTable(mirror io.Writer, header table.Row, rows []table.Row) {
 	var (
 		appendSeparator bool
 		style           cui.TableStyle
@@ -117,30 +262,95 @@ func (c *Command) drawTable(mirror io.Writer, taskList *todo.Todo) {
 	}
 
 	// テーブルデータ作成
-	tableTmp.AppendHeader(table.Row{"#", "title"})
+	tableTmp.AppendHeader(header)
 
-	tmpTask := taskList.Filter(todotxt.FilterNotCompleted) // 未完成タスクのみ
 	intSeparator := c.AppInfo.Config.GetInt("separator_interval")
 
-	for i := range tmpTask {
+	for i, row := range rows {
 		if i%intSeparator == 0 && appendSeparator {
 			tableTmp.AppendSeparator() // 区切り線の挿入
 		}
 
-		tableTmp.AppendRow([]interface{}{
-			[]todotxt.Task(tmpTask)[i].ID, []todotxt.Task(tmpTask)[i].Todo,
-		})
+		tableTmp.AppendRow(row)
 	}
 
 	ui.DrawTable(tableTmp, style) // テーブルの描画
 }
 
+// rowsMerged はローカルとグローバルの未完成タスクを、ソース付きの ID で 1 つの
+// 表の行にまとめて返します。
+func (c *Command) rowsMerged() []table.Row {
+	rows := []table.Row{}
+	tasks := c.AppInfo.Tasks
+
+	items := []struct {
+		taskList *todo.Todo
+		source   string
+	}{
+		{tasks.Local, "local"},
+		{tasks.Global, "global"},
+	}
+
+	// ローカルとグローバルが同じファイルの場合はグローバルとしてのみ表示する
+	if tasks.IsShared() {
+		items = items[1:]
+	}
+
+	for _, item := range items {
+		for _, task := range listTask(item.taskList, c.isTree) {
+			if !c.isShown(task) {
+				continue
+			}
+
+			rows = append(rows, c.appendColumns(
+				table.Row{tasks.FormatID(item.taskList, task.ID), item.source, task.Todo}, task,
+			))
+		}
+	}
+
+	return rows
+}
+
+// rowsTask は taskList の未完成タスクを表の行で返します。
+func (c *Command) rowsTask(taskList *todo.Todo) []table.Row {
+	rows := []table.Row{}
+
+	for _, task := range listTask(taskList, c.isTree) {
+		if !c.isShown(task) {
+			continue
+		}
+
+		rows = append(rows, c.appendColumns(table.Row{task.ID, task.Todo}, task))
+	}
+
+	return rows
+}
+
+// isShown は task を一覧に表示する場合に true を返します。"--stale" が指定され
+// ている場合は、指定の日数以上手を付けていないタスクのみを表示します。
+func (c *Command) isShown(task todotxt.Task) bool {
+	if c.staleDays < 1 {
+		return true
+	}
+
+	age, ok := todo.Age(&task, clock.TimeNow())
+
+	return ok && age >= c.staleDays
+}
+
 // List は "list" コマンドの本体です。
 func (c *Command) List(cmd *cobra.Command, args []string) error {
-	taskList := c.getTaskList()
+	if c.stale != "" {
+		days, err := todo.ParseDays(c.stale)
+		if err != nil {
+			return errors.Errorf("期間の指定が不正です（例: 30d, 4w）: %v", c.stale)
+		}
 
-	if taskList.Len() < 1 {
-		return errors.Errorf("まだタスクはありません")
+		c.staleDays = days
+	}
+
+	if c.isWatch {
+		return c.watch(cmd.OutOrStdout())
 	}
 
 	if c.addNoEdit {
@@ -156,11 +366,181 @@ func (c *Command) List(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	c.drawTable(cmd.OutOrStdout(), taskList)
+	return c.render(cmd.OutOrStdout())
+}
+
+// redraw は画面を消去して一覧を表示し直します。errLoad が nil 以外の場合は、一
+// 覧の後に読み込みのエラーを表示します。
+func (c *Command) redraw(w io.Writer, paths []string, errLoad error) {
+	var buf bytes.Buffer
+
+	buf.WriteString(ansiClear)
+
+	if err := c.render(&buf); err != nil {
+		fmt.Fprintln(&buf, err)
+	}
+
+	if errLoad != nil {
+		fmt.Fprintf(&buf, "\n読み込みに失敗しました: %v\n", errLoad)
+	}
+
+	fmt.Fprintf(&buf, "\n%v 更新（ctrl+c で終了）\n", time.Now().Format("15:04:05"))
+
+	for _, path := range paths {
+		fmt.Fprintf(&buf, "  監視中: %v\n", path)
+	}
+
+	_, _ = w.Write(buf.Bytes())
+}
+
+// reload は設定ファイルとローカル・グローバルのタスク・ファイルを読み込み直しま
+// す。
+func (c *Command) reload() error {
+	if err := c.AppInfo.Config.Load(); err != nil {
+		return err
+	}
+
+	if err := c.AppInfo.Tasks.Local.Reload(); err != nil {
+		return err
+	}
+
+	return c.AppInfo.Tasks.Global.Reload()
+}
+
+// render は一覧の表を w に描画します。タスクがない場合、もしくは "--stale" で表
+// 示するタスクがない場合はエラーを返します。
+func (c *Command) render(w io.Writer) error {
+	header, rows := table.Row{"#", "title"}, c.rowsTask(c.getTaskList())
+
+	if c.isMerged {
+		header, rows = table.Row{"#", "source", "title"}, c.rowsMerged()
+	}
+
+	if c.isSpent {
+		header = append(header, "spent")
+	}
+
+	if c.isEstimate {
+		header = append(header, "est")
+	}
+
+	if c.staleDays > 0 {
+		header = append(header, "age")
+
+		if len(rows) < 1 {
+			return errors.Errorf("%d 日以上手を付けていないタスクはありません", c.staleDays)
+		}
+	}
+
+	if c.countTask() < 1 {
+		return errors.Errorf("まだタスクはありません")
+	}
+
+	c.drawTable(w, header, rows)
+
+	if c.isEstimate {
+		return c.drawEstimates(w)
+	}
 
 	return nil
 }
 
+// watch はタスク・ファイルと設定ファイルを監視し、変更されるたびに読み込み直して
+// 一覧を w に表示し直します。WatchContext のコンテキストが終了するまで戻りませ
+// ん。
+func (c *Command) watch(w io.Writer) error {
+	tasks := c.AppInfo.Tasks
+	obj := watcher.New(pathWatch(tasks.Local), pathWatch(tasks.Global), c.AppInfo.Config.FileUsed())
+
+	ctx, cancel := WatchContext()
+	defer cancel()
+
+	c.redraw(w, obj.Paths(), nil)
+
+	return obj.Watch(ctx, func() error {
+		c.redraw(w, obj.Paths(), c.reload())
+
+		return nil
+	})
+}
+
 // ----------------------------------------------------------------------------
 //  Private Functions
 // ----------------------------------------------------------------------------
+
+// listTask は taskList の未完成タスクを返します。"dep:" タグの依存先に未完了の
+// タスクがある（ブロックされている）タスクの Todo には、依存先の id が付加され
+// ます。
+//
+// isTree が true の場合は親の直後に子が並ぶ順序で返し、子のタスクの Todo は深さ
+// に応じてインデントされます。完了済みの親の子は、親の深さで表示されます。
+func listTask(taskList *todo.Todo, isTree bool) []todotxt.Task {
+	result := []todotxt.Task{}
+
+	if !isTree {
+		for i := range *taskList.TaskList {
+			if task := &(*taskList.TaskList)[i]; !task.Completed {
+				result = append(result, markBlocked(taskList, task))
+			}
+		}
+
+		return result
+	}
+
+	var walk func(nodes []*todo.Node, depth int)
+
+	walk = func(nodes []*todo.Node, depth int) {
+		for _, node := range nodes {
+			if node.Task.Completed {
+				walk(node.Children, depth)
+
+				continue
+			}
+
+			task := markBlocked(taskList, node.Task)
+
+			if depth > 0 {
+				task.Todo = strings.Repeat("  ", depth-1) + "└ " + task.Todo
+			}
+
+			result = append(result, task)
+
+			walk(node.Children, depth+1)
+		}
+	}
+
+	walk(taskList.Tree(), 0)
+
+	return result
+}
+
+// markBlocked は task のコピーを返します。task がブロックされている場合、コピー
+// の Todo の先頭には "[blocked by 依存先の id]" が付加されます。
+func markBlocked(taskList *todo.Todo, task *todotxt.Task) todotxt.Task {
+	result := *task
+	blockers := taskList.Blockers(task)
+
+	if len(blockers) == 0 {
+		return result
+	}
+
+	ids := make([]string, len(blockers))
+
+	for i, blocker := range blockers {
+		ids[i] = blocker.AdditionalTags[todo.TagID]
+	}
+
+	result.Todo = fmt.Sprintf("[blocked by %v] %v", strings.Join(ids, ","), task.Todo)
+
+	return result
+}
+
+// pathWatch は taskList の監視するタスク・ファイルのパスを返します。ファイルが
+// ない場合は、保存時に作成されるファイルのパスを返します。
+func pathWatch(taskList *todo.Todo) string {
+	if taskList.FileUsed() != "" {
+		return taskList.FileUsed()
+	}
+
+	return filepath.Join(taskList.Dir(), taskList.File())
+}
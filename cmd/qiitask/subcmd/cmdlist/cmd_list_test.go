@@ -1,13 +1,18 @@
 package cmdlist_test
 
 import (
+	"context"
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/KEINOS/go-utiles/util"
 	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdlist"
 	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
 	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
 	"github.com/kami-zh/go-capturer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -69,6 +74,28 @@ func TestList_empty_task(t *testing.T) {
 	assert.Contains(t, out, "まだタスクはありません")
 }
 
+func TestList_only_completed_task(t *testing.T) {
+	pathDirLocal := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, "todo.txt"), []byte("x completed task\n"), 0o600))
+
+	appInfo, err := appinfo.New(pathDirLocal, t.TempDir(), "")
+	require.NoError(t, err)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{
+		"list",
+		"--style",
+		"csv",
+	})
+
+	out := capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute(), "tasks exist even if none is shown")
+	})
+
+	assert.Equal(t, "#,title\n", out)
+}
+
 type dataProvider []struct {
 	option string
 	expect string
@@ -140,3 +167,289 @@ func TestList_style(t *testing.T) {
 		assert.Equal(t, test.expect, out)
 	}
 }
+
+func TestList_merged(t *testing.T) {
+	pathDirLocal := t.TempDir()
+	pathDirHome := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, "todo.txt"), []byte("local task\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirHome, "todo.txt"), []byte("global task 1\nglobal task 2\n"), 0o600))
+
+	appInfo, err := appinfo.New(pathDirLocal, pathDirHome, "")
+	require.NoError(t, err)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{
+		"list",
+		"--merged",
+		"--style",
+		"csv",
+	})
+
+	out := capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute())
+	})
+
+	expect := util.HereDoc(`
+		#,source,title
+		L1,local,local task
+		G1,global,global task 1
+		G2,global,global task 2
+	`)
+
+	assert.Equal(t, expect, out)
+}
+
+func TestList_merged_shared_file(t *testing.T) {
+	pathDirHome := t.TempDir()
+	pathFileGlobal := filepath.Join(pathDirHome, "todo.txt")
+
+	require.NoError(t, os.WriteFile(pathFileGlobal, []byte("global task\n"), 0o600))
+
+	appInfo, err := appinfo.New(t.TempDir(), pathDirHome, "")
+	require.NoError(t, err)
+
+	appInfo.Tasks.Local, err = todo.Open(pathFileGlobal)
+	require.NoError(t, err)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{"list", "--merged", "--style", "csv"})
+
+	out := capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute())
+	})
+
+	assert.Equal(t, "#,source,title\nG1,global,global task\n", out, "tasks should not be listed twice")
+}
+
+func TestList_tree(t *testing.T) {
+	pathDirLocal := t.TempDir()
+	pathDirHome := t.TempDir()
+
+	data := "step 2 parent:epic\nepic task id:epic\nstandalone\nstep 1 parent:epic id:s1\nsub step parent:s1\n"
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, "todo.txt"), []byte(data), 0o600))
+
+	appInfo, err := appinfo.New(pathDirLocal, pathDirHome, "")
+	require.NoError(t, err)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{
+		"list",
+		"--tree",
+		"--style",
+		"csv",
+	})
+
+	out := capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute())
+	})
+
+	expect := util.HereDoc(`
+		#,title
+		2,epic task
+		1,└ step 2
+		4,└ step 1
+		5,  └ sub step
+		3,standalone
+	`)
+
+	assert.Equal(t, expect, out)
+}
+
+func TestList_blocked(t *testing.T) {
+	pathDirLocal := t.TempDir()
+	pathDirHome := t.TempDir()
+
+	data := "deploy dep:build,test\nbuild id:build\nx test id:test\nstep parent:build dep:build\n"
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, "todo.txt"), []byte(data), 0o600))
+
+	appInfo, err := appinfo.New(pathDirLocal, pathDirHome, "")
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		args   []string
+		expect string
+	}{
+		{
+			args: []string{"list", "--style", "csv"},
+			expect: util.HereDoc(`
+				#,title
+				1,[blocked by build] deploy
+				2,build
+				4,[blocked by build] step
+			`),
+		},
+		{
+			args: []string{"list", "--tree", "--style", "csv"},
+			expect: util.HereDoc(`
+				#,title
+				1,[blocked by build] deploy
+				2,build
+				4,└ [blocked by build] step
+			`),
+		},
+	} {
+		mother := cmdroot.New(appInfo)
+		mother.SetArgs(test.args)
+
+		out := capturer.CaptureOutput(func() {
+			require.NoError(t, mother.Execute())
+		})
+
+		assert.Equal(t, test.expect, out, "args: %v", test.args)
+	}
+}
+
+func TestList_spent(t *testing.T) {
+	pathDirLocal := t.TempDir()
+	pathDirHome := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, "todo.txt"), []byte("task 1 spent:1h30m\ntask 2\n"), 0o600))
+
+	appInfo, err := appinfo.New(pathDirLocal, pathDirHome, "")
+	require.NoError(t, err)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{
+		"list",
+		"--spent",
+		"--style",
+		"csv",
+	})
+
+	out := capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute())
+	})
+
+	expect := util.HereDoc(`
+		#,title,spent
+		1,task 1,1h30m
+		2,task 2,
+	`)
+
+	assert.Equal(t, expect, out)
+}
+
+func TestList_estimate(t *testing.T) {
+	pathDirLocal := t.TempDir()
+	pathDirHome := t.TempDir()
+
+	data := "task 1 +web est:3\ntask 2 +api est:5\ntask 3 +web\nx task 4 +web est:8\n"
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, "todo.txt"), []byte(data), 0o600))
+
+	appInfo, err := appinfo.New(pathDirLocal, pathDirHome, "")
+	require.NoError(t, err)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{
+		"list",
+		"--estimate",
+		"--style",
+		"csv",
+	})
+
+	out := capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute())
+	})
+
+	expect := util.HereDoc(`
+		#,title,est
+		1,task 1,3
+		2,task 2,5
+		3,task 3,
+
+		project,est,estimated
+		api,5,1/1
+		web,3,1/2
+	`)
+
+	assert.Equal(t, expect, out)
+}
+
+func TestList_stale(t *testing.T) {
+	testutil.MockTimeNow(t, time.Date(2021, 3, 31, 12, 0, 0, 0, time.Local))
+
+	pathDirLocal := t.TempDir()
+
+	data := "2021-01-01 old task\n" +
+		"2021-01-01 touched task touched:2021-03-30\n" +
+		"no date\n" +
+		"x 2021-01-02 2021-01-01 done task\n" +
+		"just stale touched:2021-03-03\n"
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, "todo.txt"), []byte(data), 0o600))
+
+	appInfo, err := appinfo.New(pathDirLocal, t.TempDir(), "")
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		stale  string
+		expect string
+	}{
+		{"4w", "#,title,age\n1,old task,89 日前\n5,just stale,28 日前\n"},
+		{"30d", "#,title,age\n1,old task,89 日前\n"},
+	} {
+		mother := cmdroot.New(appInfo)
+		mother.SetArgs([]string{"list", "--stale", test.stale, "--style", "csv"})
+
+		out := capturer.CaptureOutput(func() {
+			require.NoError(t, mother.Execute())
+		})
+
+		assert.Equal(t, test.expect, out, "stale: %v", test.stale)
+	}
+
+	for _, test := range []struct {
+		stale  string
+		expect string
+	}{
+		{"100d", "100 日以上手を付けていないタスクはありません"},
+		{"soon", "期間の指定が不正です（例: 30d, 4w）: soon"},
+	} {
+		mother := cmdroot.New(appInfo)
+		mother.SetArgs([]string{"list", "--stale", test.stale})
+
+		out := capturer.CaptureOutput(func() {
+			require.Error(t, mother.Execute())
+		})
+
+		assert.Contains(t, out, test.expect, "stale: %v", test.stale)
+	}
+}
+
+func TestList_watch(t *testing.T) {
+	oldWatchContext := cmdlist.WatchContext
+	defer func() {
+		cmdlist.WatchContext = oldWatchContext
+	}()
+
+	cmdlist.WatchContext = func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.Background(), time.Second)
+	}
+
+	pathDirLocal := t.TempDir()
+	pathFileLocal := filepath.Join(pathDirLocal, todo.NameFile)
+
+	appInfo, err := appinfo.New(pathDirLocal, t.TempDir(), "")
+	require.NoError(t, err)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{"list", "--watch"})
+
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+
+		_ = os.WriteFile(pathFileLocal, []byte("created while watching\n"), 0o600)
+	}()
+
+	out := capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute(), "it should keep watching even if there is no task")
+	})
+
+	assert.Contains(t, out, "まだタスクはありません")
+	assert.Contains(t, out, "監視中: "+pathFileLocal)
+	assert.Contains(t, out, "| 1 | created while watching |", "it should redraw with the reloaded task")
+}
@@ -0,0 +1,376 @@
+/*
+Package cmdlists defines the "lists" command.
+*/
+package cmdlists
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/config"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/safefile"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo  *appinfo.AppInfo
+	CUI      *cui.UI
+	isGlobal bool // flag for "--global" option of "create"
+}
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// regexNameList はタスク・リスト名として使える書式です。（設定のキーは大文字・
+// 小文字を区別しないため、小文字のみとします）
+var regexNameList = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "lists" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdLists := new(Command)
+
+	// コマンドの割り当て
+	cmdLists.Command = &cobra.Command{
+		Use:   "lists",
+		Short: "名前付きのタスク・リストを管理します",
+		Long: util.HereDoc(`
+				About:
+				  'lists' コマンドは、設定ファイルの "lists" に宣言された名前付きのタ
+				  スク・リスト（"work.txt" や "someday.txt" など）の一覧を表示します。
+				  サブ・コマンドでリストの作成・名前の変更・削除ができます。
+
+				  各コマンドで使うリストは '--list NAME' で指定します。指定しない場
+				  合は "todo"（"todo.txt"）が使われます。
+			`),
+		Example: util.HereDoc(`
+				qiitask lists                     // タスク・リストの一覧を表示します
+				qiitask lists create work         // "work.txt" のリストを作成します
+				qiitask lists rename work office  // "work" のリストを "office" に変更します
+				qiitask lists delete office       // "office" のリストを削除します
+				qiitask list --list work          // "work" のリストのタスクを表示します
+			`, "  "),
+		Args: cobra.NoArgs,
+	}
+
+	// Set app info (conf and tasks)
+	cmdLists.AppInfo = appInfo
+
+	// Add CUI object
+	cmdLists.CUI = cui.New()
+
+	// RunE function
+	cmdLists.Command.RunE = cmdLists.Lists
+
+	// "lists" コマンドのサブ・コマンドを追加
+	cmdLists.AddCommand(
+		cmdLists.newCreate(),
+		cmdLists.newRename(),
+		cmdLists.newDelete(),
+	)
+
+	return cmdLists.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Create は "lists create" コマンドの本体です。
+func (c *Command) Create(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	if err := c.validateNew(name); err != nil {
+		return err
+	}
+
+	nameFile := name + filepath.Ext(todo.NameFile)
+
+	c.AppInfo.Config.SetList(name, nameFile)
+
+	if err := c.AppInfo.Config.Save(); err != nil {
+		return errors.Wrap(err, "failed to save config file")
+	}
+
+	pathFile := filepath.Join(c.AppInfo.Tasks.Target(c.isGlobal).Dir(), nameFile)
+
+	if !util.IsFile(pathFile) {
+		if err := safefile.WriteFile(pathFile, []byte{}, 0); err != nil {
+			return errors.Wrap(err, "failed to create task file")
+		}
+	}
+
+	cmd.Println(fmt.Sprintf("タスク・リストを作成しました: %v\n    %v", name, pathFile))
+
+	return nil
+}
+
+// Delete は "lists delete" コマンドの本体です。
+func (c *Command) Delete(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	nameFile, err := c.fileDeclared(name)
+	if err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("タスク・リスト %v の宣言を削除しますか？", name)
+
+	if isYes, err := c.CUI.Confirm(msg); err != nil {
+		return errors.Wrap(err, "error during confirmation")
+	} else if !isYes {
+		return errors.New("削除をキャンセルしました")
+	}
+
+	c.AppInfo.Config.SetList(name, "")
+
+	if err := c.AppInfo.Config.Save(); err != nil {
+		return errors.Wrap(err, "failed to save config file")
+	}
+
+	cmd.Println(fmt.Sprintf("タスク・リストを削除しました: %v", name))
+
+	for _, pathFile := range c.listFiles(nameFile) {
+		msg := fmt.Sprintf("タスク・ファイルも削除しますか？\n    %v", pathFile)
+
+		if isYes, err := c.CUI.Confirm(msg); err != nil {
+			return errors.Wrap(err, "error during confirmation")
+		} else if !isYes {
+			continue
+		}
+
+		if err := os.Remove(pathFile); err != nil {
+			return errors.Wrap(err, "failed to remove task file")
+		}
+
+		cmd.Println(fmt.Sprintf("    削除しました: %v", pathFile))
+	}
+
+	return nil
+}
+
+// Lists は "lists" コマンドの本体です。
+func (c *Command) Lists(cmd *cobra.Command, args []string) error {
+	lists := c.AppInfo.Config.GetLists()
+	names := make([]string, 0, len(lists))
+
+	for name := range lists {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	tableTmp := table.NewWriter()
+	tableTmp.AppendHeader(table.Row{"", "name", "file", "path"})
+
+	for _, name := range names {
+		current := ""
+		if lists[name] == c.AppInfo.Tasks.Global.File() {
+			current = "*"
+		}
+
+		paths := c.listFiles(lists[name])
+		if len(paths) == 0 {
+			paths = []string{"(なし)"}
+		}
+
+		for i, pathFile := range paths {
+			if i == 0 {
+				tableTmp.AppendRow(table.Row{current, name, lists[name], pathFile})
+
+				continue
+			}
+
+			tableTmp.AppendRow(table.Row{"", "", "", pathFile})
+		}
+	}
+
+	ui := cui.New()
+	ui.MirrorIO = cmd.OutOrStdout()
+
+	ui.DrawTable(tableTmp, cui.AsDefaultTable)
+
+	return nil
+}
+
+// Rename は "lists rename" コマンドの本体です。
+//
+// リストのファイル名がリスト名と同じ（"work" なら "work.txt"）場合は、ファイル名
+// も新しい名前に変更します。
+func (c *Command) Rename(cmd *cobra.Command, args []string) error {
+	nameOld, nameNew := args[0], args[1]
+
+	nameFileOld, err := c.fileDeclared(nameOld)
+	if err != nil {
+		return err
+	}
+
+	if err := c.validateNew(nameNew); err != nil {
+		return err
+	}
+
+	nameFileNew := nameFileOld
+	if nameFileOld == nameOld+filepath.Ext(todo.NameFile) {
+		nameFileNew = nameNew + filepath.Ext(todo.NameFile)
+	}
+
+	pathsOld := c.listFiles(nameFileOld)
+
+	if nameFileNew != nameFileOld {
+		for _, pathOld := range pathsOld {
+			if pathNew := filepath.Join(filepath.Dir(pathOld), nameFileNew); util.IsFile(pathNew) {
+				return errors.Errorf("変更先のファイルがすでに存在します: %v", pathNew)
+			}
+		}
+	}
+
+	c.AppInfo.Config.SetList(nameOld, "")
+	c.AppInfo.Config.SetList(nameNew, nameFileNew)
+
+	if err := c.AppInfo.Config.Save(); err != nil {
+		return errors.Wrap(err, "failed to save config file")
+	}
+
+	cmd.Println(fmt.Sprintf("タスク・リストの名前を変更しました: %v -> %v", nameOld, nameNew))
+
+	if nameFileNew == nameFileOld {
+		return nil
+	}
+
+	for _, pathOld := range pathsOld {
+		pathNew := filepath.Join(filepath.Dir(pathOld), nameFileNew)
+
+		if err := os.Rename(pathOld, pathNew); err != nil {
+			return errors.Wrap(err, "failed to rename task file")
+		}
+
+		cmd.Println(fmt.Sprintf("    %v -> %v", pathOld, pathNew))
+	}
+
+	return nil
+}
+
+// fileDeclared は設定で宣言された name のタスク・リストのファイル名を返します。
+// デフォルトのリストや宣言されていないリストの場合はエラーを返します。
+func (c *Command) fileDeclared(name string) (string, error) {
+	if name == config.NameListDefault {
+		return "", errors.Errorf("デフォルトのタスク・リストは変更できません: %v", name)
+	}
+
+	return c.AppInfo.Config.FileList(name)
+}
+
+// listFiles は nameFile のタスク・ファイルのうち、ローカルおよびグローバルに存在
+// するファイルのパスを返します。
+func (c *Command) listFiles(nameFile string) []string {
+	paths := []string{}
+
+	for _, pathDir := range []string{c.AppInfo.Tasks.Local.Dir(), c.AppInfo.Tasks.Global.Dir()} {
+		taskList, err := todo.NewFile(pathDir, nameFile)
+		if err != nil || taskList.FileUsed() == "" {
+			continue
+		}
+
+		if len(paths) == 0 || paths[0] != taskList.FileUsed() {
+			paths = append(paths, taskList.FileUsed())
+		}
+	}
+
+	return paths
+}
+
+// newCreate は "lists create" サブ・コマンドの新規オブジェクトを返します。
+func (c *Command) newCreate() *cobra.Command {
+	cmdCreate := &cobra.Command{
+		Use:   "create NAME",
+		Short: "タスク・リストを作成します",
+		Long: util.HereDoc(`
+				About:
+				  'lists create' コマンドは、"NAME.txt" のタスク・リストを設定ファイ
+				  ルに宣言し、空のタスク・ファイルを作成します。
+			`),
+		Example: util.HereDoc(`
+				qiitask lists create work
+				qiitask lists create someday --global
+			`, "  "),
+		Args: cobra.ExactArgs(1),
+		RunE: c.Create,
+	}
+
+	cmdCreate.Flags().BoolVarP(
+		&c.isGlobal, "global", "g", false, "グローバル・タスクのディレクトリに作成します",
+	)
+
+	return cmdCreate
+}
+
+// newDelete は "lists delete" サブ・コマンドの新規オブジェクトを返します。
+func (c *Command) newDelete() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete NAME",
+		Short: "タスク・リストを削除します",
+		Long: util.HereDoc(`
+				About:
+				  'lists delete' コマンドは、設定ファイルからタスク・リストの宣言を削
+				  除します。タスク・ファイルは確認後に削除されます。
+			`),
+		Example: util.HereDoc(`
+				qiitask lists delete work
+			`, "  "),
+		Args: cobra.ExactArgs(1),
+		RunE: c.Delete,
+	}
+}
+
+// newRename は "lists rename" サブ・コマンドの新規オブジェクトを返します。
+func (c *Command) newRename() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename OLD NEW",
+		Short: "タスク・リストの名前を変更します",
+		Long: util.HereDoc(`
+				About:
+				  'lists rename' コマンドは、タスク・リストの名前を変更します。ファイ
+				  ル名がリスト名と同じ場合は、タスク・ファイルの名前も変更されます。
+			`),
+		Example: util.HereDoc(`
+				qiitask lists rename work office
+			`, "  "),
+		Args: cobra.ExactArgs(2),
+		RunE: c.Rename,
+	}
+}
+
+// validateNew は name が新規のタスク・リスト名として使えない場合にエラーを返しま
+// す。
+func (c *Command) validateNew(name string) error {
+	if !regexNameList.MatchString(name) {
+		return errors.Errorf("タスク・リスト名には英小文字、数字、'-'、'_' のみ使えます: %v", name)
+	}
+
+	if _, ok := c.AppInfo.Config.GetLists()[name]; ok {
+		return errors.Errorf("タスク・リストはすでに存在します: %v", name)
+	}
+
+	return nil
+}
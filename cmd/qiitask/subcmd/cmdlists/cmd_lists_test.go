@@ -0,0 +1,156 @@
+package cmdlists_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdlists"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/kami-zh/go-capturer"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------------------------------------
+//  Helper Functions
+// ----------------------------------------------------------------------------
+
+// execute は pathDirLocal をローカル、pathDirHome をグローバルとして args のコマ
+// ンドを実行し、その出力を返します。
+func execute(t *testing.T, pathDirLocal, pathDirHome string, isError bool, args ...string) string {
+	t.Helper()
+
+	appInfo, err := appinfo.New(pathDirLocal, pathDirHome, "")
+	require.NoError(t, err)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs(args)
+
+	return capturer.CaptureOutput(func() {
+		if isError {
+			require.Error(t, mother.Execute())
+
+			return
+		}
+
+		require.NoError(t, mother.Execute())
+	})
+}
+
+// prepareDirs はローカルのタスクを持つディレクトリとホームディレクトリを返しま
+// す。
+func prepareDirs(t *testing.T) (string, string) {
+	t.Helper()
+
+	pathDirLocal := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, todo.NameFile), []byte("default task\n"), 0o600))
+
+	return pathDirLocal, t.TempDir()
+}
+
+// ----------------------------------------------------------------------------
+//  Tests
+// ----------------------------------------------------------------------------
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj1 := cmdlists.New(appInfo)
+	obj2 := cmdlists.New(appInfo)
+
+	assert.NotSame(t, obj1, obj2, "it should not reference the same object")
+	assert.True(t, obj1.HasSubCommands())
+}
+
+func TestLists_create_and_use(t *testing.T) {
+	pathDirLocal, pathDirHome := prepareDirs(t)
+
+	out := execute(t, pathDirLocal, pathDirHome, false, "lists", "create", "work")
+	assert.Contains(t, out, "タスク・リストを作成しました: work")
+	assert.FileExists(t, filepath.Join(pathDirLocal, "work.txt"))
+	assert.FileExists(t, filepath.Join(pathDirHome, ".config", "qiitask", "config.json"),
+		"the list should be declared in the config file")
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, "work.txt"), []byte("work task\n"), 0o600))
+
+	out = execute(t, pathDirLocal, pathDirHome, false, "list", "--list", "work")
+	assert.Contains(t, out, "work task")
+	assert.NotContains(t, out, "default task")
+
+	out = execute(t, pathDirLocal, pathDirHome, false, "lists")
+	assert.Contains(t, out, "work.txt")
+	assert.Contains(t, out, filepath.Join(pathDirLocal, "work.txt"))
+}
+
+func TestLists_create_invalid(t *testing.T) {
+	pathDirLocal, pathDirHome := prepareDirs(t)
+
+	out := execute(t, pathDirLocal, pathDirHome, true, "lists", "create", "Work List")
+	assert.Contains(t, out, "タスク・リスト名には英小文字、数字")
+
+	out = execute(t, pathDirLocal, pathDirHome, true, "lists", "create", "todo")
+	assert.Contains(t, out, "タスク・リストはすでに存在します")
+}
+
+func TestLists_rename(t *testing.T) {
+	pathDirLocal, pathDirHome := prepareDirs(t)
+
+	execute(t, pathDirLocal, pathDirHome, false, "lists", "create", "work")
+
+	out := execute(t, pathDirLocal, pathDirHome, false, "lists", "rename", "work", "office")
+	assert.Contains(t, out, "タスク・リストの名前を変更しました: work -> office")
+
+	assert.NoFileExists(t, filepath.Join(pathDirLocal, "work.txt"))
+	assert.FileExists(t, filepath.Join(pathDirLocal, "office.txt"))
+
+	out = execute(t, pathDirLocal, pathDirHome, true, "list", "--list", "work")
+	assert.Contains(t, out, "タスク・リストが見つかりません: work")
+}
+
+func TestLists_delete(t *testing.T) {
+	pathDirLocal, pathDirHome := prepareDirs(t)
+
+	execute(t, pathDirLocal, pathDirHome, false, "lists", "create", "work")
+
+	appInfo, err := appinfo.New(pathDirLocal, pathDirHome, "")
+	require.NoError(t, err)
+
+	obj := new(cmdlists.Command)
+	obj.Command = new(cobra.Command)
+	obj.AppInfo = appInfo
+	obj.CUI = cui.New()
+
+	// Deny
+	obj.CUI.ForceFalse = true
+
+	err = obj.Delete(obj.Command, []string{"work"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "削除をキャンセルしました")
+
+	// Accept
+	obj.CUI.ForceFalse = false
+	obj.CUI.ForceTrue = true
+
+	out := capturer.CaptureOutput(func() {
+		require.NoError(t, obj.Delete(obj.Command, []string{"work"}))
+	})
+
+	assert.Contains(t, out, "タスク・リストを削除しました: work")
+	assert.NoFileExists(t, filepath.Join(pathDirLocal, "work.txt"))
+	assert.NotContains(t, appInfo.Config.GetLists(), "work")
+}
+
+func TestLists_delete_default(t *testing.T) {
+	pathDirLocal, pathDirHome := prepareDirs(t)
+
+	out := execute(t, pathDirLocal, pathDirHome, true, "lists", "delete", "todo")
+	assert.Contains(t, out, "デフォルトのタスク・リストは変更できません")
+}
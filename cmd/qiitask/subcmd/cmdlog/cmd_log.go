@@ -0,0 +1,168 @@
+/*
+Package cmdlog defines the "log" command.
+*/
+package cmdlog
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/journal"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo  *appinfo.AppInfo
+	isGlobal bool // flag for "--global" option
+	showDiff bool // flag for "--diff" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "log" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdLog := new(Command)
+
+	// コマンドの割り当て
+	cmdLog.Command = &cobra.Command{
+		Use:   "log",
+		Short: "タスクや設定ファイルの変更履歴を表示します",
+		Long: util.HereDoc(`
+				About:
+				  'log' コマンドは、変更履歴（ジャーナル）を新しい順に表示します。
+				  'undo' コマンドで取り消された変更には "undone" と表示されます。
+			`),
+		Example: util.HereDoc(`
+				qiitask log
+				qiitask log --diff   // 変更内容も表示します
+				qiitask log --global // グローバル・タスクの変更履歴を表示します
+			`, "  "),
+		Args: cobra.NoArgs,
+	}
+
+	// Set app info (conf and tasks)
+	cmdLog.AppInfo = appInfo
+
+	// RunE function
+	cmdLog.Command.RunE = cmdLog.Log
+
+	// Define flags for `log` command.
+	cmdLog.Flags().BoolVarP(
+		&cmdLog.isGlobal, "global", "g", false, "グローバル・タスクの変更履歴を表示します",
+	)
+	cmdLog.Flags().BoolVarP(
+		&cmdLog.showDiff, "diff", "d", false, "変更内容（差分）も表示します",
+	)
+
+	return cmdLog.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Log は "log" コマンドの本体です。
+func (c *Command) Log(cmd *cobra.Command, args []string) error {
+	pathFile := c.AppInfo.Tasks.Target(c.isGlobal).FileUsed()
+	if pathFile == "" {
+		return errors.New("変更履歴はありません")
+	}
+
+	history, err := journal.Load(pathFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to load journal")
+	}
+
+	if len(history.Entries) == 0 {
+		return errors.New("変更履歴はありません")
+	}
+
+	if c.showDiff {
+		c.printDiff(cmd, history)
+
+		return nil
+	}
+
+	tableTmp := table.NewWriter()
+	tableTmp.AppendHeader(table.Row{"#", "time", "operation", "file", "changes", "state"})
+
+	for i := len(history.Entries) - 1; i >= 0; i-- {
+		entry := history.Entries[i]
+		added, deleted := entry.CountLines()
+
+		tableTmp.AppendRow(table.Row{
+			i + 1,
+			entry.Time.Format("2006-01-02 15:04:05"),
+			entry.Operation,
+			filepath.Base(entry.File),
+			fmt.Sprintf("+%d -%d", added, deleted),
+			getState(history, i),
+		})
+	}
+
+	ui := cui.New()
+	ui.MirrorIO = cmd.OutOrStdout()
+
+	ui.DrawTable(tableTmp, cui.AsDefaultTable)
+
+	return nil
+}
+
+// printDiff は各変更履歴の差分を新しい順に出力します。
+func (c *Command) printDiff(cmd *cobra.Command, history *journal.Journal) {
+	for i := len(history.Entries) - 1; i >= 0; i-- {
+		entry := history.Entries[i]
+
+		cmd.Printf("#%d %v %v %v %v\n",
+			i+1,
+			entry.Time.Format("2006-01-02 15:04:05"),
+			entry.Operation,
+			entry.File,
+			getState(history, i),
+		)
+
+		for _, change := range entry.Changes {
+			cmd.Printf("@@ -%d,%d +%d,%d @@\n",
+				change.BeforeStart+1, len(change.Before), change.AfterStart+1, len(change.After),
+			)
+
+			for _, line := range change.Before {
+				cmd.Println("-" + line)
+			}
+
+			for _, line := range change.After {
+				cmd.Println("+" + line)
+			}
+		}
+
+		cmd.Println()
+	}
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// getState は index 番目の履歴が Undo 済みの場合に "undone" を返します。
+func getState(history *journal.Journal, index int) string {
+	if history.Entries[index].Undone {
+		return "undone"
+	}
+
+	return ""
+}
@@ -0,0 +1,90 @@
+package cmdlog_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdlog"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/journal"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/kami-zh/go-capturer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj1 := cmdlog.New(appInfo)
+	obj2 := cmdlog.New(appInfo)
+
+	assert.NotSame(t, obj1, obj2, "it should not reference the same object")
+}
+
+func TestLog(t *testing.T) {
+	oldOperation := journal.Operation
+	defer func() {
+		journal.Operation = oldOperation
+	}()
+
+	pathDir := t.TempDir()
+	pathFile := filepath.Join(pathDir, todo.NameFile)
+
+	require.NoError(t, os.WriteFile(pathFile, []byte("task 1\n"), 0o600))
+
+	tasks, err := todo.New(pathDir)
+	require.NoError(t, err)
+
+	journal.Operation = "dummy-op"
+
+	tasks.AddTask(&todotxt.Task{Todo: "task 2"})
+	require.NoError(t, tasks.SaveAs(pathFile))
+
+	appInfo, err := appinfo.New(pathDir, t.TempDir(), "")
+	require.NoError(t, err)
+
+	mother := cmdroot.New(appInfo)
+
+	// Table
+	mother.SetArgs([]string{"log"})
+
+	out := capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute())
+	})
+
+	assert.Contains(t, out, "dummy-op")
+	assert.Contains(t, out, "+1 -0")
+
+	// Diff
+	mother.SetArgs([]string{"log", "--diff"})
+
+	out = capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute())
+	})
+
+	assert.Contains(t, out, "@@ -2,0 +2,1 @@")
+	assert.Contains(t, out, "+task 2")
+}
+
+func TestLog_empty(t *testing.T) {
+	pathDir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDir, todo.NameFile), []byte("task 1\n"), 0o600))
+
+	appInfo, err := appinfo.New(pathDir, t.TempDir(), "")
+	require.NoError(t, err)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{"log"})
+
+	out := capturer.CaptureOutput(func() {
+		require.Error(t, mother.Execute())
+	})
+
+	assert.Contains(t, out, "変更履歴はありません")
+}
@@ -0,0 +1,153 @@
+/*
+Package cmdmove defines the "move" command.
+*/
+package cmdmove
+
+import (
+	"fmt"
+
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo *appinfo.AppInfo
+	CUI     *cui.UI
+	isCopy  bool   // true の場合は移動せずにコピーする（"copy" コマンド）
+	nameTo  string // flag for "--to" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "move" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdMove := new(Command)
+
+	// コマンドの割り当て
+	cmdMove.Command = &cobra.Command{
+		Use:   "move ID --to global|local",
+		Short: "タスクをローカルとグローバルの間で移動します",
+		Long: util.HereDoc(`
+				About:
+				  'move' コマンドは、指定した ID のタスクをローカルとグローバルの間で
+				  移動します。優先度やタグなどはそのまま移動されます。
+
+				  ID は 'list --merged' で表示される "L3", "G7" の書式でも指定できま
+				  す。数字のみの場合は移動先の反対側のタスクの ID として扱います。
+
+				  移動元・移動先のどちらかの保存に失敗した場合は、両方のファイルが移
+				  動前の状態に戻ります。
+			`),
+		Example: util.HereDoc(`
+				qiitask move 3 --to global  // ローカルの 3 番のタスクをグローバルに移動します
+				qiitask move G7 --to local  // グローバルの 7 番のタスクをローカルに移動します
+			`, "  "),
+		Args: cobra.ExactArgs(1),
+	}
+
+	return cmdMove.setup(appInfo)
+}
+
+// NewCopy は "copy" コマンドの新規オブジェクト（のポインタ）を返します。"copy"
+// コマンドは移動元のタスクを残す以外は "move" コマンドと同じです。
+func NewCopy(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdCopy := new(Command)
+	cmdCopy.isCopy = true
+
+	// コマンドの割り当て
+	cmdCopy.Command = &cobra.Command{
+		Use:   "copy ID --to global|local",
+		Short: "タスクをローカルとグローバルの間でコピーします",
+		Long: util.HereDoc(`
+				About:
+				  'copy' コマンドは、指定した ID のタスクをローカルとグローバルの間で
+				  コピーします。優先度やタグなどはそのままコピーされます。
+
+				  ID は 'list --merged' で表示される "L3", "G7" の書式でも指定できま
+				  す。数字のみの場合はコピー先の反対側のタスクの ID として扱います。
+			`),
+		Example: util.HereDoc(`
+				qiitask copy 3 --to global  // ローカルの 3 番のタスクをグローバルにコピーします
+				qiitask copy G7 --to local  // グローバルの 7 番のタスクをローカルにコピーします
+			`, "  "),
+		Args: cobra.ExactArgs(1),
+	}
+
+	return cmdCopy.setup(appInfo)
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Move は "move" および "copy" コマンドの本体です。
+func (c *Command) Move(cmd *cobra.Command, args []string) error {
+	var toGlobal bool
+
+	switch c.nameTo {
+	case "global":
+		toGlobal = true
+	case "local":
+		toGlobal = false
+	default:
+		return errors.Errorf("--to には global か local を指定してください: %q", c.nameTo)
+	}
+
+	tasks := c.AppInfo.Tasks
+
+	task, err := tasks.Transfer(c.CUI, args[0], toGlobal, c.isCopy)
+	if err != nil {
+		return err
+	}
+
+	to := tasks.Local
+	if toGlobal {
+		to = tasks.Global
+	}
+
+	action := "移動"
+	if c.isCopy {
+		action = "コピー"
+	}
+
+	cmd.Println(fmt.Sprintf(
+		"タスクを%vしました: %v -> %v\n    %v", action, args[0], tasks.FormatID(to, task.ID), task.Todo,
+	))
+
+	return nil
+}
+
+// setup は "move" および "copy" コマンドで共通のフィールドとフラグを設定します。
+func (c *Command) setup(appInfo *appinfo.AppInfo) *cobra.Command {
+	// Set app info (conf and tasks)
+	c.AppInfo = appInfo
+
+	// Add CUI object
+	c.CUI = cui.New()
+
+	// RunE function
+	c.Command.RunE = c.Move
+
+	// Define flags for the command.
+	c.Flags().StringVar(
+		&c.nameTo, "to", "", "移動先を指定します（global, local）",
+	)
+
+	_ = c.MarkFlagRequired("to")
+
+	return c.Command
+}
@@ -0,0 +1,86 @@
+package cmdmove_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdmove"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/kami-zh/go-capturer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// prepareAppInfo はローカルとグローバルにタスクを 1 つずつ持つ AppInfo を返しま
+// す。
+func prepareAppInfo(t *testing.T) *appinfo.AppInfo {
+	t.Helper()
+
+	pathDirLocal := t.TempDir()
+	pathDirHome := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, todo.NameFile), []byte("local task\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirHome, todo.NameFile), []byte("global task\n"), 0o600))
+
+	appInfo, err := appinfo.New(pathDirLocal, pathDirHome, "")
+	require.NoError(t, err)
+
+	return appInfo
+}
+
+func TestNew(t *testing.T) {
+	appInfo := prepareAppInfo(t)
+
+	obj1 := cmdmove.New(appInfo)
+	obj2 := cmdmove.NewCopy(appInfo)
+
+	assert.NotSame(t, obj1, obj2, "it should not reference the same object")
+	assert.Equal(t, "move", obj1.Name())
+	assert.Equal(t, "copy", obj2.Name())
+}
+
+func TestMove(t *testing.T) {
+	appInfo := prepareAppInfo(t)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{"move", "1", "--to", "global"})
+
+	out := capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute())
+	})
+
+	assert.Contains(t, out, "タスクを移動しました: 1 -> G2")
+	assert.Equal(t, 0, appInfo.Tasks.Local.Len())
+	assert.Equal(t, 2, appInfo.Tasks.Global.Len())
+}
+
+func TestCopy(t *testing.T) {
+	appInfo := prepareAppInfo(t)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{"copy", "G1", "--to", "local"})
+
+	out := capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute())
+	})
+
+	assert.Contains(t, out, "タスクをコピーしました: G1 -> L2")
+	assert.Equal(t, 2, appInfo.Tasks.Local.Len())
+	assert.Equal(t, 1, appInfo.Tasks.Global.Len())
+}
+
+func TestMove_bad_destination(t *testing.T) {
+	appInfo := prepareAppInfo(t)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{"move", "1", "--to", "remote"})
+
+	out := capturer.CaptureOutput(func() {
+		require.Error(t, mother.Execute())
+	})
+
+	assert.Contains(t, out, "--to には global か local を指定してください")
+}
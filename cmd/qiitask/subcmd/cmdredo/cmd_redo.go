@@ -0,0 +1,94 @@
+/*
+Package cmdredo defines the "redo" command.
+*/
+package cmdredo
+
+import (
+	"fmt"
+
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/journal"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo  *appinfo.AppInfo
+	isGlobal bool // flag for "--global" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "redo" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdRedo := new(Command)
+
+	// コマンドの割り当て
+	cmdRedo.Command = &cobra.Command{
+		Use:   "redo",
+		Short: "取り消した変更をやり直します",
+		Long: util.HereDoc(`
+				About:
+				  'redo' コマンドは、'undo' コマンドで取り消した変更を、変更履歴（ジャー
+				  ナル）をもとにやり直します。変更履歴は 'log' コマンドで確認できます。
+			`),
+		Example: util.HereDoc(`
+				qiitask redo
+				qiitask redo --global // グローバル・タスクの変更をやり直します
+			`, "  "),
+		Args: cobra.NoArgs,
+	}
+
+	// Set app info (conf and tasks)
+	cmdRedo.AppInfo = appInfo
+
+	// RunE function
+	cmdRedo.Command.RunE = cmdRedo.Redo
+
+	// Define flags for `redo` command.
+	cmdRedo.Flags().BoolVarP(
+		&cmdRedo.isGlobal, "global", "g", false, "グローバル・タスクの変更をやり直します",
+	)
+
+	return cmdRedo.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Redo は "redo" コマンドの本体です。
+func (c *Command) Redo(cmd *cobra.Command, args []string) error {
+	pathFile := c.AppInfo.Tasks.Target(c.isGlobal).FileUsed()
+	if pathFile == "" {
+		return errors.New("変更履歴はありません")
+	}
+
+	history, err := journal.Load(pathFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to load journal")
+	}
+
+	entry, err := history.Redo(pathFile, c.AppInfo.Config.GetInt("backup_count"))
+	if err != nil {
+		return err
+	}
+
+	cmd.Println(fmt.Sprintf(
+		"変更をやり直しました: %v %v\n    %v",
+		entry.Time.Format("2006-01-02 15:04:05"), entry.Operation, entry.File,
+	))
+
+	return nil
+}
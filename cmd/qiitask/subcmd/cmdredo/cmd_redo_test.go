@@ -0,0 +1,71 @@
+package cmdredo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdredo"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/kami-zh/go-capturer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj1 := cmdredo.New(appInfo)
+	obj2 := cmdredo.New(appInfo)
+
+	assert.NotSame(t, obj1, obj2, "it should not reference the same object")
+}
+
+func TestRedo(t *testing.T) {
+	pathDir := t.TempDir()
+	pathFile := filepath.Join(pathDir, todo.NameFile)
+
+	require.NoError(t, os.WriteFile(pathFile, []byte("task 1\n"), 0o600))
+
+	// Change the task via Todo to record the journal
+	tasks, err := todo.New(pathDir)
+	require.NoError(t, err)
+
+	tasks.AddTask(&todotxt.Task{Todo: "task 2"})
+	require.NoError(t, tasks.SaveAs(pathFile))
+
+	appInfo, err := appinfo.New(pathDir, t.TempDir(), "")
+	require.NoError(t, err)
+
+	mother := cmdroot.New(appInfo)
+
+	// Nothing to redo
+	mother.SetArgs([]string{"redo"})
+
+	out := capturer.CaptureOutput(func() {
+		require.Error(t, mother.Execute())
+	})
+
+	assert.Contains(t, out, "やり直せる変更はありません")
+
+	// Undo then redo
+	mother.SetArgs([]string{"undo"})
+	capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute())
+	})
+
+	mother.SetArgs([]string{"redo"})
+	out = capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute())
+	})
+
+	assert.Contains(t, out, "変更をやり直しました")
+
+	actual, err := os.ReadFile(pathFile)
+	require.NoError(t, err)
+	assert.Equal(t, "task 1\ntask 2\n", string(actual))
+}
@@ -0,0 +1,181 @@
+/*
+Package cmdrestore defines the "restore" command.
+*/
+package cmdrestore
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/journal"
+	"github.com/Qithub-BOT/QiiTask/core/safefile"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo  *appinfo.AppInfo
+	CUI      *cui.UI
+	isConfig bool // flag for "--settings" option
+	isGlobal bool // flag for "--global" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "restore" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdRestore := new(Command)
+
+	// コマンドの割り当て
+	cmdRestore.Command = &cobra.Command{
+		Use:   "restore [number]",
+		Short: "タスクや設定ファイルをバックアップから復元します",
+		Long: util.HereDoc(`
+				About:
+				  'restore' コマンドは、保存時に作成されたバックアップ（todo.txt.1 など）
+				  の一覧を表示します。バックアップの番号を指定すると、その内容に復元し
+				  ます。復元前の内容もバックアップされるため、復元は取り消せます。
+
+				  バックアップの数は設定ファイルの "backup_count" で変更できます。
+			`),
+		Example: util.HereDoc(`
+				qiitask restore              // タスクのバックアップ一覧を表示します
+				qiitask restore 1            // 1 つ前の状態にタスクを復元します
+				qiitask restore --global 2   // グローバル・タスクを 2 つ前の状態に復元します
+				qiitask restore --settings 1 // 設定ファイルを 1 つ前の状態に復元します
+			`, "  "),
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	// Set app info (conf and tasks)
+	cmdRestore.AppInfo = appInfo
+
+	// Add CUI object
+	cmdRestore.CUI = cui.New()
+
+	// RunE function
+	cmdRestore.Command.RunE = cmdRestore.Restore
+
+	// Define flags for `restore` command.
+	cmdRestore.Flags().BoolVarP(
+		&cmdRestore.isConfig, "settings", "s", false, "設定ファイルを対象にします",
+	)
+	cmdRestore.Flags().BoolVarP(
+		&cmdRestore.isGlobal, "global", "g", false, "グローバル・タスクを対象にします",
+	)
+
+	return cmdRestore.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// drawBackupList はバックアップの一覧をテーブルで描画します。
+func (c *Command) drawBackupList(cmd *cobra.Command, pathFile string) error {
+	listBackup := safefile.ListBackup(pathFile)
+	if len(listBackup) == 0 {
+		return errors.Errorf("バックアップはありません: %v", pathFile)
+	}
+
+	tableTmp := table.NewWriter()
+	tableTmp.AppendHeader(table.Row{"#", "updated", "path"})
+
+	for i, pathBackup := range listBackup {
+		updated := ""
+
+		if info, err := os.Stat(pathBackup); err == nil {
+			updated = info.ModTime().Format("2006-01-02 15:04:05")
+		}
+
+		tableTmp.AppendRow(table.Row{i + 1, updated, pathBackup})
+	}
+
+	ui := cui.New()
+	ui.MirrorIO = cmd.OutOrStdout()
+
+	ui.DrawTable(tableTmp, cui.AsDefaultTable)
+
+	return nil
+}
+
+// getPathTarget は復元対象のファイルのパスを返します。
+func (c *Command) getPathTarget() (string, error) {
+	pathFile := c.AppInfo.Config.FileUsed()
+
+	if !c.isConfig {
+		pathFile = c.AppInfo.Tasks.Target(c.isGlobal).FileUsed()
+	}
+
+	if pathFile == "" {
+		return "", errors.New("復元対象のファイルがありません")
+	}
+
+	return pathFile, nil
+}
+
+// Restore は "restore" コマンドの本体です。
+func (c *Command) Restore(cmd *cobra.Command, args []string) error {
+	pathFile, err := c.getPathTarget()
+	if err != nil {
+		return err
+	}
+
+	// 番号の指定がない場合は一覧を表示
+	if len(args) == 0 {
+		return c.drawBackupList(cmd, pathFile)
+	}
+
+	index, err := strconv.Atoi(args[0])
+	if err != nil || index < 1 {
+		return errors.Errorf("バックアップの番号は 1 以上の数値で指定してください: %v", args[0])
+	}
+
+	msg := fmt.Sprintf(
+		"バックアップ #%d の内容で復元しますか？\n    %v",
+		index, safefile.PathBackup(pathFile, index),
+	)
+
+	if isYes, err := c.CUI.Confirm(msg); err != nil {
+		return errors.Wrap(err, "error during confirmation")
+	} else if !isYes {
+		return errors.New("復元をキャンセルしました")
+	}
+
+	before, err := os.ReadFile(pathFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to read file before restoring")
+	}
+
+	if err := safefile.Restore(pathFile, index, c.AppInfo.Config.GetInt("backup_count")); err != nil {
+		return errors.Wrap(err, "failed to restore")
+	}
+
+	after, err := os.ReadFile(pathFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to read restored file")
+	}
+
+	if err := journal.Record(pathFile, before, after); err != nil {
+		return errors.Wrap(err, "failed to record journal")
+	}
+
+	cmd.Println(fmt.Sprintf("バックアップ #%d から復元しました。\n    %v", index, pathFile))
+
+	return nil
+}
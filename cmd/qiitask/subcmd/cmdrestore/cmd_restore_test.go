@@ -0,0 +1,122 @@
+package cmdrestore_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdrestore"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/safefile"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/kami-zh/go-capturer"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------------------------------------
+//  Helper Functions
+// ----------------------------------------------------------------------------
+
+// prepareBackup は "v1", "v2", "v3" の順に保存した todo.txt を作成し、そのディレ
+// クトリのパスを返します。
+func prepareBackup(t *testing.T) string {
+	t.Helper()
+
+	pathDir := t.TempDir()
+	pathFile := filepath.Join(pathDir, todo.NameFile)
+
+	for _, data := range []string{"v1\n", "v2\n", "v3\n"} {
+		require.NoError(t, safefile.WriteFile(pathFile, []byte(data), safefile.NumBackupDefault))
+	}
+
+	return pathDir
+}
+
+// ----------------------------------------------------------------------------
+//  Tests
+// ----------------------------------------------------------------------------
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj1 := cmdrestore.New(appInfo)
+	obj2 := cmdrestore.New(appInfo)
+
+	assert.NotSame(t, obj1, obj2, "it should not reference the same object")
+}
+
+func TestRestore_list(t *testing.T) {
+	pathDir := prepareBackup(t)
+
+	appInfo, err := appinfo.New(pathDir, t.TempDir(), "")
+	require.NoError(t, err)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{"restore"})
+
+	out := capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute())
+	})
+
+	assert.Contains(t, out, filepath.Join(pathDir, "todo.txt.1"))
+	assert.Contains(t, out, filepath.Join(pathDir, "todo.txt.2"))
+}
+
+func TestRestore_no_target(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{"restore"})
+
+	out := capturer.CaptureOutput(func() {
+		require.Error(t, mother.Execute())
+	})
+
+	assert.Contains(t, out, "復元対象のファイルがありません")
+}
+
+func TestRestore_with_number(t *testing.T) {
+	pathDir := prepareBackup(t)
+
+	appInfo, err := appinfo.New(pathDir, t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj := new(cmdrestore.Command)
+	obj.Command = new(cobra.Command)
+	obj.AppInfo = appInfo
+	obj.CUI = cui.New()
+
+	// Deny
+	obj.CUI.ForceFalse = true
+
+	err = obj.Restore(obj.Command, []string{"2"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "復元をキャンセルしました")
+
+	// Accept
+	obj.CUI.ForceFalse = false
+	obj.CUI.ForceTrue = true
+
+	out := capturer.CaptureOutput(func() {
+		require.NoError(t, obj.Restore(obj.Command, []string{"2"}))
+	})
+
+	assert.Contains(t, out, "バックアップ #2 から復元しました")
+
+	actual, err := os.ReadFile(filepath.Join(pathDir, todo.NameFile))
+	require.NoError(t, err)
+	assert.Equal(t, "v1\n", string(actual))
+
+	// Invalid number
+	err = obj.Restore(obj.Command, []string{"foo"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "バックアップの番号は 1 以上の数値で指定してください")
+}
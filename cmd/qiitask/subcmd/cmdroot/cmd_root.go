@@ -1,15 +1,48 @@
 package cmdroot
 
 import (
+	"strings"
+
 	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdagenda"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmddedupe"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmddone"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdestimate"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdexport"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdfocus"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdimport"
 	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdinit"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdlint"
 	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdlist"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdlists"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdlog"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdmove"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdredo"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdrestore"
 	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdsay"
 	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdsort"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdsplit"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdstart"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdstats"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdtui"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdundo"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdwhere"
 	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/journal"
 	"github.com/spf13/cobra"
 )
 
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+const (
+	nameFlagFile   = "file"
+	nameFlagDir    = "dir"
+	nameFlagConfig = "config"
+	nameFlagList   = "list"
+)
+
 // ----------------------------------------------------------------------------
 //  Commnad Struct
 // ----------------------------------------------------------------------------
@@ -66,19 +99,100 @@ func New(appInfo *appinfo.AppInfo) *cobra.Command {
 				"  ", // HereDoc のインデント
 			),
 			Version: appInfo.Version,
+			// サブコマンドの実行前に、変更履歴に記録する操作名をセットし、ファイル
+			// の指定があれば読み込み直す
+			PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+				journal.Operation = strings.TrimSpace(cmd.Name() + " " + strings.Join(args, " "))
+
+				if err := reload(cmd, appInfo); err != nil {
+					return err
+				}
+
+				return checkLoadErr(cmd, appInfo)
+			},
 		},
 	}
 
 	// Define persistent flags for the app.
 	cmdRoot.PersistentFlags().BoolVar(&appInfo.IsVerbose, "verbose", false, "displays debug info if any")
+	cmdRoot.PersistentFlags().String(nameFlagFile, "",
+		"ローカルのタスク・ファイルのパスを指定します（環境変数 "+appinfo.EnvFile+" より優先）")
+	cmdRoot.PersistentFlags().String(nameFlagDir, "",
+		"ローカルのタスクを検索する開始ディレクトリを指定します（環境変数 "+appinfo.EnvDir+" より優先）")
+	cmdRoot.PersistentFlags().String(nameFlagConfig, "",
+		"設定ファイルのパスを指定します（環境変数 "+appinfo.EnvConfig+" より優先）")
+	cmdRoot.PersistentFlags().String(nameFlagList, "",
+		"使用するタスク・リストの名前を指定します（環境変数 "+appinfo.EnvList+" より優先）")
 
 	// Add child commands to the "root" command.
 	cmdRoot.AddCommand(
-		cmdsay.New(appInfo),  // Add "say" command (with grand child command "hello")
-		cmdlist.New(appInfo), // Add "list" command
-		cmdsort.New(appInfo), // Add "sort" command
-		cmdinit.New(appInfo), // Add "init" command
+		cmdsay.New(appInfo),         // Add "say" command (with grand child command "hello")
+		cmdlist.New(appInfo),        // Add "list" command
+		cmdsort.New(appInfo),        // Add "sort" command
+		cmdinit.New(appInfo),        // Add "init" command
+		cmdrestore.New(appInfo),     // Add "restore" command
+		cmdundo.New(appInfo),        // Add "undo" command
+		cmdredo.New(appInfo),        // Add "redo" command
+		cmdlog.New(appInfo),         // Add "log" command
+		cmdwhere.New(appInfo),       // Add "where" command
+		cmdmove.New(appInfo),        // Add "move" command
+		cmdmove.NewCopy(appInfo),    // Add "copy" command
+		cmdlists.New(appInfo),       // Add "lists" command
+		cmddone.New(appInfo),        // Add "done" command
+		cmdstart.New(appInfo),       // Add "start" command
+		cmdstart.NewStop(appInfo),   // Add "stop" command
+		cmdstart.NewStatus(appInfo), // Add "status" command
+		cmdfocus.New(appInfo),       // Add "focus" command
+		cmdestimate.New(appInfo),    // Add "estimate" command
+		cmdlint.New(appInfo),        // Add "lint" command
+		cmddedupe.New(appInfo),      // Add "dedupe" command
+		cmdsplit.New(appInfo),       // Add "split" command
+		cmdimport.New(appInfo),      // Add "import" command
+		cmdexport.New(appInfo),      // Add "export" command
+		cmdtui.New(appInfo),         // Add "tui" command
+		cmdagenda.New(appInfo),      // Add "agenda" command
+		cmdstats.New(appInfo),       // Add "stats" command
 	)
 
 	return cmdRoot.Command
 }
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// reload は --file, --dir, --config, --list のいずれかのフラグが指定された場合
+// に、その指定でアプリの設定およびタスクを読み込み直します。
+func reload(cmd *cobra.Command, appInfo *appinfo.AppInfo) error {
+	flags := cmd.Flags()
+
+	isChanged := false
+
+	for _, name := range []string{nameFlagFile, nameFlagDir, nameFlagConfig, nameFlagList} {
+		isChanged = isChanged || flags.Changed(name)
+	}
+
+	if !isChanged {
+		return nil
+	}
+
+	override := appinfo.Override{}
+
+	override.PathFile, _ = flags.GetString(nameFlagFile)
+	override.PathDir, _ = flags.GetString(nameFlagDir)
+	override.PathConfig, _ = flags.GetString(nameFlagConfig)
+	override.List, _ = flags.GetString(nameFlagList)
+
+	return appInfo.Reload(override)
+}
+
+// checkLoadErr はタスク・ファイルの読み込みに失敗していた場合にエラーを返しま
+// す。ただし、appinfo.AnnotationAllowLoadErr が付いたコマンドは、読み込めなかっ
+// たファイルを扱うため、エラーを返しません。
+func checkLoadErr(cmd *cobra.Command, appInfo *appinfo.AppInfo) error {
+	if _, ok := cmd.Annotations[appinfo.AnnotationAllowLoadErr]; ok {
+		return nil
+	}
+
+	return appInfo.Tasks.LoadErr()
+}
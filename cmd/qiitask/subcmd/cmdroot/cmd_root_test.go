@@ -1,12 +1,16 @@
 package cmdroot_test
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
 	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdsay"
 	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdsay/cmdhello"
 	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/kami-zh/go-capturer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -59,3 +63,64 @@ func TestNew_has_child(t *testing.T) {
 	assert.IsType(t, expectGrandChild, actualGrandChild,
 		"command 'root' should contain 'hello' as a grandchild")
 }
+
+func TestNew_flag_file(t *testing.T) {
+	pathFileTask := filepath.Join(t.TempDir(), "work.txt")
+
+	require.NoError(t, os.WriteFile(pathFileTask, []byte("task 1\n"), 0o600))
+
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{"list", "--file", pathFileTask})
+
+	out := capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute())
+	})
+
+	assert.Contains(t, out, "task 1")
+	assert.Equal(t, pathFileTask, appInfo.Tasks.Local.FileUsed(),
+		"it should reload the tasks with the given file")
+}
+
+func TestNew_lint_broken_default_file(t *testing.T) {
+	pathDirCurr := t.TempDir()
+	pathFileTask := filepath.Join(pathDirCurr, "todo.txt")
+
+	// 日付が不正な行と、長すぎて読み込めない行（改行の抜けなど）を含むファイル
+	data := "task 1 due:2021-13-45\n" + strings.Repeat("x", 70*1024) + "\ntask 3\n"
+
+	require.NoError(t, os.WriteFile(pathFileTask, []byte(data), 0o600))
+
+	appInfo, err := appinfo.New(pathDirCurr, t.TempDir(), "")
+	require.NoError(t, err, "broken task file should not fail the app")
+
+	// lint は読み込めないファイルも検査できる
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{"lint"})
+
+	out := capturer.CaptureOutput(func() {
+		err = mother.Execute()
+	})
+
+	require.Error(t, err, "it should fail with the lint errors")
+	assert.Contains(t, err.Error(), "件のエラー")
+	assert.Contains(t, out, pathFileTask+":1:")
+	assert.Contains(t, out, "scanner")
+
+	// 他のコマンドは読み込みのエラーになる
+	mother = cmdroot.New(appInfo)
+	mother.SetArgs([]string{"list"})
+
+	capturer.CaptureOutput(func() {
+		err = mother.Execute()
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "task file found but another error was produced")
+
+	raw, err := os.ReadFile(pathFileTask)
+	require.NoError(t, err)
+	assert.Equal(t, data, string(raw), "broken task file should not be modified")
+}
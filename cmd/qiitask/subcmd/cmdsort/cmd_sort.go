@@ -2,7 +2,6 @@ package cmdsort
 
 import (
 	"fmt"
-	"log"
 	"path/filepath"
 	"strings"
 
@@ -11,9 +10,10 @@ import (
 
 	"github.com/KEINOS/go-utiles/util"
 	"github.com/Qithub-BOT/QiiTask/core/appinfo"
-	"github.com/Qithub-BOT/QiiTask/core/config"
+	"github.com/Qithub-BOT/QiiTask/core/clock"
 	"github.com/Qithub-BOT/QiiTask/core/cui"
 	"github.com/Qithub-BOT/QiiTask/core/query"
+	"github.com/Qithub-BOT/QiiTask/core/sortlog"
 	"github.com/Qithub-BOT/QiiTask/core/todo"
 	"github.com/spf13/cobra"
 )
@@ -21,10 +21,12 @@ import (
 // Command is the struct to hold cobra.Command and it's flag options.
 type Command struct {
 	*cobra.Command
-	AppInfo  *appinfo.AppInfo
-	CUI      *cui.UI
-	numSort  int
-	isGlobal bool
+	AppInfo   *appinfo.AppInfo
+	CUI       *cui.UI
+	numSort   int
+	isGlobal  bool
+	idsSplit  []int          // ソート後に分割するタスクの ID
+	questions map[string]int // 比較の回答に使われた質問ごとの回数
 }
 
 // ----------------------------------------------------------------------------
@@ -47,6 +49,18 @@ func New(appInfo *appinfo.AppInfo) *cobra.Command {
 				  デフォルトで、客観的な質問を使い全件ソートします。"--top" オプション
 				  で件数指定があった場合は、主観的な質問を使って n 件ぶんを部分ソートし
 				  ます。
+
+				  "id:" と "parent:" タグで親子関係のあるタスクは、同じ親を持つタスク
+				  の間でのみ比較されます。（親と子のタスクが比較されることはありません）
+
+				  "dep:" タグで依存関係のあるタスクは質問されず、依存先のタスクが常に
+				  先に並びます。依存関係が循環している場合はエラーになります。
+
+				  質問で「タスクを分割する」を選ぶと、ソートの後にそのタスクを分割で
+				  きます。（'split' コマンドと同じです）
+
+				  質問の選択肢には、各タスクを最後に変更してからの日数を表示します。
+				  （"touched:" タグの日付で、ない場合は作成日です）
 			`),
 		Example: util.HereDoc(`
 				qiitask sort          // 全件ソート
@@ -78,7 +92,9 @@ func New(appInfo *appinfo.AppInfo) *cobra.Command {
 //  Methods
 // ----------------------------------------------------------------------------
 
-func (c *Command) askIsALessThanB(a, b string, indexQ int) bool {
+// askIsALessThanB は a と b のどちらを優先するかをユーザに問い合わせ、a を優先す
+// る場合に true を返します。問い合わせが中断された場合はエラーを返します。
+func (c *Command) askIsALessThanB(a, b *todotxt.Task, indexQ int) (bool, error) {
 	questions := c.AppInfo.Config.GetQueryObjective()
 
 	// Reset index
@@ -87,25 +103,79 @@ func (c *Command) askIsALessThanB(a, b string, indexQ int) bool {
 	}
 
 	idontknow := "質問を変える"
+	split := "タスクを分割する"
+	labelA := labelTask(a)
 	selections := []string{
-		a, b, idontknow,
+		labelA, labelTask(b), idontknow, split,
 	}
 
 	answer, err := c.CUI.Select(questions[indexQ], selections, idontknow, "")
-	// TODO: fatal はよろしくない
 	if err != nil {
-		log.Fatalf("強制終了されました（タスクに変更はありません）: %v\n", err)
+		return false, err
 	}
 
-	if answer == idontknow {
+	switch answer {
+	case idontknow:
 		indexQ++
 
 		c.CUI.DrawHR()
 
 		return c.askIsALessThanB(a, b, indexQ)
+	case split:
+		if err := c.askSplit(a, b); err != nil {
+			return false, err
+		}
+
+		c.CUI.DrawHR()
+
+		return c.askIsALessThanB(a, b, indexQ)
+	}
+
+	if c.questions == nil {
+		c.questions = map[string]int{}
+	}
+
+	c.questions[questions[indexQ]]++
+
+	return answer == labelA, nil
+}
+
+// askSplit は a と b のどちらを分割するかをユーザに問い合わせ、ソート後に分割す
+// るタスクとして登録します。本文が同じタスクを区別できるよう、選択肢には ID を
+// 併記します。
+func (c *Command) askSplit(a, b *todotxt.Task) error {
+	tasks := map[string]*todotxt.Task{}
+	options := []string{}
+
+	for _, task := range []*todotxt.Task{a, b} {
+		option := fmt.Sprintf("%v: %v", task.ID, task.Todo)
+
+		tasks[option] = task
+		options = append(options, option)
+	}
+
+	answer, err := c.CUI.Select(
+		"分割するタスクを選択してください（ソートの後に分割します）",
+		options, options[0], "",
+	)
+	if err != nil {
+		return err
+	}
+
+	task, ok := tasks[answer]
+	if !ok {
+		return nil
+	}
+
+	for _, idSplit := range c.idsSplit {
+		if idSplit == task.ID {
+			return nil
+		}
 	}
 
-	return answer == a
+	c.idsSplit = append(c.idsSplit, task.ID)
+
+	return nil
 }
 
 // GetTaks は現在のタスク一覧のを返します。完了済のタスクはソートされた状態で返されます。
@@ -147,13 +217,11 @@ func (c *Command) SurvaySave() error {
 				pathDirConf = filepath.Join(pathDirConf, ".qiitask")
 			}
 
-			pathFileConf := filepath.Join(pathDirConf, config.NameConf)
-
-			return c.AppInfo.Config.WriteConfigAs(pathFileConf)
+			return c.AppInfo.Config.SaveIn(pathDirConf)
 		case err != nil:
 			return errors.Wrap(err, "error during confirmation")
 		default:
-			fmt.Println("保存しませんでした。（質問タイプを毎回選択する必要があります）")
+			c.Println("保存しませんでした。（質問タイプを毎回選択する必要があります）")
 		}
 
 		c.CUI.DrawHR()
@@ -182,31 +250,66 @@ func (c *Command) Sort(cmd *cobra.Command, args []string) error {
 
 	indexQ := 0
 
-	answers.CustomSort(func(a int, b int) bool {
-		var result bool
+	// 比較の関数はエラーを返せないため、問い合わせが中断された場合はエラーを保持
+	// し、以降は質問せずにソートを終える
+	var errAsk error
 
-		// Both A and B is undone so ask user which is prior
-		A, _ := answers.GetTodoByKey(a)
-		B, _ := answers.GetTodoByKey(b)
+	// 親子関係（"id:", "parent:" タグ）がある場合は、兄弟のタスク間でのみ比較する
+	// "dep:" タグの依存関係がある場合は、依存先のタスクを質問せずに先に並べる
+	err = answers.SortTree(func(a, b *todotxt.Task) bool {
+		var result bool
 
 		switch {
-		case answers.IsKeyDone(a) && answers.IsKeyDone(b):
+		case a.Completed && b.Completed:
 			// Both task is done so keep the order
 			result = true
-		case answers.IsKeyDone(a) && !answers.IsKeyDone(b):
+		case a.Completed && !b.Completed:
 			// Task A is done but B is not so B is prior
 			result = false
-		case !answers.IsKeyDone(a) && answers.IsKeyDone(b):
+		case !a.Completed && b.Completed:
 			// Task A is undone but B is done so A is prior
 			result = true
+		case errAsk != nil:
+			// Asking was aborted so keep the order
+			result = true
 		default:
-			result = c.askIsALessThanB(A, B, indexQ)
+			// Both A and B is undone so ask user which is prior
+			result, errAsk = c.askIsALessThanB(a, b, indexQ)
 		}
 
 		return result
 	})
+	if err != nil {
+		return errors.Wrap(err, "fail to sort tasks")
+	}
+
+	if errAsk != nil {
+		return errors.Wrap(errAsk, "強制終了されました（タスクに変更はありません）")
+	}
+
+	// 質問中に選ばれたタスクを分割する
+	for _, id := range c.idsSplit {
+		task, err := answers.GetTask(id)
+		if err != nil {
+			return errors.Wrap(err, "failed to get task to split")
+		}
+
+		c.CUI.DrawHR()
 
-	return answers.OverWrite(c.CUI)
+		msg, err := answers.AskSplit(c.CUI, task)
+		if err != nil {
+			return errors.Wrap(err, "fail to split task")
+		}
+
+		cmd.Println(msg)
+	}
+
+	if err := answers.OverWrite(c.CUI); err != nil {
+		return err
+	}
+
+	// 'stats' コマンドで集計するための記録
+	return sortlog.Record(answers.PathSave(), c.questions)
 }
 
 // SurvayQueryType はユーザに質問集のタイプ（タスク整理向け、コレクション整理向
@@ -252,3 +355,18 @@ func (c *Command) SurvayQueryType() error {
 
 	return nil
 }
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// labelTask は比較の質問で表示するタスクの選択肢を返します。最後に手を付けてか
+// らの日数が分かる場合は併記します。（例: "write docs（最終更新 12 日前）"）
+func labelTask(task *todotxt.Task) string {
+	age := todo.FormatAge(task, clock.TimeNow())
+	if age == "" {
+		return task.Todo
+	}
+
+	return fmt.Sprintf("%v（最終更新 %v）", task.Todo, age)
+}
@@ -1,15 +1,20 @@
 package cmdsort_test
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/1set/todotxt"
+	"github.com/AlecAivazis/survey/v2"
 	"github.com/KEINOS/go-utiles/util"
 	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdsort"
 	"github.com/Qithub-BOT/QiiTask/core/appinfo"
 	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/sortlog"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
 	"github.com/Qithub-BOT/QiiTask/core/todo"
 	"github.com/kami-zh/go-capturer"
 	"github.com/spf13/cobra"
@@ -38,6 +43,8 @@ func createTask(t *testing.T, todo string, isDone bool) todotxt.Task {
 func createSortCommand(t *testing.T, pathDirTemp string) *cmdsort.Command {
 	t.Helper()
 
+	testutil.MockTimeNow(t, testutil.Now)
+
 	appInfo, err := appinfo.New(pathDirTemp, pathDirTemp, "")
 	require.NoError(t, err)
 
@@ -105,12 +112,280 @@ func TestSort(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Contains(t, string(savedTask), util.HereDoc(`
-		local task 3
-		local task 4
-		x local task 5
-		x local task 2
-		x local task 1
+		local task 3 touched:2021-01-06
+		local task 4 touched:2021-01-06
+		x local task 5 touched:2021-01-06
+		x local task 2 touched:2021-01-06
+		x local task 1 touched:2021-01-06
 	`))
+
+	// Sort log for "stats" command
+	log, err := sortlog.Load(todo.NameFile)
+	require.NoError(t, err)
+	require.Len(t, log.Sessions, 1)
+
+	assert.Equal(t, 1, log.Sessions[0].Comparisons, "only the undone tasks should be asked")
+	assert.Len(t, log.Sessions[0].Questions, 1)
+}
+
+func TestSort_dependency(t *testing.T) {
+	for _, test := range []struct {
+		data      string
+		expect    string
+		msgError  string
+		isFailure bool
+	}{
+		{
+			data:   "deploy dep:build\nbuild id:build\n",
+			expect: "build id:build\ndeploy dep:build\n",
+		},
+		{
+			data:      "deploy id:deploy dep:build\nbuild id:build dep:deploy\n",
+			msgError:  "タスクの依存関係が循環しています",
+			isFailure: true,
+		},
+	} {
+		tmpDir := t.TempDir()
+		obj := createSortCommand(t, tmpDir)
+
+		retrunOrigin := util.ChDir(tmpDir)
+
+		require.NoError(t, os.WriteFile(todo.NameFile, []byte(test.data), 0o600))
+
+		taskList, err := todo.Open(filepath.Join(tmpDir, todo.NameFile))
+		require.NoError(t, err)
+
+		obj.AppInfo.Tasks.Local = taskList
+
+		obj.CUI.ForceFalse = true // do not save the config file
+		obj.CUI.ForceString = "task"
+
+		_ = capturer.CaptureOutput(func() {
+			err = obj.Sort(obj.Command, []string{})
+		})
+
+		savedTask, errRead := os.ReadFile(todo.NameFile)
+		require.NoError(t, errRead)
+
+		retrunOrigin()
+
+		if test.isFailure {
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.msgError)
+			assert.Equal(t, test.data, string(savedTask), "the task file should not be changed on error")
+
+			continue
+		}
+
+		require.NoError(t, err)
+		assert.Equal(t, test.expect, string(savedTask))
+	}
+}
+
+func TestSort_split(t *testing.T) {
+	tmpDir := t.TempDir()
+	obj := createSortCommand(t, tmpDir)
+
+	retrunOrigin := util.ChDir(tmpDir)
+	defer retrunOrigin()
+
+	require.NoError(t, os.WriteFile(todo.NameFile, []byte("b task +proj\na task\n"), 0o600))
+
+	taskList, err := todo.Open(filepath.Join(tmpDir, todo.NameFile))
+	require.NoError(t, err)
+
+	obj.AppInfo.Tasks.Local = taskList
+	obj.CUI.ForceFalse = true // do not save the config file
+
+	isAskedSplit := false
+
+	testutil.MockAskOne(t, func(p survey.Prompt) (interface{}, error) {
+		if _, ok := p.(*survey.Multiline); ok {
+			return "step 1\nstep 2", nil
+		}
+
+		msg := p.(*survey.Select).Message
+
+		switch {
+		case strings.Contains(msg, "質問タイプ"):
+			return "task", nil
+		case strings.Contains(msg, "分割するタスク"):
+			return "1: b task", nil
+		case strings.Contains(msg, "元のタスク"):
+			return "親タスクとして残す", nil
+		case !isAskedSplit:
+			isAskedSplit = true
+
+			return "タスクを分割する", nil
+		}
+
+		return "a task", nil
+	})
+
+	out := capturer.CaptureOutput(func() {
+		err = obj.Sort(obj.Command, []string{})
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "タスクを 2 件に分割しました: b task")
+
+	savedTask, err := os.ReadFile(todo.NameFile)
+	require.NoError(t, err)
+
+	expect := "a task\n" +
+		"b task +proj id:t1 touched:2021-01-06\n" +
+		"step 1 +proj parent:t1 touched:2021-01-06\n" +
+		"step 2 +proj parent:t1 touched:2021-01-06\n"
+
+	assert.Equal(t, expect, string(savedTask))
+}
+
+func TestSort_split_same_text(t *testing.T) {
+	tmpDir := t.TempDir()
+	obj := createSortCommand(t, tmpDir)
+
+	retrunOrigin := util.ChDir(tmpDir)
+	defer retrunOrigin()
+
+	require.NoError(t, os.WriteFile(todo.NameFile, []byte("same task\nsame task +proj\n"), 0o600))
+
+	taskList, err := todo.Open(filepath.Join(tmpDir, todo.NameFile))
+	require.NoError(t, err)
+
+	obj.AppInfo.Tasks.Local = taskList
+	obj.CUI.ForceFalse = true // do not save the config file
+
+	isAskedSplit := false
+
+	testutil.MockAskOne(t, func(p survey.Prompt) (interface{}, error) {
+		if _, ok := p.(*survey.Multiline); ok {
+			return "step 1", nil
+		}
+
+		prompt := p.(*survey.Select)
+
+		switch {
+		case strings.Contains(prompt.Message, "質問タイプ"):
+			return "task", nil
+		case strings.Contains(prompt.Message, "分割するタスク"):
+			assert.ElementsMatch(t, []string{"1: same task", "2: same task"}, prompt.Options, "options should be distinguished by ID")
+
+			return "2: same task", nil
+		case strings.Contains(prompt.Message, "元のタスク"):
+			return "親タスクとして残す", nil
+		case !isAskedSplit:
+			isAskedSplit = true
+
+			return "タスクを分割する", nil
+		}
+
+		return prompt.Options[0], nil
+	})
+
+	_ = capturer.CaptureOutput(func() {
+		err = obj.Sort(obj.Command, []string{})
+	})
+
+	require.NoError(t, err)
+
+	savedTask, err := os.ReadFile(todo.NameFile)
+	require.NoError(t, err)
+
+	expect := []string{
+		"same task",
+		"same task +proj id:t1 touched:2021-01-06",
+		"step 1 +proj parent:t1 touched:2021-01-06",
+	}
+
+	assert.ElementsMatch(t, expect, strings.Split(strings.TrimSuffix(string(savedTask), "\n"), "\n"),
+		"the chosen task should be split, not the first one with the same text")
+}
+
+func TestSort_split_aborted(t *testing.T) {
+	tmpDir := t.TempDir()
+	obj := createSortCommand(t, tmpDir)
+
+	retrunOrigin := util.ChDir(tmpDir)
+	defer retrunOrigin()
+
+	require.NoError(t, os.WriteFile(todo.NameFile, []byte("b task\na task\n"), 0o600))
+
+	taskList, err := todo.Open(filepath.Join(tmpDir, todo.NameFile))
+	require.NoError(t, err)
+
+	obj.AppInfo.Tasks.Local = taskList
+	obj.CUI.ForceFalse = true // do not save the config file
+
+	testutil.MockAskOne(t, func(p survey.Prompt) (interface{}, error) {
+		msg := p.(*survey.Select).Message
+
+		switch {
+		case strings.Contains(msg, "質問タイプ"):
+			return "task", nil
+		case strings.Contains(msg, "分割するタスク"):
+			return nil, errors.New("interrupted")
+		}
+
+		return "タスクを分割する", nil
+	})
+
+	_ = capturer.CaptureOutput(func() {
+		err = obj.Sort(obj.Command, []string{})
+	})
+
+	require.Error(t, err, "aborting the split question should fail the sort instead of exiting")
+	assert.Contains(t, err.Error(), "強制終了されました（タスクに変更はありません）")
+	assert.Contains(t, err.Error(), "interrupted")
+
+	savedTask, err := os.ReadFile(todo.NameFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, "b task\na task\n", string(savedTask), "tasks should not be saved")
+}
+
+func TestSort_age_label(t *testing.T) {
+	tmpDir := t.TempDir()
+	obj := createSortCommand(t, tmpDir)
+
+	retrunOrigin := util.ChDir(tmpDir)
+	defer retrunOrigin()
+
+	require.NoError(t, os.WriteFile(todo.NameFile, []byte("new task touched:2021-01-06\n2021-01-01 old task\n"), 0o600))
+
+	taskList, err := todo.Open(filepath.Join(tmpDir, todo.NameFile))
+	require.NoError(t, err)
+
+	obj.AppInfo.Tasks.Local = taskList
+	obj.CUI.ForceFalse = true // do not save the config file
+
+	options := []string{}
+
+	testutil.MockAskOne(t, func(p survey.Prompt) (interface{}, error) {
+		prompt := p.(*survey.Select)
+
+		if strings.Contains(prompt.Message, "質問タイプ") {
+			return "task", nil
+		}
+
+		options = prompt.Options
+
+		return "old task（最終更新 5 日前）", nil
+	})
+
+	_ = capturer.CaptureOutput(func() {
+		err = obj.Sort(obj.Command, []string{})
+	})
+
+	require.NoError(t, err)
+	require.Len(t, options, 4)
+	assert.Contains(t, options[:2], "new task（最終更新 今日）")
+	assert.Contains(t, options[:2], "old task（最終更新 5 日前）")
+
+	savedTask, err := os.ReadFile(todo.NameFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2021-01-01 old task\nnew task touched:2021-01-06\n", string(savedTask),
+		"reordered tasks should not be touched")
 }
 
 func TestSort_query_not_ready(t *testing.T) {
@@ -287,23 +562,22 @@ func TestSurvaySave(t *testing.T) {
 
 	{
 		obj.CUI.ForceError = false
-		obj.CUI.ForceTrue = true
-		obj.CUI.ForceFalse = false
+		obj.CUI.ForceTrue = false
+		obj.CUI.ForceFalse = true
 		obj.CUI.ForceString = "task"
 
 		out := capturer.CaptureOutput(func() {
 			err := obj.SurvaySave()
 
-			require.Error(t, err)
-			assert.Contains(t, err.Error(), ".qiitask/config.json: no such file or directory")
+			require.NoError(t, err)
 		})
 
-		assert.Empty(t, out)
+		assert.Contains(t, out, "保存しませんでした。（質問タイプを毎回選択する必要があります）")
 	}
 	{
 		obj.CUI.ForceError = false
-		obj.CUI.ForceTrue = false
-		obj.CUI.ForceFalse = true
+		obj.CUI.ForceTrue = true
+		obj.CUI.ForceFalse = false
 		obj.CUI.ForceString = "task"
 
 		out := capturer.CaptureOutput(func() {
@@ -312,6 +586,9 @@ func TestSurvaySave(t *testing.T) {
 			require.NoError(t, err)
 		})
 
-		assert.Contains(t, out, "保存しませんでした。（質問タイプを毎回選択する必要があります）")
+		assert.Empty(t, out)
+		assert.FileExists(t, filepath.Join(tmpDir, ".qiitask", "config.json"),
+			"it should create the config directory and file")
+		assert.Equal(t, filepath.Join(tmpDir, ".qiitask", "config.json"), obj.AppInfo.Config.FileUsed())
 	}
 }
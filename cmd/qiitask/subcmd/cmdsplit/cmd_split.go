@@ -0,0 +1,99 @@
+/*
+Package cmdsplit defines the "split" command.
+*/
+package cmdsplit
+
+import (
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo  *appinfo.AppInfo
+	CUI      *cui.UI
+	isGlobal bool // flag for "--global" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "split" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdSplit := new(Command)
+
+	// コマンドの割り当て
+	cmdSplit.Command = &cobra.Command{
+		Use:   "split ID",
+		Short: "タスクを複数のタスクに分割します",
+		Long: util.HereDoc(`
+				About:
+				  'split' コマンドは、指定した ID のタスクを、対話式で入力した複数のタ
+				  スクに分割します。分割後のタスクは、元のタスクの優先度・プロジェク
+				  ト・コンテキストを引き継ぎ、元のタスクの直後に追加されます。
+
+				  元のタスクは「親タスクとして残す」（"id:" と "parent:" タグで親子関
+				  係になります）か、「完了にする」かを選択できます。
+
+				  'sort' の質問でも「タスクを分割する」を選ぶと、ソートの後にタスクを
+				  分割できます。
+			`),
+		Example: util.HereDoc(`
+				qiitask split 3
+				qiitask split G7           // グローバルの 7 番のタスクを分割します
+				qiitask split 2 --global   // 同上（グローバルの 2 番）
+			`, "  "),
+		Args: cobra.ExactArgs(1),
+	}
+
+	// Set app info (conf and tasks)
+	cmdSplit.AppInfo = appInfo
+
+	// Add CUI object
+	cmdSplit.CUI = cui.New()
+
+	// RunE function
+	cmdSplit.Command.RunE = cmdSplit.Split
+
+	// Define flags for `split` command.
+	cmdSplit.Flags().BoolVarP(
+		&cmdSplit.isGlobal, "global", "g", false, "グローバル・タスクを対象にします",
+	)
+
+	return cmdSplit.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Split は "split" コマンドの本体です。
+func (c *Command) Split(cmd *cobra.Command, args []string) error {
+	taskList, task, err := c.AppInfo.Tasks.Resolve(args[0], c.isGlobal)
+	if err != nil {
+		return err
+	}
+
+	msg, err := taskList.AskSplit(c.CUI, task)
+	if err != nil {
+		return err
+	}
+
+	if err := taskList.OverWrite(c.CUI); err != nil {
+		return err
+	}
+
+	cmd.Println(msg)
+
+	return nil
+}
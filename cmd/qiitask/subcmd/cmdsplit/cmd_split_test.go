@@ -0,0 +1,115 @@
+package cmdsplit_test
+
+import (
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdsplit"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dataTasks = "(A) write book +book @home\nx 2021-01-01 finished\nother\n"
+
+// ----------------------------------------------------------------------------
+//  Helper Functions
+// ----------------------------------------------------------------------------
+
+// prepareAppInfo は dataTasks をローカルのタスクに持つ AppInfo と、タスク・フ
+// ァイルのパスを返します。
+func prepareAppInfo(t *testing.T) (*appinfo.AppInfo, string) {
+	t.Helper()
+
+	appInfo := testutil.NewAppInfo(t, dataTasks)
+
+	return appInfo, appInfo.Tasks.Local.FileUsed()
+}
+
+// ----------------------------------------------------------------------------
+//  Tests
+// ----------------------------------------------------------------------------
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj1 := cmdsplit.New(appInfo)
+	obj2 := cmdsplit.New(appInfo)
+
+	assert.NotSame(t, obj1, obj2, "it should not reference the same object")
+	assert.Equal(t, "split", obj1.Name())
+}
+
+func TestSplit_as_parent(t *testing.T) {
+	appInfo, pathFile := prepareAppInfo(t)
+
+	testutil.MockAnswers(t, "chapter 1\n\n(B) chapter 2\n", "親タスクとして残す")
+
+	out, err := testutil.Execute(cmdsplit.New(appInfo), "1")
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "タスクを 2 件に分割しました: write book")
+
+	expect := "(A) write book @home +book id:t1 touched:2021-01-06\n" +
+		"(A) chapter 1 @home +book parent:t1 touched:2021-01-06\n" +
+		"(B) chapter 2 @home +book parent:t1 touched:2021-01-06\n" +
+		"x 2021-01-01 finished\n" +
+		"other\n"
+
+	assert.Equal(t, expect, testutil.ReadFile(t, pathFile))
+}
+
+func TestSplit_as_done(t *testing.T) {
+	appInfo, pathFile := prepareAppInfo(t)
+
+	testutil.MockAnswers(t, "chapter 1", "完了にする")
+
+	out, err := testutil.Execute(cmdsplit.New(appInfo), "L1")
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "元のタスクは完了にしました")
+
+	taskList, err := todo.Open(pathFile)
+	require.NoError(t, err)
+
+	parent, err := taskList.GetTask(1)
+	require.NoError(t, err)
+
+	assert.True(t, parent.Completed)
+
+	child, err := taskList.GetTask(2)
+	require.NoError(t, err)
+
+	assert.Equal(t, "(A) chapter 1 @home +book touched:2021-01-06", child.String())
+}
+
+func TestSplit_errors(t *testing.T) {
+	for _, test := range []struct {
+		id       string
+		lines    string
+		msgError string
+	}{
+		{"2", "step", "完了済みのタスクは分割できません"},
+		{"3", "\n  \n", "分割後のタスクが入力されませんでした"},
+		{"9", "step", "タスクが見つかりません"},
+	} {
+		appInfo, pathFile := prepareAppInfo(t)
+
+		testutil.MockAskOne(t, func(p survey.Prompt) (interface{}, error) {
+			if _, ok := p.(*survey.Multiline); ok {
+				return test.lines, nil
+			}
+
+			return "親タスクとして残す", nil
+		})
+
+		_, err := testutil.Execute(cmdsplit.New(appInfo), test.id)
+
+		require.Error(t, err, "id: %v", test.id)
+		assert.Contains(t, err.Error(), test.msgError)
+		assert.Equal(t, dataTasks, testutil.ReadFile(t, pathFile), "the task file should not be changed on error")
+	}
+}
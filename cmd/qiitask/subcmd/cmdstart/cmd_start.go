@@ -0,0 +1,263 @@
+/*
+Package cmdstart defines the "start", "stop" and "status" commands.
+*/
+package cmdstart
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/1set/todotxt"
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/timelog"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo  *appinfo.AppInfo
+	CUI      *cui.UI
+	isGlobal bool // flag for "--global" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "start" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdStart := new(Command)
+
+	// コマンドの割り当て
+	cmdStart.Command = &cobra.Command{
+		Use:   "start ID",
+		Short: "タスクの作業時間の計測を開始します",
+		Long: util.HereDoc(`
+				About:
+				  'start' コマンドは、指定した ID のタスクの作業時間の計測を開始しま
+				  す。計測は 'stop' コマンドで終了します。
+
+				  作業の記録はタスク・ファイルと同じワークスペースの ".qiitask/timelog.json"
+				  に保存され、作業時間の合計はタスクの "spent:" タグに加算されます。
+				  同時に計測できるタスクは 1 つのみです。
+			`),
+		Example: util.HereDoc(`
+				qiitask start 3
+				qiitask start G7   // グローバルの 7 番のタスクの計測を開始します
+			`, "  "),
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmdStart.Flags().BoolVarP(
+		&cmdStart.isGlobal, "global", "g", false, "グローバル・タスクを対象にします",
+	)
+
+	return cmdStart.setup(appInfo, cmdStart.Start)
+}
+
+// NewStatus は "status" コマンドの新規オブジェクト（のポインタ）を返します。
+func NewStatus(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdStatus := new(Command)
+
+	// コマンドの割り当て
+	cmdStatus.Command = &cobra.Command{
+		Use:   "status",
+		Short: "作業時間を計測中のタスクを表示します",
+		Long: util.HereDoc(`
+				About:
+				  'status' コマンドは、'start' コマンドで計測中のタスクと経過時間を表
+				  示します。
+			`),
+		Example: util.HereDoc(`
+				qiitask status
+			`, "  "),
+		Args: cobra.NoArgs,
+	}
+
+	return cmdStatus.setup(appInfo, cmdStatus.Status)
+}
+
+// NewStop は "stop" コマンドの新規オブジェクト（のポインタ）を返します。
+func NewStop(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdStop := new(Command)
+
+	// コマンドの割り当て
+	cmdStop.Command = &cobra.Command{
+		Use:   "stop",
+		Short: "タスクの作業時間の計測を終了します",
+		Long: util.HereDoc(`
+				About:
+				  'stop' コマンドは、'start' コマンドで計測中のタスクの計測を終了し、
+				  作業時間をタスクの "spent:" タグに加算します。
+			`),
+		Example: util.HereDoc(`
+				qiitask stop
+			`, "  "),
+		Args: cobra.NoArgs,
+	}
+
+	return cmdStop.setup(appInfo, cmdStop.Stop)
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Start は "start" コマンドの本体です。
+func (c *Command) Start(cmd *cobra.Command, args []string) error {
+	taskList, task, err := c.AppInfo.Tasks.Resolve(args[0], c.isGlobal)
+	if err != nil {
+		return err
+	}
+
+	if task.Completed {
+		return errors.Errorf("完了済みのタスクは計測できません: %v", task.Todo)
+	}
+
+	if _, running, err := c.findRunning(); err != nil {
+		return err
+	} else if running != nil {
+		return errors.Errorf("すでに計測中のタスクがあります: %v（'stop' で終了してください）", running.Task)
+	}
+
+	log, err := timelog.Load(taskList.PathSave())
+	if err != nil {
+		return err
+	}
+
+	if _, err := log.Start(task, taskList.PathSave()); err != nil {
+		return err
+	}
+
+	if err := log.Save(); err != nil {
+		return err
+	}
+
+	cmd.Println(fmt.Sprintf("計測を開始しました: %v", task.Todo))
+
+	return nil
+}
+
+// Status は "status" コマンドの本体です。
+func (c *Command) Status(cmd *cobra.Command, args []string) error {
+	_, running, err := c.findRunning()
+	if err != nil {
+		return err
+	}
+
+	if running == nil {
+		cmd.Println("計測中のタスクはありません")
+
+		return nil
+	}
+
+	cmd.Println(fmt.Sprintf(
+		"計測中: %v（%v 経過、%v から）",
+		running.Task, timelog.FormatSpent(running.Duration()), running.Start.Format("15:04"),
+	))
+
+	return nil
+}
+
+// Stop は "stop" コマンドの本体です。
+//
+// 作業の記録はタスク・ファイルより先に保存されます。計測したタスクがタスク・フ
+// ァイルから削除されていたり、内容が変更されていた場合は、作業の記録のみ保存し
+// "spent:" タグは更新しません。
+func (c *Command) Stop(cmd *cobra.Command, args []string) error {
+	log, _, err := c.findRunning()
+	if err != nil {
+		return err
+	}
+
+	if log == nil {
+		return errors.New("計測中のタスクはありません")
+	}
+
+	session, err := log.Stop()
+	if err != nil {
+		return err
+	}
+
+	// タスク・ファイルの保存に失敗しても計測が残らないよう、先に記録を保存する
+	if err := log.Save(); err != nil {
+		return err
+	}
+
+	taskList, task := c.findTask(session)
+	if task == nil {
+		cmd.Println(fmt.Sprintf(
+			"計測を終了しました: %v（%v）\n    タスクが見つからないため \"%v:\" タグは更新していません",
+			session.Task, timelog.FormatSpent(session.Duration()), timelog.TagSpent,
+		))
+
+		return nil
+	}
+
+	total, err := timelog.AddSpent(task, session.Duration())
+	if err != nil {
+		return err
+	}
+
+	if err := taskList.OverWrite(c.CUI); err != nil {
+		return errors.Wrapf(err, "計測は終了しましたが \"%v:\" タグを更新できませんでした", timelog.TagSpent)
+	}
+
+	cmd.Println(fmt.Sprintf(
+		"計測を終了しました: %v（%v）\n    合計の作業時間: %v",
+		session.Task, timelog.FormatSpent(session.Duration()), timelog.FormatSpent(total),
+	))
+
+	return nil
+}
+
+// findRunning はローカルおよびグローバルの作業の記録から、計測中のセッションと
+// その記録を返します。計測中のセッションがない場合は nil を返します。
+func (c *Command) findRunning() (*timelog.Log, *timelog.Session, error) {
+	return timelog.FindRunning(c.AppInfo.Tasks.Local.PathSave(), c.AppInfo.Tasks.Global.PathSave())
+}
+
+// findTask は session のタスク・ファイルから、session のタスクを返します。タスク
+// が見つからない場合は nil を返します。
+func (c *Command) findTask(session *timelog.Session) (*todo.Todo, *todotxt.Task) {
+	for _, taskList := range []*todo.Todo{c.AppInfo.Tasks.Local, c.AppInfo.Tasks.Global} {
+		if pathFile, err := filepath.Abs(taskList.PathSave()); err != nil || pathFile != session.File {
+			continue
+		}
+
+		for i := range *taskList.TaskList {
+			if task := &(*taskList.TaskList)[i]; task.Todo == session.Task {
+				return taskList, task
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// setup はコマンドの共通のプロパティをセットします。
+func (c *Command) setup(appInfo *appinfo.AppInfo, runE func(cmd *cobra.Command, args []string) error) *cobra.Command {
+	// Set app info (conf and tasks)
+	c.AppInfo = appInfo
+
+	// Add CUI object
+	c.CUI = cui.New()
+
+	// RunE function
+	c.Command.RunE = runE
+
+	return c.Command
+}
@@ -0,0 +1,125 @@
+package cmdstart_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdstart"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/Qithub-BOT/QiiTask/core/timelog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockClock は clock.TimeNow を 2021-01-06 09:00 の時刻に置き換えます。時刻は戻
+// り値の関数で進めます。
+func mockClock(t *testing.T) (advance func(d time.Duration)) {
+	t.Helper()
+
+	current := time.Date(2021, 1, 6, 9, 0, 0, 0, time.Local)
+
+	testutil.MockTimeNowFunc(t, func() time.Time {
+		return current
+	})
+
+	return func(d time.Duration) {
+		current = current.Add(d)
+	}
+}
+
+func TestNew(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, "")
+
+	assert.Equal(t, "start", cmdstart.New(appInfo).Name())
+	assert.Equal(t, "stop", cmdstart.NewStop(appInfo).Name())
+	assert.Equal(t, "status", cmdstart.NewStatus(appInfo).Name())
+}
+
+func TestStart_stop(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, "write docs spent:1h\nreview\n")
+	advance := mockClock(t)
+
+	out, err := testutil.Execute(cmdroot.New(appInfo), "status")
+	require.NoError(t, err)
+	assert.Contains(t, out, "計測中のタスクはありません")
+
+	out, err = testutil.Execute(cmdroot.New(appInfo), "start", "1")
+	require.NoError(t, err)
+	assert.Contains(t, out, "計測を開始しました: write docs")
+
+	_, err = testutil.Execute(cmdroot.New(appInfo), "start", "2")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "すでに計測中のタスクがあります: write docs")
+
+	advance(40 * time.Minute)
+
+	out, err = testutil.Execute(cmdroot.New(appInfo), "status")
+	require.NoError(t, err)
+	assert.Contains(t, out, "計測中: write docs（40m 経過、09:00 から）")
+
+	advance(40 * time.Minute)
+
+	out, err = testutil.Execute(cmdroot.New(appInfo), "stop")
+	require.NoError(t, err)
+	assert.Contains(t, out, "計測を終了しました: write docs（1h20m）")
+	assert.Contains(t, out, "合計の作業時間: 2h20m")
+
+	assert.Equal(t, "write docs spent:2h20m touched:2021-01-06\nreview\n",
+		testutil.ReadFile(t, appInfo.Tasks.Local.FileUsed()))
+
+	assert.FileExists(t, timelog.PathLog(appInfo.Tasks.Local.FileUsed()))
+
+	_, err = testutil.Execute(cmdroot.New(appInfo), "stop")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "計測中のタスクはありません")
+}
+
+func TestStart_completed_task(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, "x done task\n")
+
+	_, err := testutil.Execute(cmdroot.New(appInfo), "start", "1")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "完了済みのタスクは計測できません")
+}
+
+func TestStop_task_removed(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, "write docs\n")
+	advance := mockClock(t)
+
+	_, err := testutil.Execute(cmdroot.New(appInfo), "start", "1")
+	require.NoError(t, err)
+
+	advance(10 * time.Minute)
+
+	require.NoError(t, appInfo.Tasks.Local.RemoveTaskByID(1))
+
+	out, err := testutil.Execute(cmdroot.New(appInfo), "stop")
+	require.NoError(t, err)
+	assert.Contains(t, out, "タスクが見つからないため")
+}
+
+func TestStop_save_task_failed(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, "write docs\n")
+	advance := mockClock(t)
+
+	_, err := testutil.Execute(cmdroot.New(appInfo), "start", "1")
+	require.NoError(t, err)
+
+	advance(10 * time.Minute)
+
+	// Modify the file behind the scenes to cause a conflict on save
+	pathFile := appInfo.Tasks.Local.FileUsed()
+	require.NoError(t, os.WriteFile(pathFile, []byte("write docs\nreview\n"), 0o600))
+
+	_, err = testutil.Execute(cmdroot.New(appInfo), "stop")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "計測は終了しましたが")
+
+	log, err := timelog.Load(pathFile)
+	require.NoError(t, err)
+	require.Len(t, log.Sessions, 1)
+	assert.NotNil(t, log.Sessions[0].End, "the session should be closed and saved before the task file")
+}
@@ -0,0 +1,329 @@
+/*
+Package cmdstats defines the "stats" command.
+*/
+package cmdstats
+
+import (
+	"fmt"
+
+	"github.com/1set/todotxt"
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/sortlog"
+	"github.com/Qithub-BOT/QiiTask/core/stats"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// widthBar は経過日数の分布を表す棒の最大の文字数です。
+const widthBar = 30
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// printer はセクションの見出し・スパークライン・表を、同じスタイルで順に描画し
+// ます。
+type printer struct {
+	ui        *cui.UI
+	style     cui.TableStyle
+	hasOutput bool // 描画済みのセクションがある場合 true
+}
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo    *appinfo.AppInfo
+	styleTable string // flag for "--style" option
+	days       int    // flag for "--days" option
+	weeks      int    // flag for "--weeks" option
+	isGlobal   bool   // flag for "--global" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "stats" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdStats := new(Command)
+
+	// コマンドの割り当て
+	cmdStats.Command = &cobra.Command{
+		Use:   "stats",
+		Short: "タスクの完了数や並べ替えの統計を表示します",
+		Long: util.HereDoc(`
+				About:
+				  'stats' コマンドは、タスク・ファイル（todo.txt）と、同じディレクト
+				  リの完了済みタスクの保管ファイル（done.txt）のタスクを集計して表示
+				  します。
+
+				    - 概要: タスク数、未完了のタスクの平均経過日数、完了までの平均日数
+				    - 日ごと・週ごとの完了数（スパークライン付き）
+				    - 未完了のタスクの経過日数の分布
+				    - プロジェクト・コンテキストごとのタスク数
+				    - 並べ替え（'sort' と 'tui'）の回数と、質問ごとの使用回数
+
+				  スパークラインは 1 日（1 週）を 1 文字で表し、0 は "_"、期間中の最
+				  大値は "#" になります。
+
+				  '--style' には text, color, markdown, html のいずれかを指定します。
+			`),
+		Example: util.HereDoc(`
+				qiitask stats
+				qiitask stats --days 30 --weeks 12
+				qiitask stats --style markdown > ./stats.md
+				qiitask stats --global
+			`, "  "),
+		Args: cobra.NoArgs,
+	}
+
+	// Set app info (conf and tasks)
+	cmdStats.AppInfo = appInfo
+
+	// RunE function
+	cmdStats.Command.RunE = cmdStats.Stats
+
+	// Define flags for `stats` command.
+	cmdStats.Flags().StringVarP(
+		&cmdStats.styleTable, "style", "s", "text", "表示スタイルを指定します。(text, color, markdown, html)",
+	)
+	cmdStats.Flags().IntVarP(
+		&cmdStats.days, "days", "d", stats.DaysDefault, "日ごとに集計する日数を指定します",
+	)
+	cmdStats.Flags().IntVarP(
+		&cmdStats.weeks, "weeks", "w", stats.WeeksDefault, "週ごとに集計する週数を指定します",
+	)
+	cmdStats.Flags().BoolVarP(
+		&cmdStats.isGlobal, "global", "g", false, "グローバル・タスクを集計します",
+	)
+
+	return cmdStats.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Stats は "stats" コマンドの本体です。
+func (c *Command) Stats(cmd *cobra.Command, args []string) error {
+	if c.days < 1 || c.weeks < 1 {
+		return errors.Errorf("日数と週数は 1 以上を指定してください: %v 日、%v 週", c.days, c.weeks)
+	}
+
+	style, err := tableStyle(c.styleTable)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := c.loadTasks()
+	if err != nil {
+		return err
+	}
+
+	if len(tasks) == 0 {
+		return errors.New("まだタスクはありません")
+	}
+
+	log, err := sortlog.Load(c.getTaskList().PathSave())
+	if err != nil {
+		return err
+	}
+
+	report := stats.New(tasks, log.Sessions, stats.Options{Days: c.days, Weeks: c.weeks})
+
+	ui := cui.New()
+	ui.MirrorIO = cmd.OutOrStdout()
+
+	drawReport(ui, report, style, c.days, c.weeks)
+
+	return nil
+}
+
+func (c *Command) getTaskList() *todo.Todo {
+	taskList := c.AppInfo.Tasks.Local
+
+	if c.isGlobal || taskList.FileUsed() == "" {
+		taskList = c.AppInfo.Tasks.Global
+	}
+
+	return taskList
+}
+
+// loadTasks は対象のタスク・ファイルと、同じディレクトリの done.txt のタスクを
+// 返します。done.txt がない場合は、タスク・ファイルのタスクのみを返します。
+func (c *Command) loadTasks() ([]todotxt.Task, error) {
+	taskList := c.getTaskList()
+	tasks := []todotxt.Task{}
+
+	if taskList.TaskList != nil {
+		tasks = append(tasks, *taskList.TaskList...)
+	}
+
+	done, err := todo.Open(taskList.PathDone())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load done.txt")
+	}
+
+	return append(tasks, *done.TaskList...), nil
+}
+
+// heading はセクションの見出しを描画します。2 つ目以降のセクションの前には空行
+// が入ります。
+func (p *printer) heading(title string) {
+	if p.hasOutput {
+		fmt.Fprintln(p.ui.MirrorIO)
+	}
+
+	p.ui.DrawHeading(title, p.style)
+	p.hasOutput = true
+}
+
+// sparkline は values のスパークラインと最大値を描画します。
+func (p *printer) sparkline(values []int) {
+	line := stats.Sparkline(values)
+
+	// Markdown では "_" や "*" が強調になるため、コードとして表示する
+	if p.style == cui.AsMarkdownTable {
+		line = "`" + line + "`"
+	}
+
+	p.ui.DrawNote(fmt.Sprintf("%v  （最大 %d）", line, maxInt(values)), p.style)
+
+	if p.style == cui.AsMarkdownTable {
+		fmt.Fprintln(p.ui.MirrorIO) // 表と段落を分ける
+	}
+}
+
+// table は表を描画します。rows が空の場合は表の代わりに「なし」を描画します。
+func (p *printer) table(header table.Row, rows []table.Row) {
+	if len(rows) == 0 {
+		p.ui.DrawNote("なし", p.style)
+
+		return
+	}
+
+	tableTmp := table.NewWriter()
+	tableTmp.AppendHeader(header)
+	tableTmp.AppendRows(rows)
+
+	p.ui.DrawTable(tableTmp, p.style) // テーブルの描画
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// drawReport は report の各セクションを style で描画します。
+func drawReport(ui *cui.UI, report *stats.Report, style cui.TableStyle, days, weeks int) {
+	p := &printer{ui: ui, style: style}
+
+	p.heading(fmt.Sprintf("概要（%v）", report.Today.Format(todotxt.DateLayout)))
+	p.table(table.Row{"item", "value"}, []table.Row{
+		{"未完了のタスク", report.Open},
+		{"完了済みのタスク", report.Done},
+		{"未完了のタスクの平均経過日数", fmt.Sprintf("%.1f 日", report.AgeOpen)},
+		{"完了までの平均日数", fmt.Sprintf("%.1f 日", report.LeadTime)},
+	})
+
+	p.heading(fmt.Sprintf("日ごとの完了数（過去 %d 日）", days))
+	p.sparkline(stats.Values(report.DoneByDay))
+	p.table(table.Row{"date", "done"}, rowsCount(report.DoneByDay))
+
+	p.heading(fmt.Sprintf("週ごとの完了数（過去 %d 週）", weeks))
+	p.sparkline(stats.Values(report.DoneByWeek))
+	p.table(table.Row{"week", "done"}, rowsCount(report.DoneByWeek))
+
+	maxAge := maxInt(stats.Values(report.AgeRanges))
+	rows := []table.Row{}
+
+	for _, count := range report.AgeRanges {
+		rows = append(rows, table.Row{count.Name, count.Count, stats.Bar(count.Count, maxAge, widthBar)})
+	}
+
+	p.heading("未完了のタスクの経過日数")
+	p.table(table.Row{"age", "tasks", ""}, rows)
+
+	p.heading("プロジェクトごとのタスク数")
+	p.table(table.Row{"project", "open", "done"}, rowsTally(report.Projects))
+
+	p.heading("コンテキストごとのタスク数")
+	p.table(table.Row{"context", "open", "done"}, rowsTally(report.Contexts))
+
+	p.heading(fmt.Sprintf("並べ替えの回数（過去 %d 日）", days))
+	p.sparkline(stats.Values(report.SortsByDay))
+	p.table(table.Row{"period", "sorts"}, []table.Row{
+		{"過去 7 日", report.SortsRecent[0]},
+		{"過去 30 日", report.SortsRecent[1]},
+		{"全期間", report.Sorts},
+		{"比較の回数（全期間）", report.Comparisons},
+	})
+
+	p.heading("質問ごとの使用回数")
+	p.table(table.Row{"question", "used"}, rowsCount(report.Questions))
+}
+
+// maxInt は values の最大値を返します。values が空の場合は 0 を返します。
+func maxInt(values []int) int {
+	result := 0
+
+	for _, value := range values {
+		if value > result {
+			result = value
+		}
+	}
+
+	return result
+}
+
+// rowsCount は counts を表の行で返します。
+func rowsCount(counts []stats.Count) []table.Row {
+	rows := []table.Row{}
+
+	for _, count := range counts {
+		rows = append(rows, table.Row{count.Name, count.Count})
+	}
+
+	return rows
+}
+
+// rowsTally は tallies を表の行で返します。
+func rowsTally(tallies []stats.Tally) []table.Row {
+	rows := []table.Row{}
+
+	for _, item := range tallies {
+		rows = append(rows, table.Row{item.Name, item.Open, item.Done})
+	}
+
+	return rows
+}
+
+// tableStyle は "--style" オプションの値に対応するテーブルのスタイル ID を返しま
+// す。
+func tableStyle(name string) (cui.TableStyle, error) {
+	switch name {
+	case "text":
+		return cui.AsDefaultTable, nil
+	case "color":
+		return cui.AsColoredTable, nil
+	case "markdown":
+		return cui.AsMarkdownTable, nil
+	case "html":
+		return cui.AsHTMLTable, nil
+	}
+
+	return cui.AsDefaultTable, errors.Errorf("未対応のスタイルです: %v（text, color, markdown, html のいずれかを指定してください）", name)
+}
@@ -0,0 +1,140 @@
+package cmdstats_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdstats"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/sortlog"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/kami-zh/go-capturer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	dataTasks = "2021-01-01 write docs +docs @home\nx 2021-01-06 2021-01-02 review +code\n"
+	dataDone  = "x 2021-01-05 2021-01-04 archived +docs\n"
+)
+
+// runStats はローカルに dataTasks のタスクと dataDone の done.txt を持つアプリ
+// で、今日を 2021-01-06 として "stats" コマンドを実行し、出力とエラーを返します。
+// prepare が nil 以外の場合は、実行前にタスク・ファイルのパスを渡して呼び出しま
+// す。
+func runStats(t *testing.T, prepare func(pathFileTask string), args ...string) (string, error) {
+	t.Helper()
+
+	testutil.MockTimeNow(t, time.Date(2021, 1, 6, 9, 0, 0, 0, time.Local))
+
+	pathDirLocal := t.TempDir()
+	pathFileTask := filepath.Join(pathDirLocal, todo.NameFile)
+
+	require.NoError(t, os.WriteFile(pathFileTask, []byte(dataTasks), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, todo.NameFileDone), []byte(dataDone), 0o600))
+
+	if prepare != nil {
+		prepare(pathFileTask)
+	}
+
+	appInfo, err := appinfo.New(pathDirLocal, t.TempDir(), "")
+	require.NoError(t, err)
+
+	app := cmdroot.New(appInfo)
+	app.SetArgs(append([]string{"stats"}, args...))
+
+	var errRun error
+
+	out := capturer.CaptureOutput(func() {
+		errRun = app.Execute()
+	})
+
+	return out, errRun
+}
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj1 := cmdstats.New(appInfo)
+	obj2 := cmdstats.New(appInfo)
+
+	assert.NotSame(t, obj1, obj2, "it should not reference the same object")
+	assert.Equal(t, "stats", obj1.Name())
+}
+
+func TestStats_text(t *testing.T) {
+	out, err := runStats(t, func(pathFileTask string) {
+		require.NoError(t, sortlog.Record(pathFileTask, map[string]int{"どちらが重要ですか？": 3}))
+	}, "--days", "3", "--weeks", "2")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(out, "■ 概要（2021-01-06）\n"), "it should not begin with an empty line")
+
+	for _, expect := range []string{
+		"| 完了済みのタスク             | 2      |", // done.txt のタスクも含む
+		"| 未完了のタスクの平均経過日数 | 5.0 日 |",
+		"| 完了までの平均日数           | 2.5 日 |",
+		"■ 日ごとの完了数（過去 3 日）\n  _##  （最大 1）\n",
+		"■ 週ごとの完了数（過去 2 週）\n  _#  （最大 2）\n",
+		"| 0〜6 日    |     1 | ",
+		"| docs    |    1 |    1 |",
+		"| home    |    1 |    0 |",
+		"■ 並べ替えの回数（過去 3 日）\n  __#  （最大 1）\n",
+		"| 比較の回数（全期間） |     3 |",
+		"| どちらが重要ですか？ |    3 |",
+	} {
+		assert.Contains(t, out, expect)
+	}
+}
+
+func TestStats_markdown(t *testing.T) {
+	out, err := runStats(t, nil, "--style", "markdown", "--days", "2")
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "## 日ごとの完了数（過去 2 日）\n\n`##`  （最大 1）\n\n| date | done |")
+	assert.Contains(t, out, "## 質問ごとの使用回数\n\nなし\n")
+}
+
+func TestStats_html(t *testing.T) {
+	out, err := runStats(t, nil, "--style", "html")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(out, "<h2>概要（2021-01-06）</h2>\n<table"))
+	assert.Contains(t, out, "<p>____________##  （最大 1）</p>")
+}
+
+func TestStats_errors(t *testing.T) {
+	for _, test := range []struct {
+		args   []string
+		expect string
+	}{
+		{[]string{"--style", "csv"}, "未対応のスタイルです: csv"},
+		{[]string{"--days", "0"}, "日数と週数は 1 以上を指定してください: 0 日、8 週"},
+		{[]string{"--weeks", "-1"}, "日数と週数は 1 以上を指定してください: 14 日、-1 週"},
+	} {
+		out, err := runStats(t, nil, test.args...)
+
+		require.Error(t, err, "args: %v", test.args)
+		assert.Contains(t, out, test.expect, "args: %v", test.args)
+	}
+}
+
+func TestStats_no_tasks(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	app := cmdroot.New(appInfo)
+	app.SetArgs([]string{"stats"})
+
+	out := capturer.CaptureOutput(func() {
+		require.Error(t, app.Execute())
+	})
+
+	assert.Contains(t, out, "まだタスクはありません")
+}
@@ -0,0 +1,620 @@
+package cmdtui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/clock"
+	"github.com/Qithub-BOT/QiiTask/core/query"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/Qithub-BOT/QiiTask/core/tui"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// 画面のモード。
+const (
+	modeList   = iota // 一覧
+	modeFilter        // 絞り込みの入力
+	modeEdit          // タスクの編集
+	modeAdd           // タスクの追加
+	modeQuit          // 終了時の保存の確認
+)
+
+// 一覧以外に使う行数（ヘッダー、絞り込み、区切り線、ステータス、ヘルプ）。
+const numLinesFrame = 5
+
+// queryDefault は設定ファイルに質問集がない場合に使う質問集のキーです。
+const queryDefault = "task"
+
+const (
+	helpList = "↑↓ 移動  / 絞り込み  e 編集  n 追加  space 完了  s 並べ替え  q 終了"
+	helpEdit = "enter 確定  esc 取り消し  ←→ 移動  home/end 先頭/末尾"
+	helpSort = "←/h/1 左を優先  →/l/2 右を優先  tab 質問を変える  esc 中止"
+	msgQuit  = "変更を保存しますか？ (y: 保存して終了 / n: 保存せずに終了 / esc: 戻る)"
+)
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// app は全画面表示の状態です。
+type app struct {
+	screen    *tui.Screen
+	taskList  *todo.Todo
+	style     table.Style // 色付きの場合は style.Color を使う
+	isColored bool
+	questions []string         // 並べ替えに使う質問
+	mode      int              // 画面のモード
+	filter    *tui.Input       // 絞り込みの入力欄
+	edit      *tui.Input       // 編集・追加の入力欄
+	cursor    int              // 選択中の行（絞り込み後の行の位置）
+	offset    int              // 一覧の先頭に表示している行
+	message   string           // ステータス行のメッセージ
+	isDirty   bool             // タスクを変更した場合 true
+	sorts     []map[string]int // 並べ替えごとの、回答に使われた質問ごとの回数
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// newApp は taskList を screen に表示する app の新規オブジェクトを返します。
+// questions が空の場合はデフォルトの質問集の質問を使います。
+func newApp(screen *tui.Screen, taskList *todo.Todo, questions []string, style table.Style) *app {
+	result := &app{
+		screen:    screen,
+		taskList:  taskList,
+		style:     style,
+		isColored: hasColors(style.Color.Header),
+		questions: []string{},
+		filter:    tui.NewInput(""),
+	}
+
+	for _, question := range questions {
+		if strings.TrimSpace(question) != "" {
+			result.questions = append(result.questions, question)
+		}
+	}
+
+	if len(result.questions) == 0 {
+		if queryTask, err := query.New(queryDefault); err == nil {
+			result.questions = append(result.questions, queryTask.Objective...)
+		}
+	}
+
+	if len(result.questions) == 0 {
+		result.questions = []string{"どちらのタスクを先に行いますか？"}
+	}
+
+	if taskList.TaskList == nil {
+		list := todotxt.NewTaskList()
+		taskList.TaskList = &list
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// run は終了するまでキー入力を処理して画面を描画します。保存して終了する場合は
+// true を返します。
+func (a *app) run() (bool, error) {
+	for {
+		if err := a.screen.Draw(a.viewList()); err != nil {
+			return false, err
+		}
+
+		key, err := a.screen.ReadKey()
+		if err != nil {
+			return false, err
+		}
+
+		if key.Code == tui.KeyEOF || key.Code == tui.KeyCtrlC {
+			return false, nil
+		}
+
+		if isQuit, isSave := a.handleKey(key); isQuit {
+			return isSave, nil
+		}
+	}
+}
+
+// handleKey はモードに応じて key を処理します。終了する場合は isQuit が true、
+// さらに保存する場合は isSave が true になります。
+func (a *app) handleKey(key tui.Key) (isQuit bool, isSave bool) {
+	switch a.mode {
+	case modeFilter:
+		a.handleFilter(key)
+	case modeEdit, modeAdd:
+		a.handleEdit(key)
+	case modeQuit:
+		switch {
+		case key.Code == tui.KeyRune && (key.Rune == 'y' || key.Rune == 'Y'):
+			return true, true
+		case key.Code == tui.KeyRune && (key.Rune == 'n' || key.Rune == 'N'):
+			return true, false
+		case key.Code == tui.KeyEsc:
+			a.mode = modeList
+			a.message = ""
+		}
+	default:
+		return a.handleList(key)
+	}
+
+	return false, false
+}
+
+// handleEdit は編集・追加のモードで key を処理します。
+func (a *app) handleEdit(key tui.Key) {
+	switch key.Code {
+	case tui.KeyEsc:
+		a.mode = modeList
+		a.message = "編集を取り消しました"
+	case tui.KeyEnter:
+		a.saveEdit()
+	default:
+		a.edit.HandleKey(key)
+		a.message = ""
+	}
+}
+
+// handleFilter は絞り込みの入力のモードで key を処理します。
+func (a *app) handleFilter(key tui.Key) {
+	switch key.Code {
+	case tui.KeyEsc:
+		a.filter = tui.NewInput("")
+		a.mode = modeList
+	case tui.KeyEnter:
+		a.mode = modeList
+	default:
+		a.filter.HandleKey(key)
+	}
+
+	a.cursor, a.offset = 0, 0
+}
+
+// handleList は一覧のモードで key を処理します。
+func (a *app) handleList(key tui.Key) (isQuit bool, isSave bool) {
+	a.message = ""
+	rows := a.numRows()
+
+	switch key.Code {
+	case tui.KeyUp:
+		a.moveCursor(-1)
+	case tui.KeyDown:
+		a.moveCursor(1)
+	case tui.KeyPgUp:
+		a.moveCursor(-rows)
+	case tui.KeyPgDn:
+		a.moveCursor(rows)
+	case tui.KeyHome:
+		a.moveCursor(-len(*a.taskList.TaskList))
+	case tui.KeyEnd:
+		a.moveCursor(len(*a.taskList.TaskList))
+	case tui.KeyEsc:
+		a.filter = tui.NewInput("")
+		a.cursor, a.offset = 0, 0
+	case tui.KeyEnter:
+		a.startEdit()
+	case tui.KeyRune:
+		return a.handleListRune(key.Rune)
+	}
+
+	return false, false
+}
+
+// handleListRune は一覧のモードで文字のキーを処理します。
+func (a *app) handleListRune(r rune) (isQuit bool, isSave bool) {
+	switch r {
+	case 'k':
+		a.moveCursor(-1)
+	case 'j':
+		a.moveCursor(1)
+	case 'g':
+		a.moveCursor(-len(*a.taskList.TaskList))
+	case 'G':
+		a.moveCursor(len(*a.taskList.TaskList))
+	case '/':
+		a.mode = modeFilter
+	case 'e':
+		a.startEdit()
+	case 'n':
+		a.edit = tui.NewInput("")
+		a.mode = modeAdd
+	case ' ', 'x':
+		a.toggleDone()
+	case 's':
+		a.sort()
+	case 'q':
+		if !a.isDirty {
+			return true, false
+		}
+
+		a.mode = modeQuit
+		a.message = msgQuit
+	}
+
+	return false, false
+}
+
+// moveCursor はカーソルを delta 行移動します。
+func (a *app) moveCursor(delta int) {
+	a.cursor += delta
+
+	if last := len(a.visible()) - 1; a.cursor > last {
+		a.cursor = last
+	}
+
+	if a.cursor < 0 {
+		a.cursor = 0
+	}
+}
+
+// numRows は一覧に表示できる行数を返します。
+func (a *app) numRows() int {
+	if rows := a.screen.Height - numLinesFrame; rows > 0 {
+		return rows
+	}
+
+	return 1
+}
+
+// saveEdit は入力欄の内容で、選択中のタスクを更新もしくはタスクを追加します。
+func (a *app) saveEdit() {
+	line := strings.TrimSpace(a.edit.String())
+	if line == "" {
+		a.message = "タスクが空です（esc で取り消し）"
+
+		return
+	}
+
+	task, err := todotxt.ParseTask(line)
+	if err != nil {
+		a.message = fmt.Sprintf("タスクに変換できません: %v", err)
+
+		return
+	}
+
+	if a.mode == modeAdd {
+		a.taskList.AddTask(task)
+		a.message = fmt.Sprintf("タスクを追加しました（#%d）", task.ID)
+	} else {
+		current := a.selected()
+		if current == nil {
+			return
+		}
+
+		task.ID = current.ID
+		*current = *task
+		a.message = fmt.Sprintf("タスクを更新しました（#%d）", task.ID)
+	}
+
+	a.isDirty = true
+	a.mode = modeList
+}
+
+// selected は選択中のタスクを返します。表示中のタスクがない場合は nil を返しま
+// す。
+func (a *app) selected() *todotxt.Task {
+	indexes := a.visible()
+
+	if a.cursor < 0 || a.cursor >= len(indexes) {
+		return nil
+	}
+
+	return &(*a.taskList.TaskList)[indexes[a.cursor]]
+}
+
+// startEdit は選択中のタスクの編集を開始します。
+func (a *app) startEdit() {
+	task := a.selected()
+	if task == nil {
+		return
+	}
+
+	a.edit = tui.NewInput(task.String())
+	a.mode = modeEdit
+}
+
+// toggleDone は選択中のタスクの完了・未完了を切り替えます。
+func (a *app) toggleDone() {
+	task := a.selected()
+	if task == nil {
+		return
+	}
+
+	if task.Completed {
+		task.Reopen()
+
+		a.message = fmt.Sprintf("未完了に戻しました（#%d）", task.ID)
+	} else {
+		task.Complete()
+
+		a.message = fmt.Sprintf("完了にしました（#%d）", task.ID)
+	}
+
+	a.isDirty = true
+}
+
+// visible は絞り込みに一致するタスクの、タスク一覧内の位置を返します。
+func (a *app) visible() []int {
+	result := []int{}
+	keyword := todo.NormalizeText(a.filter.String())
+
+	for i, task := range *a.taskList.TaskList {
+		if keyword == "" || strings.Contains(todo.NormalizeText(task.String()), keyword) {
+			result = append(result, i)
+		}
+	}
+
+	return result
+}
+
+// askIsALessThanB は x と y を左右に並べて表示し、どちらを優先するかをキー入力
+// で問い合わせます。x を優先する場合は true を返します。中止された場合は
+// isAbort が true になります。回答に使われた質問は used で数えます。
+func (a *app) askIsALessThanB(x, y *todotxt.Task, count int, used map[string]int) (result bool, isAbort bool) {
+	indexQ := 0
+
+	for {
+		if err := a.screen.Draw(a.viewSort(x, y, a.questions[indexQ], count)); err != nil {
+			return false, true
+		}
+
+		key, err := a.screen.ReadKey()
+		if err != nil {
+			return false, true
+		}
+
+		switch {
+		case key.Code == tui.KeyLeft || key.Code == tui.KeyRune && (key.Rune == 'h' || key.Rune == '1'):
+			used[a.questions[indexQ]]++
+
+			return true, false
+		case key.Code == tui.KeyRight || key.Code == tui.KeyRune && (key.Rune == 'l' || key.Rune == '2'):
+			used[a.questions[indexQ]]++
+
+			return false, false
+		case key.Code == tui.KeyTab || key.Code == tui.KeyRune && key.Rune == '?':
+			indexQ = (indexQ + 1) % len(a.questions)
+		case key.Code == tui.KeyEsc || key.Code == tui.KeyCtrlC || key.Code == tui.KeyEOF ||
+			key.Code == tui.KeyRune && key.Rune == 'q':
+			return false, true
+		}
+	}
+}
+
+// sort は未完了のタスクを 2 つずつ比較して並べ替えます。（'sort' コマンドと同じ
+// く、親子関係と依存関係を考慮します）中止した場合やエラーの場合は、並べ替え前
+// の順序に戻します。
+func (a *app) sort() {
+	backup := append(todotxt.TaskList{}, *a.taskList.TaskList...)
+	count := 0
+	used := map[string]int{}
+	isAborted := false
+
+	err := a.taskList.SortTree(func(x, y *todotxt.Task) bool {
+		switch {
+		case x.Completed && y.Completed:
+			return true
+		case x.Completed:
+			return false
+		case y.Completed:
+			return true
+		case isAborted:
+			return true
+		}
+
+		count++
+
+		result, isAbort := a.askIsALessThanB(x, y, count, used)
+		if isAbort {
+			isAborted = true
+		}
+
+		return result
+	})
+
+	switch {
+	case err != nil:
+		*a.taskList.TaskList = backup
+		a.message = fmt.Sprintf("並べ替えできません: %v", err)
+	case isAborted:
+		*a.taskList.TaskList = backup
+		a.message = "並べ替えを中止しました"
+	default:
+		for i := range backup {
+			if backup[i].ID != (*a.taskList.TaskList)[i].ID {
+				a.isDirty = true
+
+				break
+			}
+		}
+
+		a.message = fmt.Sprintf("並べ替えました（比較 %d 回）", count)
+
+		// 'stats' コマンドで集計するための記録。保存した場合のみ記録する
+		a.sorts = append(a.sorts, used)
+	}
+
+	a.cursor, a.offset = 0, 0
+}
+
+// header は画面幅のヘッダー行を返します。
+func (a *app) header(title string) string {
+	line := tui.Fit(title, a.screen.Width)
+
+	if a.isColored {
+		return a.style.Color.Header.Sprint(line)
+	}
+
+	return tui.Reverse(line)
+}
+
+// row は一覧の i 行目を、選択中かどうかと色付きの設定に応じて返します。
+func (a *app) row(i int, line string, isSelected bool) string {
+	line = tui.Fit(line, a.screen.Width)
+
+	switch {
+	case isSelected:
+		return tui.Reverse(line)
+	case !a.isColored:
+		return line
+	case i%2 == 1:
+		return a.style.Color.RowAlternate.Sprint(line)
+	}
+
+	return a.style.Color.Row.Sprint(line)
+}
+
+// viewList は一覧の画面の行を返します。
+func (a *app) viewList() []string {
+	width := a.screen.Width
+	indexes := a.visible()
+	tasks := *a.taskList.TaskList
+	numDone := 0
+
+	for _, task := range tasks {
+		if task.Completed {
+			numDone++
+		}
+	}
+
+	lines := []string{
+		a.header(fmt.Sprintf(" QiiTask  %v  （%d 件中 %d 件完了）", a.taskList.PathSave(), len(tasks), numDone)),
+	}
+
+	// 絞り込み
+	labelFilter := "絞り込み: "
+
+	switch {
+	case a.mode == modeFilter:
+		lines = append(lines, labelFilter+a.filter.View(width-tui.Width(labelFilter)))
+	case a.filter.String() != "":
+		lines = append(lines, tui.Fit(fmt.Sprintf("%v%v（%d 件）", labelFilter, a.filter, len(indexes)), width))
+	default:
+		lines = append(lines, tui.Fit(labelFilter+"（/ で入力）", width))
+	}
+
+	lines = append(lines, strings.Repeat("-", width))
+
+	// 一覧（カーソルが見えるようにスクロールする）
+	rows := a.numRows()
+
+	if a.cursor < a.offset {
+		a.offset = a.cursor
+	}
+
+	if a.cursor >= a.offset+rows {
+		a.offset = a.cursor - rows + 1
+	}
+
+	for i := a.offset; i < a.offset+rows; i++ {
+		switch {
+		case i < len(indexes):
+			task := tasks[indexes[i]]
+
+			lines = append(lines, a.row(i, fmt.Sprintf("%4d  %v", task.ID, task.String()), i == a.cursor))
+		case i == 0 && len(tasks) == 0:
+			lines = append(lines, tui.Fit("  （タスクがありません。n で追加できます）", width))
+		case i == 0:
+			lines = append(lines, tui.Fit("  （絞り込みに一致するタスクがありません）", width))
+		default:
+			lines = append(lines, "")
+		}
+	}
+
+	// ステータス
+	switch a.mode {
+	case modeEdit:
+		lines = append(lines, "編集: "+a.edit.View(width-tui.Width("編集: ")))
+	case modeAdd:
+		lines = append(lines, "追加: "+a.edit.View(width-tui.Width("追加: ")))
+	default:
+		lines = append(lines, tui.Fit(a.message, width))
+	}
+
+	// ヘルプ（入力中は、メッセージがあればメッセージを表示）
+	switch {
+	case a.mode != modeEdit && a.mode != modeAdd:
+		return append(lines, tui.Fit(helpList, width))
+	case a.message != "":
+		return append(lines, tui.Fit(a.message, width))
+	}
+
+	return append(lines, tui.Fit(helpEdit, width))
+}
+
+// viewSort は x と y を左右に並べた並べ替えの画面の行を返します。
+func (a *app) viewSort(x, y *todotxt.Task, question string, count int) []string {
+	width := a.screen.Width
+	widthColumn := (width - 3) / 2
+
+	lines := []string{
+		a.header(fmt.Sprintf(" 並べ替え（比較 %d 回目）", count)),
+		"",
+		tui.Fit(" "+question, width),
+		"",
+		tui.Fit("← 左", widthColumn) + " | " + tui.Fit("右 →", widthColumn),
+		strings.Repeat("-", widthColumn) + "-+-" + strings.Repeat("-", widthColumn),
+	}
+
+	left := tui.Wrap(x.String(), widthColumn)
+	right := tui.Wrap(y.String(), widthColumn)
+
+	for i := 0; i < len(left) || i < len(right); i++ {
+		cellLeft, cellRight := "", ""
+
+		if i < len(left) {
+			cellLeft = left[i]
+		}
+
+		if i < len(right) {
+			cellRight = right[i]
+		}
+
+		lines = append(lines, tui.Fit(cellLeft, widthColumn)+" | "+tui.Fit(cellRight, widthColumn))
+	}
+
+	// 最後に手を付けてからの日数
+	today := clock.TimeNow()
+	ageLeft, ageRight := todo.FormatAge(x, today), todo.FormatAge(y, today)
+
+	if ageLeft != "" || ageRight != "" {
+		if ageLeft != "" {
+			ageLeft = "最終更新 " + ageLeft
+		}
+
+		if ageRight != "" {
+			ageRight = "最終更新 " + ageRight
+		}
+
+		lines = append(lines, tui.Fit(ageLeft, widthColumn)+" | "+tui.Fit(ageRight, widthColumn))
+	}
+
+	lines = append(lines, "")
+
+	// ヘルプは最終行に表示する
+	for len(lines) < a.screen.Height-1 {
+		lines = append(lines, "")
+	}
+
+	return append(lines, tui.Fit(helpSort, width))
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// hasColors は colors に色が指定されている場合に true を返します。
+func hasColors(colors text.Colors) bool {
+	return len(colors) > 0
+}
@@ -0,0 +1,169 @@
+/*
+Package cmdtui defines the "tui" command.
+*/
+package cmdtui
+
+import (
+	"fmt"
+
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/sortlog"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/Qithub-BOT/QiiTask/core/tui"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// OpenScreen は全画面表示に使う画面を返します。テスト時にキー入力と描画先をモッ
+// クする為に変数に代入しています。
+var OpenScreen = func() (*tui.Screen, error) {
+	return tui.Open(cui.OsStdin, cui.OsStdout)
+}
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo    *appinfo.AppInfo
+	CUI        *cui.UI
+	styleTable string // flag for "--style" option
+	isGlobal   bool   // flag for "--global" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "tui" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdTUI := new(Command)
+
+	// コマンドの割り当て
+	cmdTUI.Command = &cobra.Command{
+		Use:   "tui",
+		Short: "全画面でタスクを閲覧・編集・並べ替えします",
+		Long: util.HereDoc(`
+				About:
+				  'tui' コマンドは、タスク一覧を全画面で表示し、キー操作でタスクの絞
+				  り込み・編集・追加・完了の切り替え・並べ替えを行います。
+
+				  キー操作（一覧）:
+				    ↑ ↓ / k j     カーソルの移動（PgUp, PgDn, Home, End も可）
+				    /             絞り込み（Enter で確定、Esc で解除）
+				    e / Enter     選択中のタスクを編集（todo.txt 形式）
+				    n             タスクを追加
+				    space / x     完了・未完了の切り替え
+				    s             左右に並べた 2 つのタスクを比較して並べ替え
+				    q             終了（変更がある場合は保存を確認）
+				    ctrl+c        保存せずに終了
+
+				  並べ替えでは、設定ファイルの質問集（客観的な質問）を使います。
+				  ← / h / 1 で左、→ / l / 2 で右のタスクを優先します。Tab で質問を変
+				  え、Esc で並べ替えを中止します。（タスクは並べ替え前に戻ります）
+
+				  変更は終了時にまとめて保存されます。'--style color' を指定すると、
+				  'list --style color' と同じ色で表示します。
+			`),
+		Example: util.HereDoc(`
+				qiitask tui
+				qiitask tui --style color
+				qiitask tui --global
+			`, "  "),
+		Args: cobra.NoArgs,
+	}
+
+	// Set app info (conf and tasks)
+	cmdTUI.AppInfo = appInfo
+
+	// Add CUI object
+	cmdTUI.CUI = cui.New()
+
+	// RunE function
+	cmdTUI.Command.RunE = cmdTUI.TUI
+
+	// Define flags for `tui` command.
+	cmdTUI.Flags().StringVarP(
+		&cmdTUI.styleTable, "style", "s", "text", "表示スタイルを指定します。(text, color)",
+	)
+	cmdTUI.Flags().BoolVarP(
+		&cmdTUI.isGlobal, "global", "g", false, "グローバル・タスクを表示します",
+	)
+
+	return cmdTUI.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// TUI は "tui" コマンドの本体です。
+func (c *Command) TUI(cmd *cobra.Command, args []string) error {
+	taskList := c.getTaskList()
+
+	screen, err := OpenScreen()
+	if err != nil {
+		return err
+	}
+
+	style := cui.AsDefaultTable
+	if c.styleTable == "color" {
+		style = cui.AsColoredTable
+	}
+
+	view := newApp(screen, taskList, c.AppInfo.Config.GetQueryObjective(), c.CUI.StyleTable(style))
+	isSave, errRun := view.run()
+
+	if err := screen.Close(); err != nil {
+		return err
+	}
+
+	if errRun != nil {
+		return errRun
+	}
+
+	if !view.isDirty {
+		return nil
+	}
+
+	if !isSave {
+		cmd.Println("変更を保存せずに終了しました")
+
+		return nil
+	}
+
+	if err := taskList.OverWrite(c.CUI); err != nil {
+		return errors.Wrap(err, "failed to save tasks")
+	}
+
+	// 'stats' コマンドで集計するための記録
+	for _, used := range view.sorts {
+		if err := sortlog.Record(taskList.PathSave(), used); err != nil {
+			return err
+		}
+	}
+
+	cmd.Println(fmt.Sprintf("タスクを保存しました: %v", taskList.PathSave()))
+
+	return nil
+}
+
+func (c *Command) getTaskList() *todo.Todo {
+	taskList := c.AppInfo.Tasks.Local
+
+	if c.isGlobal || taskList.FileUsed() == "" {
+		taskList = c.AppInfo.Tasks.Global
+	}
+
+	return taskList
+}
@@ -0,0 +1,181 @@
+package cmdtui_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdtui"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/sortlog"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/Qithub-BOT/QiiTask/core/tui"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runTUI は appInfo のアプリで、keys をキー入力として "tui" コマンドを実行しま
+// す。画面の出力、コマンドの出力、実行後のローカルのタスク・ファイルの内容とエ
+// ラーを返します。
+func runTUI(t *testing.T, appInfo *appinfo.AppInfo, keys string, args ...string) (string, string, string, error) {
+	t.Helper()
+
+	oldOpenScreen := cmdtui.OpenScreen
+	defer func() {
+		cmdtui.OpenScreen = oldOpenScreen
+	}()
+
+	var screen bytes.Buffer
+
+	cmdtui.OpenScreen = func() (*tui.Screen, error) {
+		return tui.New(strings.NewReader(keys), &screen, 80, 12), nil
+	}
+
+	out, err := testutil.Execute(cmdroot.New(appInfo), append([]string{"tui"}, args...)...)
+
+	return screen.String(), out, testutil.ReadFile(t, appInfo.Tasks.Local.FileUsed()), err
+}
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj1 := cmdtui.New(appInfo)
+	obj2 := cmdtui.New(appInfo)
+
+	assert.NotSame(t, obj1, obj2, "it should not reference the same object")
+	assert.Equal(t, "tui", obj1.Name())
+}
+
+func TestTUI_toggle_edit_add(t *testing.T) {
+	keys := "jx" + // 2 つ目を完了にする
+		"ke +docs\r" + // 1 つ目を編集する
+		"nthird\r" + // 追加する
+		"qy" // 保存して終了する
+
+	screen, out, data, err := runTUI(t, testutil.NewAppInfo(t, "(A) first\nsecond\n"), keys)
+
+	require.NoError(t, err)
+	assert.Contains(t, screen, "(A) first")
+	assert.Contains(t, screen, "タスクを追加しました（#3）")
+	assert.Contains(t, screen, "変更を保存しますか？")
+	assert.Contains(t, out, "タスクを保存しました")
+	assert.Equal(t, "(A) first +docs touched:2021-01-06\nx "+time.Now().Format("2006-01-02")+" second touched:2021-01-06\nthird touched:2021-01-06\n", data)
+}
+
+func TestTUI_filter(t *testing.T) {
+	screen, _, data, err := runTUI(t, testutil.NewAppInfo(t, "write docs\nreview code\n"), "/REV\rxqy")
+
+	require.NoError(t, err)
+	assert.Contains(t, screen, "絞り込み: REV（1 件）")
+	assert.Equal(t, "write docs\nx "+time.Now().Format("2006-01-02")+" review code touched:2021-01-06\n", data,
+		"it should toggle the task selected in the filtered list")
+}
+
+func TestTUI_filter_no_match(t *testing.T) {
+	screen, _, data, err := runTUI(t, testutil.NewAppInfo(t, "write docs\n"), "/zzz\rxq")
+
+	require.NoError(t, err)
+	assert.Contains(t, screen, "（絞り込みに一致するタスクがありません）")
+	assert.Equal(t, "write docs\n", data)
+}
+
+func TestTUI_sort(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, "first\n2021-01-01 second\n")
+
+	screen, _, data, err := runTUI(t, appInfo, "s1qy")
+
+	require.NoError(t, err)
+	assert.Contains(t, screen, "並べ替え（比較 1 回目）")
+	assert.Contains(t, screen, "最終更新 5 日前", "it should show the age of the task")
+	assert.Contains(t, screen, "並べ替えました（比較 1 回）")
+	assert.Equal(t, "2021-01-01 second\nfirst\n", data, "it should put the left task (the latter one) first")
+
+	log, err := sortlog.Load(appInfo.Tasks.Local.FileUsed())
+	require.NoError(t, err)
+	require.Len(t, log.Sessions, 1, "it should record the sort for the \"stats\" command")
+
+	assert.Equal(t, 1, log.Sessions[0].Comparisons)
+}
+
+func TestTUI_sort_quit_without_saving(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, "first\nsecond\n")
+
+	_, out, data, err := runTUI(t, appInfo, "s1qn")
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "変更を保存せずに終了しました")
+	assert.Equal(t, "first\nsecond\n", data)
+	assert.NoFileExists(t, sortlog.PathLog(appInfo.Tasks.Local.FileUsed()),
+		"it should not record the sort that was not saved")
+}
+
+func TestTUI_sort_abort(t *testing.T) {
+	screen, out, data, err := runTUI(t, testutil.NewAppInfo(t, "first\nsecond\n"), "s\x1bq")
+
+	require.NoError(t, err)
+	assert.Contains(t, screen, "並べ替えを中止しました")
+	assert.Empty(t, out, "it should quit without asking if nothing was changed")
+	assert.Equal(t, "first\nsecond\n", data)
+}
+
+func TestTUI_quit_without_saving(t *testing.T) {
+	for _, keys := range []string{
+		"xqn",     // 保存せずに終了
+		"xq\x1bn", // 確認を取り消した後、'n' は無視され入力の終わりで終了
+		"x\x03",   // ctrl+c
+	} {
+		_, out, data, err := runTUI(t, testutil.NewAppInfo(t, "first\n"), keys)
+
+		require.NoError(t, err, "keys: %q", keys)
+		assert.Contains(t, out, "変更を保存せずに終了しました", "keys: %q", keys)
+		assert.Equal(t, "first\n", data, "keys: %q", keys)
+	}
+}
+
+func TestTUI_edit_cancel_and_errors(t *testing.T) {
+	keys := "e\x7f\x7f\x7f\x7f\x7f\r" + // 空のタスク
+		"\x1b" + // 編集の取り消し
+		"q"
+
+	screen, _, data, err := runTUI(t, testutil.NewAppInfo(t, "first\n"), keys)
+
+	require.NoError(t, err)
+	assert.Contains(t, screen, "タスクが空です（esc で取り消し）")
+	assert.Contains(t, screen, "編集を取り消しました")
+	assert.Equal(t, "first\n", data)
+}
+
+func TestTUI_style_color(t *testing.T) {
+	screen, _, _, err := runTUI(t, testutil.NewAppInfo(t, "first\nsecond\nthird\n"), "q", "--style", "color")
+
+	require.NoError(t, err)
+	assert.Contains(t, screen, text.Colors{text.BgBlue, text.FgWhite}.EscapeSeq(),
+		"it should use the header color of the colored table")
+	assert.Contains(t, screen, text.Colors{text.BgHiBlack, text.FgWhite}.EscapeSeq(),
+		"it should use the alternate row color of the colored table")
+}
+
+func TestTUI_open_error(t *testing.T) {
+	oldOpenScreen := cmdtui.OpenScreen
+	defer func() {
+		cmdtui.OpenScreen = oldOpenScreen
+	}()
+
+	cmdtui.OpenScreen = func() (*tui.Screen, error) {
+		return nil, errors.New("forced error")
+	}
+
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	out, err := testutil.Execute(cmdroot.New(appInfo), "tui")
+
+	require.Error(t, err)
+
+	assert.Contains(t, out, "forced error")
+}
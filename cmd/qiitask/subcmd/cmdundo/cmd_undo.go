@@ -0,0 +1,99 @@
+/*
+Package cmdundo defines the "undo" command.
+*/
+package cmdundo
+
+import (
+	"fmt"
+
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/journal"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo  *appinfo.AppInfo
+	isGlobal bool // flag for "--global" option
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "undo" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdUndo := new(Command)
+
+	// コマンドの割り当て
+	cmdUndo.Command = &cobra.Command{
+		Use:   "undo",
+		Short: "直前の変更を取り消します",
+		Long: util.HereDoc(`
+				About:
+				  'undo' コマンドは、変更履歴（ジャーナル）をもとに、タスクや設定ファイル
+				  への直前の変更を取り消します。取り消した変更は 'redo' コマンドでやり
+				  直せます。変更履歴は 'log' コマンドで確認できます。
+
+				  取り消すのは対象のタスク・ファイル（'--global' の場合はグローバル・
+				  タスク）への変更のみです。同じ変更履歴に記録された、他のファイル
+				  （設定ファイルなど）への変更は取り消しません。
+			`),
+		Example: util.HereDoc(`
+				qiitask undo
+				qiitask undo --global // グローバル・タスクの変更を取り消します
+			`, "  "),
+		Args: cobra.NoArgs,
+	}
+
+	// Set app info (conf and tasks)
+	cmdUndo.AppInfo = appInfo
+
+	// RunE function
+	cmdUndo.Command.RunE = cmdUndo.Undo
+
+	// Define flags for `undo` command.
+	cmdUndo.Flags().BoolVarP(
+		&cmdUndo.isGlobal, "global", "g", false, "グローバル・タスクの変更を取り消します",
+	)
+
+	return cmdUndo.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Undo は "undo" コマンドの本体です。
+func (c *Command) Undo(cmd *cobra.Command, args []string) error {
+	pathFile := c.AppInfo.Tasks.Target(c.isGlobal).FileUsed()
+	if pathFile == "" {
+		return errors.New("変更履歴はありません")
+	}
+
+	history, err := journal.Load(pathFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to load journal")
+	}
+
+	entry, err := history.Undo(pathFile, c.AppInfo.Config.GetInt("backup_count"))
+	if err != nil {
+		return err
+	}
+
+	cmd.Println(fmt.Sprintf(
+		"変更を取り消しました: %v %v\n    %v",
+		entry.Time.Format("2006-01-02 15:04:05"), entry.Operation, entry.File,
+	))
+
+	return nil
+}
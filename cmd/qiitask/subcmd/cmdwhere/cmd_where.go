@@ -0,0 +1,106 @@
+/*
+Package cmdwhere defines the "where" command.
+*/
+package cmdwhere
+
+import (
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/workspace"
+	"github.com/spf13/cobra"
+)
+
+// ----------------------------------------------------------------------------
+//  Commnad Struct
+// ----------------------------------------------------------------------------
+
+// Command は cobra.Command 型の拡張型です。cobra.Command に加えフラグの設定値を
+// 保持するためのフィールドを持ちます。
+type Command struct {
+	*cobra.Command
+	AppInfo *appinfo.AppInfo
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は "where" コマンドの新規オブジェクト（のポインタ）を返します。
+func New(appInfo *appinfo.AppInfo) *cobra.Command {
+	// コマンドのインスタンス生成
+	cmdWhere := new(Command)
+
+	// コマンドの割り当て
+	cmdWhere.Command = &cobra.Command{
+		Use:   "where",
+		Short: "使用しているタスクと設定ファイルの検索経路を表示します（デバッグ用）",
+		Long: util.HereDoc(`
+				About:
+				  'where' コマンドは、ローカル・タスクおよび設定ファイルを探すために遡っ
+				  たディレクトリの経路と、検索を終了した理由を表示します。また、実際に
+				  読み込まれたファイルのパスも表示します。
+			`),
+		Example: util.HereDoc(`
+				qiitask where
+			`, "  "),
+		Args: cobra.NoArgs,
+	}
+
+	// Set app info (conf and tasks)
+	cmdWhere.AppInfo = appInfo
+
+	// RunE function
+	cmdWhere.Command.RunE = cmdWhere.Where
+
+	return cmdWhere.Command
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Where は "where" コマンドの本体です。
+func (c *Command) Where(cmd *cobra.Command, args []string) error {
+	cmd.Println("■ ローカル・タスク")
+	printChain(cmd, c.AppInfo.Tasks.Local.SearchResult())
+	printUsed(cmd, c.AppInfo.Tasks.Local.FileUsed())
+
+	cmd.Println("■ グローバル・タスク")
+	printUsed(cmd, c.AppInfo.Tasks.Global.FileUsed())
+
+	cmd.Println("■ 設定ファイル")
+	printChain(cmd, c.AppInfo.Config.SearchResult())
+	printUsed(cmd, c.AppInfo.Config.FileUsed())
+
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// printChain は検索したディレクトリの経路を出力します。
+func printChain(cmd *cobra.Command, result *workspace.Result) {
+	if result == nil {
+		return
+	}
+
+	for _, step := range result.Chain {
+		if step.Reason == "" {
+			cmd.Printf("  %v\n", step.Dir)
+
+			continue
+		}
+
+		cmd.Printf("  %v ... %v\n", step.Dir, step.Reason)
+	}
+}
+
+// printUsed は読み込まれたファイルのパスを出力します。
+func printUsed(cmd *cobra.Command, pathFile string) {
+	if pathFile == "" {
+		pathFile = "(なし)"
+	}
+
+	cmd.Printf("  => %v\n", pathFile)
+}
@@ -0,0 +1,49 @@
+package cmdwhere_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdroot"
+	"github.com/Qithub-BOT/QiiTask/cmd/qiitask/subcmd/cmdwhere"
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/kami-zh/go-capturer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	obj1 := cmdwhere.New(appInfo)
+	obj2 := cmdwhere.New(appInfo)
+
+	assert.NotSame(t, obj1, obj2, "it should not reference the same object")
+}
+
+func TestWhere(t *testing.T) {
+	pathDirRoot := t.TempDir()
+	pathDirSub := filepath.Join(pathDirRoot, "sub")
+	pathFileTask := filepath.Join(pathDirRoot, todo.NameFile)
+
+	require.NoError(t, os.Mkdir(pathDirSub, 0o755))
+	require.NoError(t, os.WriteFile(pathFileTask, []byte("task 1\n"), 0o600))
+
+	appInfo, err := appinfo.New(pathDirSub, t.TempDir(), "")
+	require.NoError(t, err)
+
+	mother := cmdroot.New(appInfo)
+	mother.SetArgs([]string{"where"})
+
+	out := capturer.CaptureOutput(func() {
+		require.NoError(t, mother.Execute())
+	})
+
+	assert.Contains(t, out, "  "+pathDirSub+"\n")
+	assert.Contains(t, out, pathDirRoot+" ... found "+pathFileTask)
+	assert.Contains(t, out, "  => "+pathFileTask)
+	assert.Contains(t, out, "  => (なし)")
+}
@@ -0,0 +1,175 @@
+/*
+Package agenda は日々の見直し（デイリー・レビュー）用に、タスクを期日や状態ごと
+のセクションに分類するパッケージです。
+*/
+package agenda
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/clock"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+const (
+	// TopDefault は上位のタスクとして表示するタスク数のデフォルトです。
+	TopDefault = 5
+	// StaleDaysDefault は手を付けていないとみなすまでの日数のデフォルトです。
+	StaleDaysDefault = 30
+	// DaysWeek は「今週」とみなす、明日からの日数です。
+	DaysWeek = 7
+)
+
+// セクションの種類。
+const (
+	KindOverdue   = "overdue"   // 期限切れ
+	KindToday     = "today"     // 今日が期限
+	KindWeek      = "week"      // 今週が期限
+	KindTop       = "top"       // 上位のタスク
+	KindYesterday = "yesterday" // 昨日完了したタスク
+	KindStale     = "stale"     // ながらく手を付けていないタスク
+)
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// weekdays は曜日の表記です。
+var weekdays = []string{"日", "月", "火", "水", "木", "金", "土"}
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Options はセクションに分類する際の設定です。0 以下の値はデフォルト値になりま
+// す。
+type Options struct {
+	Top       int // 上位のタスクとして表示するタスク数
+	StaleDays int // 手を付けていないとみなすまでの日数
+}
+
+// Section は同じ条件に該当するタスクのまとまりです。
+type Section struct {
+	Kind   string // セクションの種類（KindXxx）
+	Title  string // 見出し
+	Header string // Items の Detail の列名
+	Items  []Item // 該当するタスク
+}
+
+// Item はセクション内のタスクです。
+type Item struct {
+	Task   *todotxt.Task // タスク
+	Detail string        // 期日や経過日数などの補足
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// Build は taskList のタスクを、期限切れ・今日が期限・今週が期限・上位のタスク・
+// 昨日完了したタスク・ながらく手を付けていないタスクの順のセクションに分類して返
+// します。該当するタスクがないセクションも含まれます。
+//
+// 上位のタスクは、一覧の上から順（'sort' で優先度の高い順）の未完了のタスクで、
+// "dep:" タグでブロックされているタスクは除かれます。手を付けていないかは、タス
+// クを最後に変更した日（"touched:" タグ、ない場合は作成日）からの経過日数で判断
+// します。（todo.Age を参照）今日の日付には clock.TimeNow が使われます。
+func Build(taskList *todo.Todo, opts Options) []Section {
+	if opts.Top <= 0 {
+		opts.Top = TopDefault
+	}
+
+	if opts.StaleDays <= 0 {
+		opts.StaleDays = StaleDaysDefault
+	}
+
+	today := Date(clock.TimeNow())
+
+	var overdue, dueToday, dueWeek, top, yesterday, stale []Item
+
+	if taskList.TaskList != nil {
+		for i := range *taskList.TaskList {
+			task := &(*taskList.TaskList)[i]
+
+			if task.Completed {
+				if task.HasCompletedDate() && todo.DaysBetween(task.CompletedDate, today) == 1 {
+					yesterday = append(yesterday, Item{task, formatDate(task.CompletedDate)})
+				}
+
+				continue
+			}
+
+			if task.HasDueDate() {
+				switch days := todo.DaysBetween(today, task.DueDate); {
+				case days < 0:
+					overdue = append(overdue, Item{
+						task, fmt.Sprintf("%v（%d 日超過）", task.DueDate.Format(todotxt.DateLayout), -days),
+					})
+				case days == 0:
+					dueToday = append(dueToday, Item{task, formatDate(task.DueDate)})
+				case days <= DaysWeek:
+					dueWeek = append(dueWeek, Item{task, formatDate(task.DueDate)})
+				}
+			}
+
+			if len(top) < opts.Top && len(taskList.Blockers(task)) == 0 {
+				top = append(top, Item{task, task.Priority})
+			}
+
+			if age, ok := todo.Age(task, today); ok && age >= opts.StaleDays {
+				stale = append(stale, Item{task, fmt.Sprintf("%d 日", age)})
+			}
+		}
+	}
+
+	sortByDue(overdue)
+	sortByDue(dueToday)
+	sortByDue(dueWeek)
+
+	sort.SliceStable(stale, func(a, b int) bool {
+		touchedA, _ := todo.Touched(stale[a].Task)
+		touchedB, _ := todo.Touched(stale[b].Task)
+
+		return touchedA.Before(touchedB)
+	})
+
+	return []Section{
+		{KindOverdue, "期限切れ", "due", overdue},
+		{KindToday, "今日が期限", "due", dueToday},
+		{KindWeek, "今週が期限", "due", dueWeek},
+		{KindTop, fmt.Sprintf("上位 %d 件のタスク", opts.Top), "pri", top},
+		{KindYesterday, "昨日完了したタスク", "done", yesterday},
+		{KindStale, "ながらく手を付けていないタスク", "age", stale},
+	}
+}
+
+// Date は t の時刻を切り捨てた、ローカル時刻の日付を返します。
+func Date(t time.Time) time.Time {
+	year, month, day := t.Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// formatDate は日付を曜日付きの文字列で返します。（例: "2021-01-04（月）"）
+func formatDate(date time.Time) string {
+	return fmt.Sprintf("%v（%v）", date.Format(todotxt.DateLayout), weekdays[date.Weekday()])
+}
+
+// sortByDue は items を期日の早い順に並べ替えます。期日が同じ場合は元の順序のま
+// まです。
+func sortByDue(items []Item) {
+	sort.SliceStable(items, func(a, b int) bool {
+		return items[a].Task.DueDate.Before(items[b].Task.DueDate)
+	})
+}
@@ -0,0 +1,110 @@
+package agenda_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/agenda"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTodo は data のタスクを持つ todo.Todo を返します。
+func newTodo(t *testing.T, data string) *todo.Todo {
+	t.Helper()
+
+	pathDir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDir, todo.NameFile), []byte(data), 0o600))
+
+	taskList, err := todo.New(pathDir)
+	require.NoError(t, err)
+
+	return taskList
+}
+
+// todos は items のタスクの Todo と Detail の一覧を返します。
+func todos(items []agenda.Item) [][2]string {
+	result := [][2]string{}
+
+	for _, item := range items {
+		result = append(result, [2]string{item.Task.Todo, item.Detail})
+	}
+
+	return result
+}
+
+func TestBuild(t *testing.T) {
+	testutil.MockTimeNow(t, testutil.Now)
+
+	taskList := newTodo(t, ""+
+		"(B) 2020-11-01 old task\n"+
+		"blocked id:b dep:a\n"+
+		"due today due:2021-01-06\n"+
+		"(A) overdue later due:2021-01-05\n"+
+		"overdue first due:2020-12-31\n"+
+		"due next week due:2021-01-13\n"+
+		"due too late due:2021-01-14\n"+
+		"2020-12-07 just stale id:a\n"+
+		"x 2021-01-05 done yesterday\n"+
+		"x 2021-01-04 done before due:2021-01-01\n"+
+		"2020-10-01 touched recently touched:2021-01-04\n")
+
+	sections := agenda.Build(taskList, agenda.Options{Top: 3})
+
+	require.Len(t, sections, 6)
+
+	assert.Equal(t, agenda.KindOverdue, sections[0].Kind)
+	assert.Equal(t, [][2]string{
+		{"overdue first", "2020-12-31（6 日超過）"},
+		{"overdue later", "2021-01-05（1 日超過）"},
+	}, todos(sections[0].Items), "overdue tasks should be sorted by due date")
+
+	assert.Equal(t, agenda.KindToday, sections[1].Kind)
+	assert.Equal(t, [][2]string{{"due today", "2021-01-06（水）"}}, todos(sections[1].Items))
+
+	assert.Equal(t, agenda.KindWeek, sections[2].Kind)
+	assert.Equal(t, [][2]string{{"due next week", "2021-01-13（水）"}}, todos(sections[2].Items))
+
+	assert.Equal(t, agenda.KindTop, sections[3].Kind)
+	assert.Equal(t, "上位 3 件のタスク", sections[3].Title)
+	assert.Equal(t, [][2]string{
+		{"old task", "B"},
+		{"due today", ""},
+		{"overdue later", "A"},
+	}, todos(sections[3].Items), "blocked tasks should be skipped")
+
+	assert.Equal(t, agenda.KindYesterday, sections[4].Kind)
+	assert.Equal(t, [][2]string{{"done yesterday", "2021-01-05（火）"}}, todos(sections[4].Items))
+
+	assert.Equal(t, agenda.KindStale, sections[5].Kind)
+	assert.Equal(t, "ながらく手を付けていないタスク", sections[5].Title)
+	assert.Equal(t, [][2]string{
+		{"old task", "66 日"},
+		{"just stale", "30 日"},
+	}, todos(sections[5].Items))
+}
+
+func TestBuild_options(t *testing.T) {
+	testutil.MockTimeNow(t, testutil.Now)
+
+	taskList := newTodo(t, "2021-01-01 one\n2021-01-02 two\n")
+
+	sections := agenda.Build(taskList, agenda.Options{StaleDays: 5})
+
+	assert.Equal(t, "上位 5 件のタスク", sections[3].Title, "zero should be the default")
+	assert.Len(t, sections[3].Items, 2)
+	assert.Equal(t, [][2]string{{"one", "5 日"}}, todos(sections[5].Items))
+}
+
+func TestBuild_no_tasks(t *testing.T) {
+	taskList, err := todo.New(t.TempDir())
+	require.NoError(t, err)
+
+	for _, section := range agenda.Build(taskList, agenda.Options{}) {
+		assert.Empty(t, section.Items, section.Kind)
+	}
+}
@@ -4,11 +4,39 @@ Package appinfo はアプリの設定情報およびタスクを管理するオ
 package appinfo
 
 import (
+	"os"
+
 	"github.com/Qithub-BOT/QiiTask/core/config"
 	"github.com/Qithub-BOT/QiiTask/core/todo"
 	"github.com/pkg/errors"
 )
 
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+const (
+	// EnvFile はローカルのタスク・ファイルのパスを指定する環境変数名です。
+	EnvFile = "QIITASK_FILE"
+	// EnvDir はローカルのタスクを検索する開始ディレクトリを指定する環境変数名です。
+	EnvDir = "QIITASK_DIR"
+	// EnvConfig は設定ファイルのパスを指定する環境変数名です。
+	EnvConfig = "QIITASK_CONFIG"
+	// EnvList は使用するタスク・リストの名前を指定する環境変数名です。
+	EnvList = "QIITASK_LIST"
+	// AnnotationAllowLoadErr はタスク・ファイルが読み込めなくても実行できるコマンド
+	// に付ける、cobra.Command の Annotations のキーです。（lint など）
+	AnnotationAllowLoadErr = "qiitask_allow_load_error"
+)
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// OsGetenv は os.Getenv のコピーです。テスト時に os.Getenv の動作をモックする為
+// に変数に代入しています。
+var OsGetenv = os.Getenv
+
 // ----------------------------------------------------------------------------
 //  型の定義
 // ----------------------------------------------------------------------------
@@ -18,10 +46,22 @@ import (
 // 生成されたオブジェクトは、アプリの各コマンド（cmd にあるパッケージ）に共有情
 // 報として渡されます。
 type AppInfo struct {
-	Config    *config.Config // アプリの設定ファイル情報
-	Tasks     *todo.Set      // ローカルおよびグローバルのタスク情報
-	Version   string         // アプリのバージョン情報
-	IsVerbose bool           // アプリの詳細表示モード（情報がある場合）
+	Config      *config.Config // アプリの設定ファイル情報
+	Tasks       *todo.Set      // ローカルおよびグローバルのタスク情報
+	Version     string         // アプリのバージョン情報
+	IsVerbose   bool           // アプリの詳細表示モード（情報がある場合）
+	pathDirCurr string         // ローカルのタスクおよび設定ファイルの検索開始ディレクトリ
+	pathDirHome string         // グローバルのタスクおよび設定ファイルのディレクトリ
+	override    Override       // ファイルの検索先の上書き指定
+}
+
+// Override はタスクおよび設定ファイルの検索先を上書きする指定です。"" のフィー
+// ルドは上書きしません。
+type Override struct {
+	PathFile   string // ローカルのタスク・ファイルのパス（検索を行いません）
+	PathDir    string // ローカルのタスクおよび設定ファイルの検索開始ディレクトリ
+	PathConfig string // 設定ファイルのパス（検索を行いません）
+	List       string // 使用するタスク・リストの名前（設定の "lists" で宣言）
 }
 
 // ----------------------------------------------------------------------------
@@ -31,24 +71,124 @@ type AppInfo struct {
 // New は初期化された AppInfo オブジェクトを返します。
 //
 // 返されたオブジェクトは pathDirCurr, pathDirHome に存在したアプリの設定および
-// タスクを読み込んだ状態で返されます。
-func New(pathDirCurr, pathDirHome, version string) (*AppInfo, error) {
-	conf, err := config.New(pathDirCurr, pathDirHome)
+// タスクを読み込んだ状態で返されます。overrides が指定された場合は、その指定で
+// ファイルの検索先を上書きします。（後に指定したものが優先されます）
+func New(pathDirCurr, pathDirHome, version string, overrides ...Override) (*AppInfo, error) {
+	app := new(AppInfo)
+
+	app.Version = version
+	app.IsVerbose = false
+	app.pathDirCurr = pathDirCurr
+	app.pathDirHome = pathDirHome
+
+	for _, override := range overrides {
+		app.override = app.override.Merge(override)
+	}
+
+	if err := app.load(); err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// OverrideFromEnv は環境変数 QIITASK_FILE, QIITASK_DIR, QIITASK_CONFIG,
+// QIITASK_LIST からファイルの検索先の上書き指定を返します。
+func OverrideFromEnv() Override {
+	return Override{
+		PathFile:   OsGetenv(EnvFile),
+		PathDir:    OsGetenv(EnvDir),
+		PathConfig: OsGetenv(EnvConfig),
+		List:       OsGetenv(EnvList),
+	}
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Override は現在のファイルの検索先の上書き指定を返します。
+func (a *AppInfo) Override() Override {
+	return a.override
+}
+
+// Reload は override でファイルの検索先を上書きし、アプリの設定およびタスクを
+// 読み込み直します。
+//
+// Config および Tasks のフィールドが置き換わるため、各コマンドは実行時に AppInfo
+// から参照する必要があります。エラーの場合は読み込み前の状態を維持します。
+func (a *AppInfo) Reload(override Override) error {
+	backup := *a
+
+	a.override = a.override.Merge(override)
+
+	if err := a.load(); err != nil {
+		*a = backup
+
+		return err
+	}
+
+	return nil
+}
+
+// Merge は o を other で上書きした指定を返します。other の "" のフィールドは上
+// 書きしません。
+func (o Override) Merge(other Override) Override {
+	if other.PathFile != "" {
+		o.PathFile = other.PathFile
+	}
+
+	if other.PathDir != "" {
+		o.PathDir = other.PathDir
+	}
+
+	if other.PathConfig != "" {
+		o.PathConfig = other.PathConfig
+	}
+
+	if other.List != "" {
+		o.List = other.List
+	}
+
+	return o
+}
+
+// load はアプリの設定およびタスクを読み込みます。
+func (a *AppInfo) load() error {
+	pathDirCurr := a.pathDirCurr
+	if a.override.PathDir != "" {
+		pathDirCurr = a.override.PathDir
+	}
+
+	conf, err := config.New(pathDirCurr, a.pathDirHome, a.override.PathConfig)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to instantiate config object")
+		return errors.Wrap(err, "failed to instantiate config object")
 	}
 
-	tasks, err := todo.NewSet(pathDirCurr, pathDirHome)
+	tasks, err := todo.NewSet(pathDirCurr, a.pathDirHome, a.override.PathFile)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to instantiate tasks object")
+		return errors.Wrap(err, "failed to instantiate tasks object")
 	}
 
-	app := new(AppInfo)
+	if a.override.List != "" {
+		nameFile, err := conf.FileList(a.override.List)
+		if err != nil {
+			return err
+		}
 
-	app.Config = conf
-	app.Tasks = tasks
-	app.Version = version
-	app.IsVerbose = false
+		if err := tasks.SelectList(nameFile); err != nil {
+			return errors.Wrap(err, "failed to load task list")
+		}
+	}
 
-	return app, nil
+	tasks.SetNumBackup(conf.GetInt("backup_count"))
+
+	a.Config = conf
+	a.Tasks = tasks
+
+	return nil
 }
@@ -39,7 +39,8 @@ func ExampleNew() {
 		fmt.Println("ok (app version whould be as is)")
 	}
 
-	if pathDirCurr == appInfo.Tasks.Local.Dir() {
+	// Local task is searched from the current dir up to the parent dirs
+	if pathDirCurr == appInfo.Tasks.Local.SearchResult().Chain[0].Dir {
 		fmt.Println("ok")
 	}
 
@@ -90,3 +91,77 @@ func TestNew_load_mal_format_conf(t *testing.T) {
 
 	assert.Contains(t, err.Error(), "failed to instantiate config object")
 }
+
+func TestNew_override(t *testing.T) {
+	pathDirTmp := t.TempDir()
+	pathFileTask := filepath.Join(pathDirTmp, "work.txt")
+	pathFileConf := filepath.Join(pathDirTmp, "my_conf.json")
+
+	require.NoError(t, os.WriteFile(pathFileTask, []byte("task 1\n"), 0o600))
+	require.NoError(t, os.WriteFile(pathFileConf, []byte(`{"backup_count": 7}`), 0o600))
+
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "",
+		appinfo.Override{PathFile: "dummy.txt"},
+		appinfo.Override{PathFile: pathFileTask, PathConfig: pathFileConf},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, pathFileTask, appInfo.Tasks.Local.FileUsed(), "the later override should win")
+	assert.Equal(t, pathFileConf, appInfo.Config.FileUsed())
+	assert.Equal(t, 7, appInfo.Tasks.Local.NumBackup)
+}
+
+func TestOverrideFromEnv(t *testing.T) {
+	// Backup and defer restoration
+	oldOsGetenv := appinfo.OsGetenv
+	defer func() {
+		appinfo.OsGetenv = oldOsGetenv
+	}()
+
+	appinfo.OsGetenv = func(key string) string {
+		return map[string]string{
+			appinfo.EnvFile:   "/path/to/todo.txt",
+			appinfo.EnvDir:    "/path/to",
+			appinfo.EnvConfig: "/path/to/config.json",
+		}[key]
+	}
+
+	expect := appinfo.Override{
+		PathFile:   "/path/to/todo.txt",
+		PathDir:    "/path/to",
+		PathConfig: "/path/to/config.json",
+	}
+
+	assert.Equal(t, expect, appinfo.OverrideFromEnv())
+}
+
+func TestReload(t *testing.T) {
+	pathDirWork := t.TempDir()
+	pathFileTask := filepath.Join(pathDirWork, todo.NameFile)
+
+	require.NoError(t, os.WriteFile(pathFileTask, []byte("task 1\n"), 0o600))
+
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "", appinfo.Override{PathConfig: "/dummy/config.json"})
+	require.NoError(t, err)
+	require.Empty(t, appInfo.Tasks.Local.FileUsed())
+
+	require.NoError(t, appInfo.Reload(appinfo.Override{PathDir: pathDirWork}))
+
+	assert.Equal(t, pathFileTask, appInfo.Tasks.Local.FileUsed())
+	assert.Equal(t, "/dummy/config.json", appInfo.Override().PathConfig, "previous override should be kept")
+}
+
+func TestReload_fail(t *testing.T) {
+	pathDirApp := filepath.Join(util.GetPathDirRepo(), "testdata", "error", "malformed_conf")
+
+	appInfo, err := appinfo.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	tasksBefore := appInfo.Tasks
+
+	err = appInfo.Reload(appinfo.Override{PathDir: pathDirApp})
+	require.Error(t, err)
+
+	assert.Same(t, tasksBefore, appInfo.Tasks, "it should keep the previous state on error")
+	assert.Empty(t, appInfo.Override().PathDir)
+}
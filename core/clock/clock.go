@@ -0,0 +1,14 @@
+/*
+Package clock はアプリで共通して使う現在時刻を定義します。
+
+"touched:" タグの日付、変更履歴や作業時間の記録の時刻など、アプリのすべてのパッ
+ケージは現在時刻を TimeNow から取得します。テストでは TimeNow を置き換えること
+で、すべてのパッケージの時刻をまとめて固定できます。
+*/
+package clock
+
+import "time"
+
+// TimeNow は time.Now のコピーです。テスト時に time.Now の動作をモックする為に変
+// 数に代入しています。
+var TimeNow = time.Now
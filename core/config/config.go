@@ -6,9 +6,16 @@ Package config はアプリの設定（"config.json"）を管理するオブジ
 package config
 
 import (
+	"encoding/json"
+	"os"
 	"path/filepath"
 
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/journal"
 	"github.com/Qithub-BOT/QiiTask/core/query"
+	"github.com/Qithub-BOT/QiiTask/core/safefile"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/Qithub-BOT/QiiTask/core/workspace"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
@@ -21,6 +28,10 @@ import (
 // NameConf はアプリ設定のファイル名です。
 const NameConf = "config.json"
 
+// NameListDefault はデフォルトのタスク・リストの名前です。このリストには
+// todo.NameFile（"todo.txt"）が使われます。
+const NameListDefault = "todo"
+
 // ----------------------------------------------------------------------------
 //  型の定義
 // ----------------------------------------------------------------------------
@@ -30,9 +41,10 @@ const NameConf = "config.json"
 type Config struct {
 	*viper.Viper
 	// 拡張プロパティ
-	pathDir      string // 設定ファイルの読み込み・保存先のディレクトリ
-	pathFile     string // 設定ファイルが読み込まれいた場合のファイルのパス
-	triedLoading bool   // Load() を実行した場合 true
+	pathDir      string            // 設定ファイルの読み込み・保存先のディレクトリ
+	pathFile     string            // 設定ファイルが読み込まれいた場合のファイルのパス
+	search       *workspace.Result // カレント・ディレクトリからの検索結果
+	triedLoading bool              // Load() を実行した場合 true
 }
 
 // ----------------------------------------------------------------------------
@@ -41,30 +53,44 @@ type Config struct {
 
 // New はデフォルトの設定済み新規 Config インスタンスを返します。
 //
-// 引数はファイルの検索先のパスとして指定する必要があります。
-func New(pathDirCurr, pathDirHome string) (*Config, error) {
+// 引数はファイルの検索先のパスとして指定する必要があります。pathDirCurr からは
+// タスク・ファイルと同様に、親ディレクトリを遡ってワークスペースのルートを検索
+// します。（todo.Find を参照）
+//
+// pathFileConf が "" 以外の場合は検索を行わず、pathFileConf の設定ファイルを読
+// み込みます。ファイルが存在しない場合はデフォルトの設定を使います。
+func New(pathDirCurr, pathDirHome, pathFileConf string) (*Config, error) {
 	config := new(Config)
 	config.Viper = viper.New()
+	config.search = workspace.Search(pathDirCurr, todo.NameFile)
 
-	// 検索対象のファイル名をセット
-	config.SetConfigName(NameConf)
 	config.SetConfigType("json") // この設定は必須
 
-	// 設定ファイルの検索パスの追加
-	config.AddConfigPath(
-		filepath.Join(pathDirCurr, ".qiitask"), // カレント
-	)
-	config.AddConfigPath(
-		filepath.Join(pathDirHome, ".config", "qiitask"), // ユーザホーム
-	)
-
-	// デフォルトの保存先ディレクトリをユーザ・ホームにセット。
-	// この値は設定ファイルを見つけた場合に上書きされます。
-	config.pathDir = filepath.Join(pathDirHome, ".config", "qiitask")
+	if pathFileConf != "" {
+		// 指定されたファイルのみを対象にする
+		config.SetConfigFile(pathFileConf)
+		config.pathDir = filepath.Dir(pathFileConf)
+	} else {
+		// 検索対象のファイル名をセット
+		config.SetConfigName(NameConf)
+
+		// 設定ファイルの検索パスの追加
+		config.AddConfigPath(
+			filepath.Join(config.search.Root, workspace.NameDirConf), // カレントのワークスペース
+		)
+		config.AddConfigPath(
+			filepath.Join(pathDirHome, ".config", "qiitask"), // ユーザホーム
+		)
+
+		// デフォルトの保存先ディレクトリをユーザ・ホームにセット。
+		// この値は設定ファイルを見つけた場合に上書きされます。
+		config.pathDir = filepath.Join(pathDirHome, ".config", "qiitask")
+	}
 
-	config.SetDefault("standby_interval", 5)       // 入力待ち時間（秒）
-	config.SetDefault("separator_interval", 5)     // リストの区切り位置（行）
-	config.SetDefault("queries", new(query.Query)) // ソートに使う質問集
+	config.SetDefault("standby_interval", 5)                     // 入力待ち時間（秒）
+	config.SetDefault("separator_interval", 5)                   // リストの区切り位置（行）
+	config.SetDefault("queries", new(query.Query))               // ソートに使う質問集
+	config.SetDefault("backup_count", safefile.NumBackupDefault) // 保存時のバックアップ数
 
 	if err := config.Load(); err != nil {
 		return nil, errors.Wrap(err, "failed to initialize app config")
@@ -82,6 +108,34 @@ func (c *Config) FileUsed() string {
 	return c.pathFile
 }
 
+// FileList は name のタスク・リストのファイル名を返します。name が "" もしくは
+// NameListDefault の場合は todo.NameFile を返します。設定の "lists" に宣言され
+// ていない場合はエラーを返します。
+func (c *Config) FileList(name string) (string, error) {
+	nameFile, ok := c.GetLists()[name]
+	if name == "" || !ok && name == NameListDefault {
+		return todo.NameFile, nil
+	}
+
+	if !ok {
+		return "", errors.Errorf("タスク・リストが見つかりません: %v", name)
+	}
+
+	return nameFile, nil
+}
+
+// GetLists は設定の "lists" に宣言されたタスク・リストの名前とファイル名の一覧を
+// 返します。デフォルトのリスト（NameListDefault）も含まれます。
+func (c *Config) GetLists() map[string]string {
+	lists := map[string]string{NameListDefault: todo.NameFile}
+
+	for name, nameFile := range c.GetStringMapString("lists") {
+		lists[name] = nameFile
+	}
+
+	return lists
+}
+
 // GetQueryDescription は質問一覧の説明文を返します。
 func (c *Config) GetQueryDescription() string {
 	result := ""
@@ -93,6 +147,17 @@ func (c *Config) GetQueryDescription() string {
 	return result
 }
 
+// GetQueryEffort は見積りに使う、作業量を比較する質問を返します。設定ファイル
+// の "queries_effort" が空の場合は、同梱の質問集の作業量の質問（query.Effort）
+// を返します。
+func (c *Config) GetQueryEffort() []string {
+	if result := c.GetStringSlice("queries_effort"); len(result) > 0 {
+		return result
+	}
+
+	return query.Effort()
+}
+
 // GetQueryObjective は質問一覧のうち、客観的な質問を返します。
 func (c *Config) GetQueryObjective() []string {
 	result := []string{""}
@@ -145,6 +210,13 @@ func (c *Config) Load() error {
 			return nil
 		}
 
+		// SetConfigFile で指定したファイルが存在しない場合も同様
+		if errors.Is(err, os.ErrNotExist) {
+			c.pathFile = ""
+
+			return nil
+		}
+
 		// Wrap error if err was other than file-not-found
 		return errors.Wrap(err, "config file was found but another error was produced")
 	}
@@ -169,10 +241,81 @@ func (c *Config) OverWrite() error {
 		return errors.New("config file path not set. Use SaveAs method instead")
 	}
 
-	return c.WriteConfig()
+	return c.SaveAs(c.pathFile)
+}
+
+// Save は設定ファイルを保存します。設定ファイルが存在しない場合は、デフォルトの
+// 保存先ディレクトリに新規作成します。
+func (c *Config) Save() error {
+	if c.pathFile != "" {
+		return c.OverWrite()
+	}
+
+	return c.SaveIn(c.pathDir)
+}
+
+// SaveIn は pathDir ディレクトリに設定ファイルを新規作成し、以降の保存先にしま
+// す。保存は SaveAs と同じくアトミックに行われます。
+func (c *Config) SaveIn(pathDir string) error {
+	if err := os.MkdirAll(pathDir, 0o755); err != nil {
+		return errors.Wrap(err, "failed to create config directory")
+	}
+
+	pathFile := filepath.Join(pathDir, NameConf)
+
+	if err := c.SaveAs(pathFile); err != nil {
+		return err
+	}
+
+	c.pathDir = pathDir
+	c.pathFile = pathFile
+
+	return nil
 }
 
 // SaveAs は設定ファイルを保存します。
+//
+// 保存は一時ファイルを経由してアトミックに行われ、既存のファイルは設定の
+// "backup_count" 個までバックアップされます。また、保存前後の差分はジャーナルに
+// 記録されます。
 func (c *Config) SaveAs(pathFile string) error {
-	return c.WriteConfigAs(pathFile)
+	// viper.WriteConfig と同じ書式で出力
+	data, err := json.MarshalIndent(c.AllSettings(), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal config")
+	}
+
+	before := []byte{}
+
+	if util.IsFile(pathFile) {
+		if before, err = os.ReadFile(pathFile); err != nil {
+			return errors.Wrap(err, "failed to read config file before saving")
+		}
+	}
+
+	if err := safefile.WriteFile(pathFile, data, c.GetInt("backup_count")); err != nil {
+		return errors.Wrap(err, "failed to write config file")
+	}
+
+	return errors.Wrap(journal.Record(pathFile, before, data), "failed to record journal")
+}
+
+// SetList は name のタスク・リストを nameFile のファイル名で宣言します。
+// nameFile が "" の場合は宣言を削除します。設定ファイルへの保存は行いません。
+func (c *Config) SetList(name, nameFile string) {
+	lists := c.GetStringMapString("lists")
+
+	if nameFile == "" {
+		delete(lists, name)
+	} else {
+		lists[name] = nameFile
+	}
+
+	c.Set("lists", lists)
+}
+
+// SearchResult はカレント・ディレクトリから設定ファイルを検索した際の検索結果を
+// 返します。
+func (c *Config) SearchResult() *workspace.Result {
+	return c.search
 }
@@ -7,6 +7,8 @@ import (
 
 	"github.com/KEINOS/go-utiles/util"
 	"github.com/Qithub-BOT/QiiTask/core/config"
+	"github.com/Qithub-BOT/QiiTask/core/query"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -21,7 +23,7 @@ func TestGetQuery(t *testing.T) {
 			"working_task_under_conf",
 		)
 
-		conf, err := config.New(pathDirTest, t.TempDir())
+		conf, err := config.New(pathDirTest, t.TempDir(), "")
 		require.NoError(t, err)
 
 		// Test
@@ -40,7 +42,7 @@ func TestGetQuery(t *testing.T) {
 			"working_with_task_and_conf",
 		)
 
-		conf, err := config.New(pathDirTest, t.TempDir())
+		conf, err := config.New(pathDirTest, t.TempDir(), "")
 		require.NoError(t, err)
 
 		// Test
@@ -56,7 +58,7 @@ func TestLoad(t *testing.T) {
 	pathDirTest := filepath.Join(util.GetPathDirRepo(), "testdata", "golden", "working_with_task_and_conf")
 	pathDirConf := filepath.Join(pathDirTest, ".qiitask")
 
-	conf, err := config.New(pathDirTest, t.TempDir())
+	conf, err := config.New(pathDirTest, t.TempDir(), "")
 	require.NoError(t, err)
 
 	assert.NotEmpty(t, conf.FileUsed(), "it should load an existing conf file at: %v", pathDirConf)
@@ -81,7 +83,7 @@ func TestLoad(t *testing.T) {
 func TestLoad_queries_wrong(t *testing.T) {
 	pathDirTest := filepath.Join(util.GetPathDirRepo(), "testdata", "error", "queries_as_wrong")
 
-	_, err := config.New(pathDirTest, t.TempDir())
+	_, err := config.New(pathDirTest, t.TempDir(), "")
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to initialize app config")
@@ -91,7 +93,7 @@ func TestLoad_queries_wrong(t *testing.T) {
 func TestLoad_malformed_conf(t *testing.T) {
 	pathDirTest := filepath.Join(util.GetPathDirRepo(), "testdata", "error", "malformed_conf")
 
-	conf, err := config.New(pathDirTest, t.TempDir())
+	conf, err := config.New(pathDirTest, t.TempDir(), "")
 	require.Nil(t, conf, "on error the object should be nil")
 	require.Error(t, err, "mal-formed conf should return an error")
 
@@ -100,7 +102,7 @@ func TestLoad_malformed_conf(t *testing.T) {
 
 func TestNew_default_conf_values(t *testing.T) {
 	// instantiate object with empty dir as a target
-	conf, err := config.New(t.TempDir(), t.TempDir())
+	conf, err := config.New(t.TempDir(), t.TempDir(), "")
 
 	require.NoError(t, err)
 	require.True(t, conf.IsDefaultConf(),
@@ -122,10 +124,10 @@ func TestNew_default_conf_values(t *testing.T) {
 }
 
 func TestNew_instantiation(t *testing.T) {
-	obj1, err := config.New("", "")
+	obj1, err := config.New("", "", "")
 	require.NoError(t, err)
 
-	obj2, err := config.New("", "")
+	obj2, err := config.New("", "", "")
 	require.NoError(t, err)
 
 	// We do not use singleton style Viper usage.
@@ -150,7 +152,7 @@ func TestOverWrite(t *testing.T) {
 	require.NoError(t, util.CopyFile(pathFileConfOriginal, pathFileConf),
 		"failed to copy the original conf file to temp during test")
 
-	conf, err := config.New(pathDirTemp, t.TempDir())
+	conf, err := config.New(pathDirTemp, t.TempDir(), "")
 	require.NoError(t, err)
 	require.Equal(t, pathFileConf, conf.FileUsed(), "it should use the existing conf")
 
@@ -166,13 +168,16 @@ func TestOverWrite(t *testing.T) {
 	require.NoError(t, err, "failed to read witten file")
 
 	assert.Equal(t,
-		"{\n  \"queries\": {},\n  \"separator_interval\": 1,\n  \"standby_interval\": 2\n}",
+		"{\n  \"backup_count\": 3,\n  \"queries\": {},\n  \"separator_interval\": 1,\n  \"standby_interval\": 2\n}",
 		string(confByte),
 	)
+
+	// Assert backup
+	assert.FileExists(t, pathFileConf+".1", "the previous config should be backed up")
 }
 
 func TestOverWrite_config_path_not_set(t *testing.T) {
-	obj, err := config.New(t.TempDir(), t.TempDir())
+	obj, err := config.New(t.TempDir(), t.TempDir(), "")
 	require.NoError(t, err)
 
 	err = obj.OverWrite()
@@ -185,7 +190,7 @@ func TestSaveAs(t *testing.T) {
 	pathDirTemp := t.TempDir()
 	pathFileConf := filepath.Join(pathDirTemp, "config.json")
 
-	obj, err := config.New(pathDirTemp, pathDirTemp)
+	obj, err := config.New(pathDirTemp, pathDirTemp, "")
 	require.NoError(t, err)
 
 	// Set dummy conf value
@@ -201,7 +206,122 @@ func TestSaveAs(t *testing.T) {
 	require.NoError(t, err, "failed to read witten file")
 
 	assert.Equal(t,
-		"{\n  \"queries\": {},\n  \"separator_interval\": 100,\n  \"standby_interval\": 200\n}",
+		"{\n  \"backup_count\": 3,\n  \"queries\": {},\n  \"separator_interval\": 100,\n  \"standby_interval\": 200\n}",
 		string(confByte),
 	)
 }
+
+func TestNew_search_parent_dir(t *testing.T) {
+	pathDirRoot := t.TempDir()
+	pathDirSub := filepath.Join(pathDirRoot, "foo", "bar")
+	pathFileConf := filepath.Join(pathDirRoot, ".qiitask", config.NameConf)
+
+	require.NoError(t, os.MkdirAll(pathDirSub, 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(pathFileConf), 0o755))
+	require.NoError(t, os.WriteFile(pathFileConf, []byte(`{"separator_interval": 7}`), 0o600))
+
+	conf, err := config.New(pathDirSub, t.TempDir(), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, pathFileConf, conf.FileUsed(), "it should find the config file in the parent dir")
+	assert.Equal(t, 7, conf.GetInt("separator_interval"))
+	assert.Equal(t, pathDirRoot, conf.SearchResult().Root)
+}
+
+func TestNew_explicit_file(t *testing.T) {
+	pathDirTmp := t.TempDir()
+	pathFileConf := filepath.Join(pathDirTmp, "my_conf.json")
+
+	require.NoError(t, os.WriteFile(pathFileConf, []byte(`{"separator_interval": 9}`), 0o600))
+
+	conf, err := config.New(t.TempDir(), t.TempDir(), pathFileConf)
+	require.NoError(t, err)
+
+	assert.Equal(t, pathFileConf, conf.FileUsed())
+	assert.Equal(t, 9, conf.GetInt("separator_interval"))
+}
+
+func TestNew_explicit_file_not_exist(t *testing.T) {
+	pathFileConf := filepath.Join(t.TempDir(), "my_conf.json")
+
+	conf, err := config.New(t.TempDir(), t.TempDir(), pathFileConf)
+	require.NoError(t, err, "missing config file should use the default")
+
+	assert.Empty(t, conf.FileUsed())
+	assert.Equal(t, 5, conf.GetInt("separator_interval"))
+}
+
+func TestFileList(t *testing.T) {
+	conf, err := config.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	conf.SetList("work", "work.txt")
+
+	for name, expect := range map[string]string{
+		"":                     todo.NameFile,
+		config.NameListDefault: todo.NameFile,
+		"work":                 "work.txt",
+	} {
+		actual, err := conf.FileList(name)
+		require.NoError(t, err)
+
+		assert.Equal(t, expect, actual, "name: %q", name)
+	}
+
+	_, err = conf.FileList("unknown")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "タスク・リストが見つかりません")
+
+	conf.SetList("work", "")
+	assert.Equal(t, map[string]string{config.NameListDefault: todo.NameFile}, conf.GetLists())
+}
+
+func TestGetQueryEffort(t *testing.T) {
+	conf, err := config.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, query.Effort(), conf.GetQueryEffort())
+
+	conf.Set("queries_effort", []string{"どちらが簡単ですか"})
+
+	assert.Equal(t, []string{"どちらが簡単ですか"}, conf.GetQueryEffort())
+}
+
+func TestSave_new_file(t *testing.T) {
+	pathDirHome := t.TempDir()
+
+	conf, err := config.New(t.TempDir(), pathDirHome, "")
+	require.NoError(t, err)
+	require.Empty(t, conf.FileUsed())
+
+	conf.SetList("work", "work.txt")
+
+	require.NoError(t, conf.Save())
+
+	pathFileConf := filepath.Join(pathDirHome, ".config", "qiitask", config.NameConf)
+	assert.Equal(t, pathFileConf, conf.FileUsed())
+
+	// Reload
+	conf, err = config.New(t.TempDir(), pathDirHome, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "work.txt", conf.GetLists()["work"])
+}
+
+func TestSaveIn(t *testing.T) {
+	pathDirConf := filepath.Join(t.TempDir(), ".qiitask")
+
+	conf, err := config.New(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	require.NoError(t, conf.SaveIn(pathDirConf))
+
+	pathFileConf := filepath.Join(pathDirConf, config.NameConf)
+
+	assert.Equal(t, pathFileConf, conf.FileUsed())
+
+	// Overwrite creates a backup
+	require.NoError(t, conf.Save())
+
+	assert.FileExists(t, pathFileConf+".1")
+}
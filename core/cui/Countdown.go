@@ -0,0 +1,54 @@
+package cui
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/Qithub-BOT/QiiTask/core/clock"
+)
+
+// TimeAfter は time.After のコピーです。テスト時に待機せずに進める為に変数に代入
+// しています。
+var TimeAfter = time.After
+
+// Countdown は duration の間、label と残り時間を進捗バーで描画しながら待機し、
+// 経過時間を返します。
+//
+// 待機中に ctrl+c （SIGINT）が送られてきた場合は、その時点で待機を終了し、それ
+// までの経過時間を返します。
+func (ui *UI) Countdown(label string, duration time.Duration) time.Duration {
+	interrupt := make(chan os.Signal, 1)
+
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	timeStart := clock.TimeNow()
+
+	for {
+		elapsed := clock.TimeNow().Sub(timeStart)
+		if elapsed >= duration {
+			elapsed = duration
+		}
+
+		remain := (duration - elapsed).Round(time.Second)
+		labelRemain := fmt.Sprintf("%02d:%02d %v", int(remain.Minutes()), int(remain.Seconds())%60, label)
+
+		ui.DrawProgress(labelRemain, int(elapsed/time.Second), int(duration/time.Second))
+
+		if elapsed >= duration {
+			fmt.Fprintln(ui.MirrorIO)
+
+			return elapsed
+		}
+
+		select {
+		case <-interrupt:
+			fmt.Fprintln(ui.MirrorIO)
+
+			return elapsed
+		case <-TimeAfter(time.Second):
+		}
+	}
+}
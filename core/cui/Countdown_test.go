@@ -0,0 +1,55 @@
+package cui_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockClock は clock.TimeNow を呼び出されるごとに step 進む時刻に、
+// cui.TimeAfter を待機せずに返す関数に置き換えます。
+func mockClock(t *testing.T, step time.Duration) {
+	t.Helper()
+
+	oldTimeAfter := cui.TimeAfter
+
+	t.Cleanup(func() {
+		cui.TimeAfter = oldTimeAfter
+	})
+
+	current := time.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	testutil.MockTimeNowFunc(t, func() time.Time {
+		result := current
+		current = current.Add(step)
+
+		return result
+	})
+
+	cui.TimeAfter = func(d time.Duration) <-chan time.Time {
+		ch := make(chan time.Time, 1)
+		ch <- current
+
+		return ch
+	}
+}
+
+func TestCountdown(t *testing.T) {
+	mockClock(t, time.Minute)
+
+	ui := cui.New()
+	buffer := &bytes.Buffer{}
+
+	ui.MirrorIO = buffer
+
+	elapsed := ui.Countdown("write docs", 3*time.Minute)
+
+	assert.Equal(t, 3*time.Minute, elapsed)
+	assert.Contains(t, buffer.String(), "02:00 write docs")
+	assert.Contains(t, buffer.String(), "01:00 write docs")
+	assert.Contains(t, buffer.String(), "100% 00:00 write docs\n")
+}
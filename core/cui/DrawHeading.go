@@ -0,0 +1,32 @@
+package cui
+
+import (
+	"fmt"
+	"html"
+)
+
+// DrawHeading はテーブルの前に置く見出しを、テーブルのスタイルにあわせて描画し
+// ます。Markdown の場合は "##" の見出し、HTML の場合は h2 要素になります。
+func (ui *UI) DrawHeading(title string, style TableStyle) {
+	switch style {
+	case AsMarkdownTable:
+		fmt.Fprintf(ui.MirrorIO, "## %v\n\n", title)
+	case AsHTMLTable:
+		fmt.Fprintf(ui.MirrorIO, "<h2>%v</h2>\n", html.EscapeString(title))
+	default:
+		fmt.Fprintf(ui.MirrorIO, "■ %v\n", title)
+	}
+}
+
+// DrawNote はテーブルの代わりに表示する 1 行の文章（「なし」など）を、テーブル
+// のスタイルにあわせて描画します。HTML の場合は p 要素になります。
+func (ui *UI) DrawNote(note string, style TableStyle) {
+	switch style {
+	case AsMarkdownTable:
+		fmt.Fprintln(ui.MirrorIO, note)
+	case AsHTMLTable:
+		fmt.Fprintf(ui.MirrorIO, "<p>%v</p>\n", html.EscapeString(note))
+	default:
+		fmt.Fprintf(ui.MirrorIO, "  %v\n", note)
+	}
+}
@@ -0,0 +1,31 @@
+package cui_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrawHeading(t *testing.T) {
+	for _, test := range []struct {
+		style  cui.TableStyle
+		expect string
+	}{
+		{cui.AsDefaultTable, "■ A & B\n  none\n"},
+		{cui.AsColoredTable, "■ A & B\n  none\n"},
+		{cui.AsMarkdownTable, "## A & B\n\nnone\n"},
+		{cui.AsHTMLTable, "<h2>A &amp; B</h2>\n<p>none</p>\n"},
+	} {
+		buffer := &bytes.Buffer{}
+
+		ui := cui.New()
+		ui.MirrorIO = buffer
+
+		ui.DrawHeading("A & B", test.style)
+		ui.DrawNote("none", test.style)
+
+		assert.Equal(t, test.expect, buffer.String(), "style: %v", test.style)
+	}
+}
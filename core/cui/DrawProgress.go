@@ -0,0 +1,37 @@
+package cui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DrawProgress は current / total の進捗バーと label を 1 行で描画します。
+//
+// 行頭に戻る "\r" で描画するため、続けて呼び出すと同じ行のバーが更新されます。
+// 描画を終える場合は改行を出力してください。
+func (ui *UI) DrawProgress(label string, current int, total int) {
+	width := ui.TermWidth()
+
+	if width == 0 {
+		width = WidthTermDefault
+	}
+
+	widthBar := width / 2
+
+	if total <= 0 {
+		total = 1
+	}
+
+	if current > total {
+		current = total
+	}
+
+	if current < 0 {
+		current = 0
+	}
+
+	filled := widthBar * current / total
+	bar := strings.Repeat("#", filled) + strings.Repeat(".", widthBar-filled)
+
+	fmt.Fprintf(ui.MirrorIO, "\r[%v] %3d%% %v", bar, 100*current/total, label)
+}
@@ -0,0 +1,40 @@
+package cui_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrawProgress(t *testing.T) {
+	widthBar := cui.WidthTermDefault / 2
+
+	for _, test := range []struct {
+		current int
+		total   int
+		filled  int
+		percent string
+	}{
+		{0, 10, 0, "  0%"},
+		{5, 10, widthBar / 2, " 50%"},
+		{10, 10, widthBar, "100%"},
+		{20, 10, widthBar, "100%"}, // over
+		{-1, 10, 0, "  0%"},        // under
+		{1, 0, widthBar, "100%"},   // zero total
+	} {
+		ui := cui.New()
+		buffer := &bytes.Buffer{}
+
+		ui.MirrorIO = buffer
+
+		ui.DrawProgress("label", test.current, test.total)
+
+		expect := "\r[" + strings.Repeat("#", test.filled) + strings.Repeat(".", widthBar-test.filled) + "] " +
+			test.percent + " label"
+
+		assert.Equal(t, expect, buffer.String(), "current: %v, total: %v", test.current, test.total)
+	}
+}
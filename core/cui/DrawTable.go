@@ -31,12 +31,7 @@ func (ui *UI) DrawTable(tblToWrite table.Writer, style TableStyle) {
 		tblToWrite.SetOutputMirror(ui.MirrorIO)
 	}
 
-	switch style {
-	case AsColoredTable:
-		tblToWrite.SetStyle(ui.getStyleTableColored())
-	default:
-		tblToWrite.SetStyle(ui.getStyleTableDefault())
-	}
+	tblToWrite.SetStyle(ui.StyleTable(style))
 
 	switch style {
 	case AsCSVTable:
@@ -50,6 +45,16 @@ func (ui *UI) DrawTable(tblToWrite table.Writer, style TableStyle) {
 	}
 }
 
+// StyleTable は style のスタイル ID で描画する際のテーブルのスタイルを返します。
+// 全画面表示（TUI）など、テーブル以外の描画で同じ色を使う場合にも利用します。
+func (ui *UI) StyleTable(style TableStyle) table.Style {
+	if style == AsColoredTable {
+		return ui.getStyleTableColored()
+	}
+
+	return ui.getStyleTableDefault()
+}
+
 // getStyleTableColored は色付きテーブルのスタイルを返します。
 func (ui *UI) getStyleTableColored() table.Style {
 	style := table.StyleColoredBlueWhiteOnBlack
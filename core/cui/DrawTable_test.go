@@ -1,8 +1,12 @@
 package cui_test
 
 import (
+	"testing"
+
 	"github.com/Qithub-BOT/QiiTask/core/cui"
 	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/stretchr/testify/assert"
 )
 
 func ExampleUI_DrawTable() {
@@ -130,3 +134,11 @@ func ExampleUI_DrawTable_html() {
 	//   </tbody>
 	// </table>
 }
+
+func TestStyleTable(t *testing.T) {
+	ui := cui.New()
+
+	assert.Equal(t, table.StyleDefault.Name, ui.StyleTable(cui.AsDefaultTable).Name)
+	assert.Equal(t, table.StyleDefault.Name, ui.StyleTable(cui.AsMarkdownTable).Name)
+	assert.Equal(t, text.Colors{text.BgBlue, text.FgWhite}, ui.StyleTable(cui.AsColoredTable).Color.Header)
+}
@@ -0,0 +1,44 @@
+package cui
+
+import (
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/pkg/errors"
+)
+
+// InputLines は複数行の入力 UI です。戻り値は入力された行のうち、空行を除いた各
+// 行の前後の空白を取り除いたものです。
+//
+// cui.UI.ForceString が空（""）以外の場合は、その値を入力として扱います。
+func (ui *UI) InputLines(msg string, helpMsg string) ([]string, error) {
+	if ui.ForceError {
+		return nil, errors.New("forced error")
+	}
+
+	input := ui.ForceString
+
+	if input == "" {
+		prompt := &survey.Multiline{
+			Message: msg,
+			Help:    helpMsg,
+		}
+
+		answer, err := ui.AskOne(prompt, "")
+		if err != nil {
+			return nil, err
+		}
+
+		input = answer
+	}
+
+	result := []string{}
+
+	for _, line := range strings.Split(input, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			result = append(result, line)
+		}
+	}
+
+	return result, nil
+}
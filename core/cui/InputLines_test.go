@@ -0,0 +1,49 @@
+package cui_test
+
+import (
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2"
+	surveyCore "github.com/AlecAivazis/survey/v2/core"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputLines(t *testing.T) {
+	// Backup and defer recover
+	oldSurveyAskOne := cui.SurveyAskOne
+	defer func() {
+		cui.SurveyAskOne = oldSurveyAskOne
+	}()
+
+	// Mock user input
+	cui.SurveyAskOne = func(p survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+		return surveyCore.WriteAnswer(response, "", "  line 1\n\nline 2  \n")
+	}
+
+	obj := cui.New()
+
+	actual, err := obj.InputLines("test input", "no help message")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"line 1", "line 2"}, actual, "it should trim spaces and skip empty lines")
+}
+
+func TestInputLines_forced(t *testing.T) {
+	obj := cui.New()
+
+	obj.ForceString = "foo\nbar"
+
+	actual, err := obj.InputLines("test input", "no help message")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar"}, actual)
+
+	obj.ForceError = true
+
+	_, err = obj.InputLines("test input", "no help message")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "forced error")
+}
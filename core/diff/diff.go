@@ -0,0 +1,81 @@
+/*
+Package diff は行単位の差分および 3-way マージを行う関数をまとめたパッケージです。
+*/
+package diff
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Hunk は 2 つの行スライス（before, after）の差分のうち、連続して変更された区
+// 間を表す型です。before[BeforeStart:BeforeEnd] が after[AfterStart:AfterEnd]
+// に置き換わったことを表します。
+type Hunk struct {
+	BeforeStart int
+	BeforeEnd   int
+	AfterStart  int
+	AfterEnd    int
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// Lines は before と after の行単位の差分を、最長共通部分列（LCS）をもとに Hunk
+// のスライスで返します。差分がない場合は空のスライスを返します。
+func Lines(before, after []string) []Hunk {
+	lenBefore := len(before)
+	lenAfter := len(after)
+
+	// lcs[i][j] は before[i:] と after[j:] の最長共通部分列の長さ
+	lcs := make([][]int, lenBefore+1)
+	for i := range lcs {
+		lcs[i] = make([]int, lenAfter+1)
+	}
+
+	for i := lenBefore - 1; i >= 0; i-- {
+		for j := lenAfter - 1; j >= 0; j-- {
+			switch {
+			case before[i] == after[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	hunks := []Hunk{}
+	i, j := 0, 0
+
+	for i < lenBefore || j < lenAfter {
+		if i < lenBefore && j < lenAfter && before[i] == after[j] {
+			i++
+			j++
+
+			continue
+		}
+
+		hunk := Hunk{BeforeStart: i, AfterStart: j}
+
+		for i < lenBefore || j < lenAfter {
+			if i < lenBefore && j < lenAfter && before[i] == after[j] {
+				break
+			}
+
+			if j >= lenAfter || (i < lenBefore && lcs[i+1][j] >= lcs[i][j+1]) {
+				i++
+			} else {
+				j++
+			}
+		}
+
+		hunk.BeforeEnd = i
+		hunk.AfterEnd = j
+
+		hunks = append(hunks, hunk)
+	}
+
+	return hunks
+}
@@ -0,0 +1,44 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/diff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	for _, test := range []struct {
+		before []string
+		after  []string
+		expect []diff.Hunk
+	}{
+		{
+			[]string{"a", "b", "c"},
+			[]string{"a", "b", "c"},
+			[]diff.Hunk{},
+		},
+		{
+			[]string{"a", "b", "c"},
+			[]string{"a", "B", "c"},
+			[]diff.Hunk{{BeforeStart: 1, BeforeEnd: 2, AfterStart: 1, AfterEnd: 2}},
+		},
+		{
+			[]string{"a", "b", "c"},
+			[]string{"a", "c", "d"},
+			[]diff.Hunk{
+				{BeforeStart: 1, BeforeEnd: 2, AfterStart: 1, AfterEnd: 1},
+				{BeforeStart: 3, BeforeEnd: 3, AfterStart: 2, AfterEnd: 3},
+			},
+		},
+		{
+			[]string{},
+			[]string{"a"},
+			[]diff.Hunk{{BeforeStart: 0, BeforeEnd: 0, AfterStart: 0, AfterEnd: 1}},
+		},
+	} {
+		actual := diff.Lines(test.before, test.after)
+
+		assert.Equal(t, test.expect, actual, "before: %v\nafter: %v", test.before, test.after)
+	}
+}
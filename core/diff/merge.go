@@ -0,0 +1,64 @@
+package diff
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// Merge3 は base（読み込み時の内容）に対する theirs（外部で変更された内容）の変
+// 更点を、ours（アプリ内で変更された内容）に適用した結果を返します。
+//
+// ours の並び順を優先するため、外部で変更された行は ours 内の同じ行の位置で置き
+// 換えられ、外部で削除された行は ours から取り除かれます。外部で追加された行は
+// 末尾に追加されます。ours 側でも変更されていた行（競合）は、どちらの変更も失わ
+// ないように外部の変更を末尾に追加します。
+func Merge3(base, ours, theirs []string) []string {
+	replaced := map[string][]string{} // base の行 -> 外部で変更された行
+	deleted := map[string]int{}       // 外部で削除された base の行（件数）
+	added := []string{}               // 外部で追加された行
+
+	for _, hunk := range Lines(base, theirs) {
+		lenBefore := hunk.BeforeEnd - hunk.BeforeStart
+		lenAfter := hunk.AfterEnd - hunk.AfterStart
+
+		for k := 0; k < lenBefore || k < lenAfter; k++ {
+			switch {
+			case k < lenBefore && k < lenAfter:
+				lineBase := base[hunk.BeforeStart+k]
+				replaced[lineBase] = append(replaced[lineBase], theirs[hunk.AfterStart+k])
+			case k < lenBefore:
+				deleted[base[hunk.BeforeStart+k]]++
+			default:
+				added = append(added, theirs[hunk.AfterStart+k])
+			}
+		}
+	}
+
+	result := make([]string, 0, len(ours)+len(added))
+
+	for _, line := range ours {
+		if queue := replaced[line]; len(queue) > 0 {
+			result = append(result, queue[0])
+			replaced[line] = queue[1:]
+
+			continue
+		}
+
+		if deleted[line] > 0 {
+			deleted[line]--
+
+			continue
+		}
+
+		result = append(result, line)
+	}
+
+	// ours 側でも変更されていたため置き換えられなかった外部の変更（競合）を追加
+	for _, lineBase := range base {
+		if queue := replaced[lineBase]; len(queue) > 0 {
+			result = append(result, queue...)
+			replaced[lineBase] = nil
+		}
+	}
+
+	return append(result, added...)
+}
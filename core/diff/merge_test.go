@@ -0,0 +1,51 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/diff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge3(t *testing.T) {
+	for _, test := range []struct {
+		msgErr string
+		base   []string
+		ours   []string
+		theirs []string
+		expect []string
+	}{
+		{
+			"no external change should return ours as is",
+			[]string{"a", "b", "c"},
+			[]string{"c", "a", "b"},
+			[]string{"a", "b", "c"},
+			[]string{"c", "a", "b"},
+		},
+		{
+			"external modification should be applied in the sorted position",
+			[]string{"a", "b", "c"},
+			[]string{"c", "b", "a"},
+			[]string{"a", "B", "c"},
+			[]string{"c", "B", "a"},
+		},
+		{
+			"external deletion and addition should be applied",
+			[]string{"a", "b", "c"},
+			[]string{"c", "b", "a"},
+			[]string{"a", "c", "d"},
+			[]string{"c", "a", "d"},
+		},
+		{
+			"conflicting modification should keep both changes",
+			[]string{"a", "b"},
+			[]string{"b", "A"},
+			[]string{"a2", "b"},
+			[]string{"b", "A", "a2"},
+		},
+	} {
+		actual := diff.Merge3(test.base, test.ours, test.theirs)
+
+		assert.Equal(t, test.expect, actual, test.msgErr)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Package estimate はタスクの見積り（ストーリー・ポイント）を扱うパッケージです。
+
+見積りはタスクの "est:" タグにフィボナッチ数列のポイントで記録されます。ポイン
+トは作業量の少ない順に並べたタスクの順位から、相対的に決められます。
+*/
+package estimate
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/1set/todotxt"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// TagEstimate はタスクの見積りのポイントを記録するタグ名です。（例: "est:5"）
+const TagEstimate = "est"
+
+// NameNoProject はプロジェクトのないタスクを集計する際のプロジェクト名です。
+const NameNoProject = "(なし)"
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// Scale は見積りに使うポイントの一覧（フィボナッチ数列）です。
+var Scale = []int{1, 2, 3, 5, 8, 13, 21}
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Sum はプロジェクトごとの見積りの集計です。
+type Sum struct {
+	Project   string // プロジェクト名（"+" は含まない）
+	Points    int    // 見積りのポイントの合計
+	Tasks     int    // タスクの数
+	Estimated int    // 見積り済みのタスクの数
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// Get は task の "est:" タグのポイントを返します。タグがない場合は 0 を返します。
+func Get(task *todotxt.Task) (int, error) {
+	value := task.AdditionalTags[TagEstimate]
+	if value == "" {
+		return 0, nil
+	}
+
+	points, err := strconv.Atoi(value)
+	if err != nil || points < 0 {
+		return 0, errors.Errorf("不正な見積りです: %v:%v", TagEstimate, value)
+	}
+
+	return points, nil
+}
+
+// Points は作業量の少ない順に並べた total 件のタスクのうち、rank 番目（0 スター
+// ト）のタスクのポイントを返します。
+//
+// 順位を Scale の要素数で等分し、作業量の少ない側から小さいポイントを割り当て
+// ます。total が Scale の要素数より少ない場合は、順位の割合に応じて Scale から
+// 間引いたポイントになります。
+func Points(rank int, total int) int {
+	if total < 1 || rank < 0 {
+		return Scale[0]
+	}
+
+	if rank >= total {
+		rank = total - 1
+	}
+
+	return Scale[rank*len(Scale)/total]
+}
+
+// Set は task の "est:" タグに points をセットします。
+func Set(task *todotxt.Task, points int) {
+	if task.AdditionalTags == nil {
+		task.AdditionalTags = map[string]string{}
+	}
+
+	task.AdditionalTags[TagEstimate] = strconv.Itoa(points)
+}
+
+// SumByProject は tasks の見積りをプロジェクトごとに集計して、プロジェクト名順
+// に返します。複数のプロジェクトを持つタスクは、それぞれのプロジェクトに集計さ
+// れます。プロジェクトのないタスクは NameNoProject に集計されます。
+func SumByProject(tasks []todotxt.Task) ([]Sum, error) {
+	sums := map[string]*Sum{}
+
+	for i := range tasks {
+		points, err := Get(&tasks[i])
+		if err != nil {
+			return nil, err
+		}
+
+		projects := tasks[i].Projects
+		if len(projects) == 0 {
+			projects = []string{NameNoProject}
+		}
+
+		for _, project := range projects {
+			if _, ok := sums[project]; !ok {
+				sums[project] = &Sum{Project: project}
+			}
+
+			sums[project].Tasks++
+			sums[project].Points += points
+
+			if points > 0 {
+				sums[project].Estimated++
+			}
+		}
+	}
+
+	result := make([]Sum, 0, len(sums))
+
+	for _, sum := range sums {
+		result = append(result, *sum)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Project < result[j].Project
+	})
+
+	return result, nil
+}
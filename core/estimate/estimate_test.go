@@ -0,0 +1,85 @@
+package estimate_test
+
+import (
+	"testing"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/estimate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoints(t *testing.T) {
+	for _, test := range []struct {
+		total  int
+		expect []int
+	}{
+		{1, []int{1}},
+		{2, []int{1, 5}},
+		{7, []int{1, 2, 3, 5, 8, 13, 21}},
+		{10, []int{1, 1, 2, 3, 3, 5, 8, 8, 13, 21}},
+	} {
+		actual := []int{}
+
+		for rank := 0; rank < test.total; rank++ {
+			actual = append(actual, estimate.Points(rank, test.total))
+		}
+
+		assert.Equal(t, test.expect, actual, "total: %v", test.total)
+	}
+
+	// Out of range
+	assert.Equal(t, 1, estimate.Points(0, 0))
+	assert.Equal(t, 1, estimate.Points(-1, 3))
+	assert.Equal(t, estimate.Points(2, 3), estimate.Points(5, 3), "rank over total should be the last rank")
+}
+
+func TestGet_Set(t *testing.T) {
+	task := &todotxt.Task{Todo: "no tags"}
+
+	points, err := estimate.Get(task)
+	require.NoError(t, err)
+	assert.Equal(t, 0, points)
+
+	estimate.Set(task, 8)
+
+	points, err = estimate.Get(task)
+	require.NoError(t, err)
+	assert.Equal(t, 8, points)
+	assert.Equal(t, "no tags est:8", task.String())
+}
+
+func TestGet_malformed(t *testing.T) {
+	task, err := todotxt.ParseTask("task est:large")
+	require.NoError(t, err)
+
+	_, err = estimate.Get(task)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "不正な見積りです")
+}
+
+func TestSumByProject(t *testing.T) {
+	tasks := todotxt.NewTaskList()
+
+	for _, line := range []string{
+		"task 1 +web est:3",
+		"task 2 +web +api est:5",
+		"task 3 +api",
+		"task 4 est:2",
+	} {
+		task, err := todotxt.ParseTask(line)
+		require.NoError(t, err)
+
+		tasks = append(tasks, *task)
+	}
+
+	sums, err := estimate.SumByProject(tasks)
+	require.NoError(t, err)
+
+	assert.Equal(t, []estimate.Sum{
+		{Project: estimate.NameNoProject, Points: 2, Tasks: 1, Estimated: 1},
+		{Project: "api", Points: 5, Tasks: 2, Estimated: 1},
+		{Project: "web", Points: 8, Tasks: 2, Estimated: 2},
+	}, sums)
+}
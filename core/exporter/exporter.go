@@ -0,0 +1,187 @@
+/*
+Package exporter は todo.txt 形式のタスクを外部の形式に変換して出力するパッケー
+ジです。
+
+形式ごとの出力関数（Formatter）は形式名で登録されており、Write で形式名を指定し
+て出力します。JSON と YAML は、タスクのすべての項目を Record として出力します。
+*/
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/1set/todotxt"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// 組み込みの形式名。
+const (
+	FormatJSON = "json" // Record の配列の JSON
+	FormatYAML = "yaml" // Record の配列の YAML
+	FormatICS  = "ics"  // iCalendar の VTODO
+	FormatOrg  = "org"  // Org-mode の見出し
+)
+
+// DateLayout は Record の日付の書式です。
+const DateLayout = "2006-01-02"
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// formatters は形式名と出力関数の一覧です。
+var formatters = map[string]Formatter{
+	FormatJSON: WriteJSON,
+	FormatYAML: WriteYAML,
+	FormatICS:  WriteICS,
+	FormatOrg:  WriteOrg,
+}
+
+// extensions はファイルの拡張子と形式名の対応です。
+var extensions = map[string]string{
+	".json": FormatJSON,
+	".yaml": FormatYAML,
+	".yml":  FormatYAML,
+	".ics":  FormatICS,
+	".org":  FormatOrg,
+}
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Formatter は tasks を w に出力する関数です。
+type Formatter func(w io.Writer, tasks []todotxt.Task) error
+
+// Record は出力用のタスクの全項目です。日付は DateLayout の書式で、未設定の項目
+// は省略されます。
+type Record struct {
+	ID            int               `json:"id" yaml:"id"`
+	Raw           string            `json:"raw" yaml:"raw"`
+	Todo          string            `json:"todo" yaml:"todo"`
+	Completed     bool              `json:"completed" yaml:"completed"`
+	Priority      string            `json:"priority,omitempty" yaml:"priority,omitempty"`
+	CompletedDate string            `json:"completed_date,omitempty" yaml:"completed_date,omitempty"`
+	CreatedDate   string            `json:"created_date,omitempty" yaml:"created_date,omitempty"`
+	DueDate       string            `json:"due_date,omitempty" yaml:"due_date,omitempty"`
+	Projects      []string          `json:"projects,omitempty" yaml:"projects,omitempty"`
+	Contexts      []string          `json:"contexts,omitempty" yaml:"contexts,omitempty"`
+	Tags          map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// DetectFormat はファイル名の拡張子から形式名を返します。不明な拡張子の場合は
+// FormatJSON を返します。
+func DetectFormat(pathFile string) string {
+	if format, ok := extensions[strings.ToLower(filepath.Ext(pathFile))]; ok {
+		return format
+	}
+
+	return FormatJSON
+}
+
+// Formats は登録されている形式名の一覧を名前順に返します。
+func Formats() []string {
+	result := make([]string, 0, len(formatters))
+
+	for name := range formatters {
+		result = append(result, name)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// NewRecord は task の全項目を Record にして返します。
+func NewRecord(task *todotxt.Task) Record {
+	record := Record{
+		ID:        task.ID,
+		Raw:       task.String(),
+		Todo:      task.Todo,
+		Completed: task.Completed,
+		Priority:  task.Priority,
+		Projects:  task.Projects,
+		Contexts:  task.Contexts,
+		Tags:      task.AdditionalTags,
+	}
+
+	if task.HasCompletedDate() {
+		record.CompletedDate = task.CompletedDate.Format(DateLayout)
+	}
+
+	if task.HasCreatedDate() {
+		record.CreatedDate = task.CreatedDate.Format(DateLayout)
+	}
+
+	if task.HasDueDate() {
+		record.DueDate = task.DueDate.Format(DateLayout)
+	}
+
+	if len(record.Tags) == 0 {
+		record.Tags = nil
+	}
+
+	return record
+}
+
+// Records は tasks の全項目を Record の一覧にして返します。
+func Records(tasks []todotxt.Task) []Record {
+	result := make([]Record, len(tasks))
+
+	for i := range tasks {
+		result[i] = NewRecord(&tasks[i])
+	}
+
+	return result
+}
+
+// Register は出力関数 formatter を形式名 name で登録します。同じ名前の形式があ
+// る場合は上書きされます。
+func Register(name string, formatter Formatter) {
+	formatters[strings.ToLower(name)] = formatter
+}
+
+// Write は tasks を format 形式で w に出力します。
+func Write(format string, w io.Writer, tasks []todotxt.Task) error {
+	formatter, ok := formatters[strings.ToLower(format)]
+	if !ok {
+		return errors.Errorf(
+			"未対応の形式です: %v（%v のいずれかを指定してください）", format, strings.Join(Formats(), ", "),
+		)
+	}
+
+	return formatter(w, tasks)
+}
+
+// WriteJSON は tasks を Record の配列の JSON として w に出力します。
+func WriteJSON(w io.Writer, tasks []todotxt.Task) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return errors.Wrap(encoder.Encode(Records(tasks)), "failed to encode tasks to JSON")
+}
+
+// WriteYAML は tasks を Record の配列の YAML として w に出力します。
+func WriteYAML(w io.Writer, tasks []todotxt.Task) error {
+	data, err := yaml.Marshal(Records(tasks))
+	if err != nil {
+		return errors.Wrap(err, "failed to encode tasks to YAML")
+	}
+
+	_, err = w.Write(data)
+
+	return errors.Wrap(err, "failed to write YAML")
+}
@@ -0,0 +1,117 @@
+package exporter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/exporter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+const dataTasks = "(A) 2021-01-01 write docs +docs @home id:d1 est:3 due:2021-02-01\n" +
+	"x 2021-01-03 2021-01-02 review\n"
+
+// parseTasks は data を todo.txt 形式のタスクの一覧に変換します。
+func parseTasks(t *testing.T, data string) []todotxt.Task {
+	t.Helper()
+
+	tasks := todotxt.NewTaskList()
+
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		task, err := todotxt.ParseTask(line)
+		require.NoError(t, err)
+
+		tasks.AddTask(task)
+	}
+
+	return tasks
+}
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, exporter.FormatJSON, exporter.DetectFormat(""))
+	assert.Equal(t, exporter.FormatJSON, exporter.DetectFormat("tasks.txt"))
+	assert.Equal(t, exporter.FormatYAML, exporter.DetectFormat("tasks.YML"))
+	assert.Equal(t, exporter.FormatICS, exporter.DetectFormat("/path/to/tasks.ics"))
+	assert.Equal(t, exporter.FormatOrg, exporter.DetectFormat("tasks.org"))
+}
+
+func TestNewRecord(t *testing.T) {
+	tasks := parseTasks(t, dataTasks)
+
+	assert.Equal(t, exporter.Record{
+		ID:          1,
+		Raw:         "(A) 2021-01-01 write docs @home +docs est:3 id:d1 due:2021-02-01",
+		Todo:        "write docs",
+		Priority:    "A",
+		CreatedDate: "2021-01-01",
+		DueDate:     "2021-02-01",
+		Projects:    []string{"docs"},
+		Contexts:    []string{"home"},
+		Tags:        map[string]string{"id": "d1", "est": "3"},
+	}, exporter.NewRecord(&tasks[0]))
+
+	assert.Equal(t, exporter.Record{
+		ID:            2,
+		Raw:           "x 2021-01-03 2021-01-02 review",
+		Todo:          "review",
+		Completed:     true,
+		CompletedDate: "2021-01-03",
+		CreatedDate:   "2021-01-02",
+	}, exporter.NewRecord(&tasks[1]))
+}
+
+func TestWrite_json(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, exporter.Write("JSON", &buf, parseTasks(t, dataTasks)))
+
+	records := []exporter.Record{}
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &records))
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "d1", records[0].Tags["id"])
+	assert.Equal(t, "2021-01-03", records[1].CompletedDate)
+	assert.NotContains(t, buf.String(), `"tags": null`, "empty fields should be omitted")
+}
+
+func TestWrite_yaml(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, exporter.Write(exporter.FormatYAML, &buf, parseTasks(t, dataTasks)))
+
+	records := []exporter.Record{}
+
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &records))
+
+	assert.Equal(t, exporter.Records(parseTasks(t, dataTasks)), records)
+}
+
+func TestWrite_unknown_format(t *testing.T) {
+	err := exporter.Write("xml", io.Discard, []todotxt.Task{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "未対応の形式です: xml（ics, json, org, yaml のいずれかを指定してください）")
+}
+
+func TestRegister(t *testing.T) {
+	exporter.Register("Count", func(w io.Writer, tasks []todotxt.Task) error {
+		_, err := io.WriteString(w, string(rune('0'+len(tasks))))
+
+		return err
+	})
+
+	assert.Contains(t, exporter.Formats(), "count")
+
+	var buf bytes.Buffer
+
+	require.NoError(t, exporter.Write("count", &buf, parseTasks(t, dataTasks)))
+
+	assert.Equal(t, "2", buf.String())
+}
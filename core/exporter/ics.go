@@ -0,0 +1,200 @@
+package exporter
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/clock"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// ICSProductID は出力する iCalendar の PRODID です。
+const ICSProductID = "-//Qithub-BOT//QiiTask//JA"
+
+// iCalendar に出力する QiiTask 独自のプロパティ名。
+const (
+	ICSPropContexts = "X-QIITASK-CONTEXTS" // コンテキストの一覧（カンマ区切り）
+	ICSPropTags     = "X-QIITASK-TAGS"     // key:value タグの一覧（カンマ区切り）
+	ICSPropRaw      = "X-QIITASK-RAW"      // todo.txt 形式の元の行
+)
+
+// icsLineMax は iCalendar の 1 行の最大のオクテット数です。（改行を除く）
+const icsLineMax = 75
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// ICSPriority は todo.txt の優先度（"A"〜"Z"）を iCalendar の PRIORITY（1〜9、
+// 1 が最高）に変換します。優先度がない場合は 0 を返します。"I" 以降は 9 になり
+// ます。
+func ICSPriority(priority string) int {
+	if len(priority) != 1 || priority < "A" || priority > "Z" {
+		return 0
+	}
+
+	if result := int(priority[0]-'A') + 1; result < 9 {
+		return result
+	}
+
+	return 9
+}
+
+// ICSUID は task の iCalendar の UID を返します。"id:" タグがある場合はその値
+// から、ない場合は作成日とタスクの本文のハッシュ値から作られます。ハッシュ値は
+// タスクの追加や並べ替えでは変わりませんが、本文を変更すると変わります。UID を
+// 完全に固定するには事前に "id:" タグを付与してください。
+// （todo.Todo.AssignTagIDs を参照）
+func ICSUID(task *todotxt.Task) string {
+	if id := task.AdditionalTags[todo.TagID]; id != "" {
+		return id + "@qiitask"
+	}
+
+	sum := sha256.Sum256([]byte(task.CreatedDate.Format(todotxt.DateLayout) + " " + task.Todo))
+
+	return fmt.Sprintf("task-%x@qiitask", sum[:8])
+}
+
+// WriteICS は tasks を iCalendar（RFC 5545）の VTODO として w に出力します。
+//
+// プロジェクトは CATEGORIES に、コンテキストとタグは QiiTask 独自のプロパティ
+// に出力されます。元の行も X-QIITASK-RAW に出力されるため、QiiTask で取り込む場
+// 合は標準のプロパティで表せない項目も復元できます。
+func WriteICS(w io.Writer, tasks []todotxt.Task) error {
+	stamp := clock.TimeNow().UTC().Format("20060102T150405Z")
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + ICSProductID,
+	}
+
+	for i := range tasks {
+		task := &tasks[i]
+
+		lines = append(lines,
+			"BEGIN:VTODO",
+			"UID:"+escapeICS(ICSUID(task)),
+			"DTSTAMP:"+stamp,
+			"SUMMARY:"+escapeICS(task.Todo),
+		)
+
+		if task.HasCreatedDate() {
+			lines = append(lines, "CREATED:"+task.CreatedDate.Format("20060102")+"T000000Z")
+		}
+
+		if task.HasDueDate() {
+			lines = append(lines, "DUE;VALUE=DATE:"+task.DueDate.Format("20060102"))
+		}
+
+		if priority := ICSPriority(task.Priority); priority > 0 {
+			lines = append(lines, fmt.Sprintf("PRIORITY:%d", priority))
+		}
+
+		if task.Completed {
+			lines = append(lines, "STATUS:COMPLETED")
+
+			if task.HasCompletedDate() {
+				lines = append(lines, "COMPLETED:"+task.CompletedDate.Format("20060102")+"T000000Z")
+			}
+		} else {
+			lines = append(lines, "STATUS:NEEDS-ACTION")
+		}
+
+		if len(task.Projects) > 0 {
+			lines = append(lines, "CATEGORIES:"+joinICS(task.Projects))
+		}
+
+		if len(task.Contexts) > 0 {
+			lines = append(lines, ICSPropContexts+":"+joinICS(task.Contexts))
+		}
+
+		if len(task.AdditionalTags) > 0 {
+			tags := []string{}
+
+			for key, value := range task.AdditionalTags {
+				tags = append(tags, key+":"+value)
+			}
+
+			sort.Strings(tags)
+
+			lines = append(lines, ICSPropTags+":"+joinICS(tags))
+		}
+
+		lines = append(lines,
+			ICSPropRaw+":"+escapeICS(task.String()),
+			"END:VTODO",
+		)
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	var builder strings.Builder
+
+	for _, line := range lines {
+		builder.WriteString(foldICS(line))
+		builder.WriteString("\r\n")
+	}
+
+	_, err := io.WriteString(w, builder.String())
+
+	return errors.Wrap(err, "failed to write iCalendar")
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// escapeICS は iCalendar の TEXT 型の値として value をエスケープします。
+func escapeICS(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+// foldICS は line を 75 オクテットごとに折り返します。マルチバイト文字の途中で
+// は折り返しません。
+func foldICS(line string) string {
+	var builder strings.Builder
+
+	size := 0
+
+	for _, r := range line {
+		width := len(string(r))
+
+		if size+width > icsLineMax {
+			builder.WriteString("\r\n ")
+
+			size = 1 // 行頭の空白
+		}
+
+		builder.WriteRune(r)
+
+		size += width
+	}
+
+	return builder.String()
+}
+
+// joinICS は values をエスケープしてカンマ区切りで返します。
+func joinICS(values []string) string {
+	escaped := make([]string, len(values))
+
+	for i, value := range values {
+		escaped[i] = escapeICS(value)
+	}
+
+	return strings.Join(escaped, ",")
+}
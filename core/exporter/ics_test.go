@@ -0,0 +1,94 @@
+package exporter_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Qithub-BOT/QiiTask/core/exporter"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestICSPriority(t *testing.T) {
+	assert.Equal(t, 0, exporter.ICSPriority(""))
+	assert.Equal(t, 0, exporter.ICSPriority("a"))
+	assert.Equal(t, 1, exporter.ICSPriority("A"))
+	assert.Equal(t, 3, exporter.ICSPriority("C"))
+	assert.Equal(t, 9, exporter.ICSPriority("I"))
+	assert.Equal(t, 9, exporter.ICSPriority("Z"))
+}
+
+func TestICSUID(t *testing.T) {
+	tasks := parseTasks(t, "2021-01-02 review\nwrite docs id:d1\n(B) 2021-01-02 review +docs touched:2021-02-01\n")
+
+	assert.Equal(t, "d1@qiitask", exporter.ICSUID(&tasks[1]))
+	assert.Equal(t, exporter.ICSUID(&tasks[0]), exporter.ICSUID(&tasks[2]),
+		"UID should not depend on the line, priority, projects or tags")
+
+	tasks[2].Todo = "review again"
+
+	assert.NotEqual(t, exporter.ICSUID(&tasks[0]), exporter.ICSUID(&tasks[2]))
+}
+
+func TestWriteICS(t *testing.T) {
+	testutil.MockTimeNow(t, time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC))
+
+	tasks := parseTasks(t, "(A) 2021-01-01 write docs; a, b +docs @home id:d1 est:3 due:2021-02-01\n"+
+		"x 2021-01-03 2021-01-02 review\n")
+
+	var buf bytes.Buffer
+
+	require.NoError(t, exporter.Write(exporter.FormatICS, &buf, tasks))
+
+	expect := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Qithub-BOT//QiiTask//JA",
+		"BEGIN:VTODO",
+		"UID:d1@qiitask",
+		"DTSTAMP:20210203T040506Z",
+		`SUMMARY:write docs\; a\, b`,
+		"CREATED:20210101T000000Z",
+		"DUE;VALUE=DATE:20210201",
+		"PRIORITY:1",
+		"STATUS:NEEDS-ACTION",
+		"CATEGORIES:docs",
+		"X-QIITASK-CONTEXTS:home",
+		"X-QIITASK-TAGS:est:3,id:d1",
+		`X-QIITASK-RAW:(A) 2021-01-01 write docs\; a\, b @home +docs est:3 id:d1 due`,
+		" :2021-02-01",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:task-108df8f15ba39449@qiitask",
+		"DTSTAMP:20210203T040506Z",
+		"SUMMARY:review",
+		"CREATED:20210102T000000Z",
+		"STATUS:COMPLETED",
+		"COMPLETED:20210103T000000Z",
+		"X-QIITASK-RAW:x 2021-01-03 2021-01-02 review",
+		"END:VTODO",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	assert.Equal(t, expect, buf.String())
+}
+
+func TestWriteICS_fold_multibyte(t *testing.T) {
+	tasks := parseTasks(t, strings.Repeat("あ", 60)+"\n")
+
+	var buf bytes.Buffer
+
+	require.NoError(t, exporter.WriteICS(&buf, tasks))
+
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, "line: %q", line)
+		assert.True(t, utf8.ValidString(line), "it should not split multibyte characters: %q", line)
+	}
+
+	assert.Contains(t, strings.ReplaceAll(buf.String(), "\r\n ", ""), "SUMMARY:"+strings.Repeat("あ", 60))
+}
@@ -0,0 +1,121 @@
+package exporter
+
+import (
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/1set/todotxt"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// OrgPropRaw は Org-mode のプロパティに出力する todo.txt 形式の元の行のプロパテ
+// ィ名です。
+const OrgPropRaw = "TODOTXT"
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// rxOrgTagInvalid は Org-mode のタグに使えない文字です。
+var rxOrgTagInvalid = regexp.MustCompile(`[^\p{L}\p{N}_@#%]`)
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// WriteOrg は tasks を Org-mode の見出しとして w に出力します。
+//
+// 完了済みのタスクは DONE、未完了のタスクは TODO になります。プロジェクトはその
+// ままの名前で、コンテキストは "@" 付きで見出しのタグになります。作成日・タグ・
+// 元の行はプロパティに出力されます。
+func WriteOrg(w io.Writer, tasks []todotxt.Task) error {
+	var builder strings.Builder
+
+	for i := range tasks {
+		task := &tasks[i]
+
+		// 見出し
+		heading := []string{"*", "TODO"}
+		if task.Completed {
+			heading[1] = "DONE"
+		}
+
+		if task.HasPriority() {
+			heading = append(heading, "[#"+task.Priority+"]")
+		}
+
+		heading = append(heading, task.Todo)
+
+		tags := []string{}
+
+		for _, project := range task.Projects {
+			tags = append(tags, orgTag(project))
+		}
+
+		for _, context := range task.Contexts {
+			tags = append(tags, "@"+orgTag(context))
+		}
+
+		if len(tags) > 0 {
+			heading = append(heading, ":"+strings.Join(tags, ":")+":")
+		}
+
+		builder.WriteString(strings.Join(heading, " ") + "\n")
+
+		// 計画（完了日・期日）
+		planning := []string{}
+
+		if task.HasCompletedDate() {
+			planning = append(planning, "CLOSED: ["+task.CompletedDate.Format("2006-01-02 Mon")+"]")
+		}
+
+		if task.HasDueDate() {
+			planning = append(planning, "DEADLINE: <"+task.DueDate.Format("2006-01-02 Mon")+">")
+		}
+
+		if len(planning) > 0 {
+			builder.WriteString("  " + strings.Join(planning, " ") + "\n")
+		}
+
+		// プロパティ
+		builder.WriteString("  :PROPERTIES:\n")
+
+		if task.HasCreatedDate() {
+			builder.WriteString("  :CREATED: [" + task.CreatedDate.Format("2006-01-02 Mon") + "]\n")
+		}
+
+		keys := make([]string, 0, len(task.AdditionalTags))
+
+		for key := range task.AdditionalTags {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			builder.WriteString("  :" + key + ": " + task.AdditionalTags[key] + "\n")
+		}
+
+		builder.WriteString("  :" + OrgPropRaw + ": " + task.String() + "\n")
+		builder.WriteString("  :END:\n")
+	}
+
+	_, err := io.WriteString(w, builder.String())
+
+	return errors.Wrap(err, "failed to write Org-mode")
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// orgTag は name の Org-mode のタグに使えない文字を "_" に置き換えて返します。
+func orgTag(name string) string {
+	return rxOrgTagInvalid.ReplaceAllString(name, "_")
+}
@@ -0,0 +1,36 @@
+package exporter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/exporter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteOrg(t *testing.T) {
+	tasks := parseTasks(t, "(A) 2021-01-01 write docs +docs @home id:d1 est:3 due:2021-02-01\n"+
+		"x 2021-01-03 review +c++\n")
+
+	var buf bytes.Buffer
+
+	require.NoError(t, exporter.Write(exporter.FormatOrg, &buf, tasks))
+
+	expect := "" +
+		"* TODO [#A] write docs :docs:@home:\n" +
+		"  DEADLINE: <2021-02-01 Mon>\n" +
+		"  :PROPERTIES:\n" +
+		"  :CREATED: [2021-01-01 Fri]\n" +
+		"  :est: 3\n" +
+		"  :id: d1\n" +
+		"  :TODOTXT: (A) 2021-01-01 write docs @home +docs est:3 id:d1 due:2021-02-01\n" +
+		"  :END:\n" +
+		"* DONE review :c__:\n" +
+		"  CLOSED: [2021-01-03 Sun]\n" +
+		"  :PROPERTIES:\n" +
+		"  :TODOTXT: x 2021-01-03 review +c++\n" +
+		"  :END:\n"
+
+	assert.Equal(t, expect, buf.String())
+}
@@ -0,0 +1,289 @@
+package importer
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/exporter"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// icsProperty は iCalendar の 1 行（プロパティ）です。
+type icsProperty struct {
+	name   string            // プロパティ名（大文字）
+	params map[string]string // パラメーター（名前は大文字）
+	value  string            // 値（エスケープされたまま）
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// ParseICS は iCalendar（RFC 5545）の VTODO をタスクに変換します。VTODO 以外の
+// コンポーネントは無視されます。
+//
+// SUMMARY は本文、DUE は期日、PRIORITY（1〜9）は優先度（"A"〜"I"）、STATUS が
+// COMPLETED の場合は完了、CATEGORIES はプロジェクトになります。QiiTask で出力し
+// た iCalendar（X-QIITASK-RAW あり）の場合は、コンテキストやタグなど標準のプロ
+// パティで表せない項目を元の行から復元します。標準のプロパティで表せる項目は、
+// 元の行よりプロパティの値が優先されます。
+//
+// 各タスクには、UID から作られる出典のタグ（"src:ics:UID"）が付きます。
+func ParseICS(data []byte) ([]todotxt.Task, error) {
+	result := []todotxt.Task{}
+
+	var props []icsProperty // VTODO 内のプロパティ。VTODO 外では nil
+
+	for _, line := range unfoldICS(string(data)) {
+		prop, ok := parseICSLine(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VTODO"):
+			props = []icsProperty{}
+		case prop.name == "END" && strings.EqualFold(prop.value, "VTODO"):
+			if props == nil {
+				continue
+			}
+
+			task, err := taskFromICS(props)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, *task)
+			props = nil
+		case props != nil:
+			props = append(props, prop)
+		}
+	}
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// parseICSDate は iCalendar の DATE もしくは DATE-TIME の値を日付に変換します。
+// UTC（"Z" 付き）の時刻はローカル時刻の日付になります。
+func parseICSDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	date := value
+
+	if strings.HasSuffix(value, "Z") {
+		parsed, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "不正な日時です: %v", value)
+		}
+
+		date = parsed.Local().Format("20060102")
+	}
+
+	if len(date) > 8 {
+		date = date[:8] // 時刻を捨てる
+	}
+
+	parsed, err := time.ParseInLocation("20060102", date, time.Local)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "不正な日付です: %v", value)
+	}
+
+	return parsed, nil
+}
+
+// parseICSLine は iCalendar の 1 行を名前・パラメーター・値に分けます。書式が不
+// 正な行の場合は false を返します。
+func parseICSLine(line string) (icsProperty, bool) {
+	index := strings.Index(line, ":")
+	if index < 1 {
+		return icsProperty{}, false
+	}
+
+	head := strings.Split(line[:index], ";")
+	prop := icsProperty{
+		name:   strings.ToUpper(head[0]),
+		params: map[string]string{},
+		value:  line[index+1:],
+	}
+
+	for _, param := range head[1:] {
+		if pair := strings.SplitN(param, "=", 2); len(pair) == 2 {
+			prop.params[strings.ToUpper(pair[0])] = strings.Trim(pair[1], `"`)
+		}
+	}
+
+	return prop, true
+}
+
+// splitICS はカンマ区切りの iCalendar の TEXT 型の値を分割し、エスケープを戻し
+// た一覧を返します。空の要素は含まれません。
+func splitICS(value string) []string {
+	result := []string{}
+	start := 0
+	isEscaped := false
+
+	for i, r := range value {
+		switch {
+		case isEscaped:
+			isEscaped = false
+		case r == '\\':
+			isEscaped = true
+		case r == ',':
+			if item := unescapeICS(value[start:i]); item != "" {
+				result = append(result, item)
+			}
+
+			start = i + 1
+		}
+	}
+
+	if item := unescapeICS(value[start:]); item != "" {
+		result = append(result, item)
+	}
+
+	return result
+}
+
+// taskFromICS は VTODO のプロパティからタスクを作成します。
+func taskFromICS(props []icsProperty) (*todotxt.Task, error) {
+	task := todotxt.Task{}
+	task.AdditionalTags = map[string]string{}
+
+	uid := ""
+
+	for _, prop := range props {
+		if prop.name == exporter.ICSPropRaw {
+			raw, err := parseLine(unescapeICS(prop.value))
+			if err != nil {
+				return nil, err
+			}
+
+			task = *raw
+
+			if task.AdditionalTags == nil {
+				task.AdditionalTags = map[string]string{}
+			}
+		}
+	}
+
+	isRaw := task.Todo != ""
+
+	// 標準のプロパティで表せる項目は、カレンダー・アプリで変更されている可能性が
+	// あるため、元の行よりプロパティの値を優先する
+	priorityRaw := task.Priority
+
+	if isRaw {
+		task.DueDate = time.Time{}
+		task.CreatedDate = time.Time{}
+		task.Completed = false
+		task.Projects = nil
+		task.Priority = ""
+	}
+
+	for _, prop := range props {
+		var err error
+
+		switch prop.name {
+		case "UID":
+			uid = unescapeICS(prop.value)
+		case "SUMMARY":
+			task.Todo = unescapeICS(prop.value)
+		case "DUE":
+			task.DueDate, err = parseICSDate(prop.value)
+		case "CREATED":
+			task.CreatedDate, err = parseICSDate(prop.value)
+		case "COMPLETED":
+			task.CompletedDate, err = parseICSDate(prop.value)
+		case "STATUS":
+			task.Completed = strings.EqualFold(prop.value, "COMPLETED")
+		case "PRIORITY":
+			priority, errAtoi := strconv.Atoi(strings.TrimSpace(prop.value))
+			if errAtoi != nil || priority < 1 || priority > 9 {
+				continue
+			}
+
+			// "I" 以降の優先度は同じ PRIORITY になるため、変更がない場合は元の優先度を使う
+			task.Priority = string(rune('A' + priority - 1))
+			if exporter.ICSPriority(priorityRaw) == priority {
+				task.Priority = priorityRaw
+			}
+		case "CATEGORIES":
+			task.Projects = append(task.Projects, nameAll(splitICS(prop.value))...)
+		case exporter.ICSPropContexts:
+			if !isRaw {
+				task.Contexts = append(task.Contexts, nameAll(splitICS(prop.value))...)
+			}
+		case exporter.ICSPropTags:
+			if !isRaw {
+				for _, tag := range splitICS(prop.value) {
+					if pair := strings.SplitN(tag, ":", 2); len(pair) == 2 {
+						task.AdditionalTags[pair[0]] = pair[1]
+					}
+				}
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if strings.TrimSpace(task.Todo) == "" {
+		return nil, errors.Errorf("VTODO に SUMMARY がありません（UID: %v）", uid)
+	}
+
+	if !task.Completed {
+		task.CompletedDate = time.Time{}
+	}
+
+	if uid != "" {
+		task.AdditionalTags[TagSource] = SourceICS + ":" + tagValue(uid)
+	}
+
+	return &task, nil
+}
+
+// unescapeICS は iCalendar の TEXT 型の値のエスケープを戻し、前後の空白を取り
+// 除いて返します。改行は空白になります。
+func unescapeICS(value string) string {
+	var builder strings.Builder
+
+	isEscaped := false
+
+	for _, r := range value {
+		switch {
+		case isEscaped:
+			if r == 'n' || r == 'N' {
+				r = ' '
+			}
+
+			builder.WriteRune(r)
+
+			isEscaped = false
+		case r == '\\':
+			isEscaped = true
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	return strings.TrimSpace(builder.String())
+}
+
+// unfoldICS は iCalendar の折り返された行を戻し、行の一覧を返します。
+func unfoldICS(data string) []string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+
+	return strings.Split(data, "\n")
+}
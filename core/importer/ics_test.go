@@ -0,0 +1,128 @@
+package importer_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/exporter"
+	"github.com/Qithub-BOT/QiiTask/core/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// taskListOf は todo.txt 形式の lines から ID 付きのタスクの一覧を作成します。
+func taskListOf(t *testing.T, lines ...string) todotxt.TaskList {
+	t.Helper()
+
+	tasks := todotxt.NewTaskList()
+
+	for _, line := range lines {
+		task, err := todotxt.ParseTask(line)
+		require.NoError(t, err)
+
+		tasks.AddTask(task)
+	}
+
+	return tasks
+}
+
+func TestParseICS(t *testing.T) {
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:event-1",
+		"SUMMARY:not a task",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"UID:todo-1@example.com",
+		"SUMMARY:Write\\, review and ",
+		" ship the docs",
+		"DUE;VALUE=DATE:20210201",
+		"PRIORITY:2",
+		"STATUS:NEEDS-ACTION",
+		"CATEGORIES:docs,Web Site",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:todo-2",
+		"SUMMARY:deploy",
+		"CREATED:20210101T000000",
+		"STATUS:COMPLETED",
+		"COMPLETED;VALUE=DATE:20210103",
+		"PRIORITY:0",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	tasks, err := importer.ParseICS([]byte(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"(B) Write, review and ship the docs +Web_Site +docs src:ics:todo-1@example.com due:2021-02-01",
+		"x 2021-01-03 2021-01-01 deploy src:ics:todo-2",
+	}, stringsOf(tasks))
+}
+
+func TestParseICS_round_trip(t *testing.T) {
+	tasks := taskListOf(t,
+		"(A) 2021-01-01 write docs, then review @home +docs est:3 id:d1 due:2021-02-01",
+		"x 2021-01-03 2021-01-02 review",
+	)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, exporter.WriteICS(&buf, tasks))
+
+	imported, err := importer.ParseICS(buf.Bytes())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"(A) 2021-01-01 write docs, then review @home +docs est:3 id:d1 src:ics:d1@qiitask due:2021-02-01",
+		"x 2021-01-03 2021-01-02 review src:ics:task-108df8f15ba39449@qiitask",
+	}, stringsOf(imported))
+}
+
+func TestParseICS_raw_with_changes(t *testing.T) {
+	tasks := taskListOf(t,
+		"(A) write docs @home +docs id:d1 due:2021-02-01",
+		"(J) low priority @work id:l1",
+	)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, exporter.WriteICS(&buf, tasks))
+
+	// Edit the standard properties as a calendar app would
+	data := buf.String()
+	data = strings.Replace(data, "SUMMARY:write docs", "SUMMARY:write the docs", 1)
+	data = strings.Replace(data, "DUE;VALUE=DATE:20210201", "DUE;VALUE=DATE:20210301", 1)
+	data = strings.Replace(data, "PRIORITY:1", "PRIORITY:2", 1)
+	data = strings.Replace(data, "STATUS:NEEDS-ACTION", "STATUS:COMPLETED", 1)
+	data = strings.Replace(data, "CATEGORIES:docs\r\n", "", 1)
+
+	imported, err := importer.ParseICS([]byte(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"x (B) write the docs @home id:d1 src:ics:d1@qiitask due:2021-03-01",
+		"(J) low priority @work id:l1 src:ics:l1@qiitask",
+	}, stringsOf(imported), "standard properties should take priority over the raw line")
+}
+
+func TestParseICS_errors(t *testing.T) {
+	for _, test := range []struct {
+		data     string
+		msgError string
+	}{
+		{"BEGIN:VTODO\nUID:u1\nEND:VTODO\n", "VTODO に SUMMARY がありません（UID: u1）"},
+		{"BEGIN:VTODO\nSUMMARY:task\nDUE:2021-02-01\nEND:VTODO\n", "不正な日付です: 2021-02-01"},
+		{"BEGIN:VTODO\nSUMMARY:task\nDUE:2021Z\nEND:VTODO\n", "不正な日時です: 2021Z"},
+	} {
+		_, err := importer.ParseICS([]byte(test.data))
+
+		require.Error(t, err, "data: %q", test.data)
+		assert.Contains(t, err.Error(), test.msgError)
+	}
+}
@@ -0,0 +1,355 @@
+/*
+Package importer は外部の形式のデータを todo.txt 形式のタスクに変換するパッケー
+ジです。
+
+形式ごとの変換関数（Parser）は形式名で登録されており、Parse で形式名を指定して
+変換します。
+*/
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/1set/todotxt"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// 組み込みの形式名。
+const (
+	FormatMarkdown    = "md"          // Markdown のチェックリスト（"- [ ] item"）
+	FormatCSV         = "csv"         // ヘッダー行付きの CSV
+	FormatLines       = "lines"       // 1 行 1 タスクのテキスト
+	FormatICS         = "ics"         // iCalendar の VTODO
+	FormatTaskwarrior = "taskwarrior" // Taskwarrior の "task export" の JSON
+)
+
+// TagSource は取り込んだタスクの出典を表すタグ名です。値は "出典:ID" の形式で、
+// 再度取り込んだ際に同じタスクを判別するために使われます。
+const TagSource = "src"
+
+// 出典のタグの値の接頭辞。
+const (
+	SourceICS         = "ics" // iCalendar の UID
+	SourceTaskwarrior = "tw"  // Taskwarrior の uuid
+)
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// parsers は形式名と変換関数の一覧です。
+var parsers = map[string]Parser{
+	FormatMarkdown:    ParseMarkdown,
+	FormatCSV:         ParseCSV,
+	FormatLines:       ParseLines,
+	FormatICS:         ParseICS,
+	FormatTaskwarrior: ParseTaskwarrior,
+}
+
+// extensions はファイルの拡張子と形式名の対応です。
+var extensions = map[string]string{
+	".md":       FormatMarkdown,
+	".markdown": FormatMarkdown,
+	".csv":      FormatCSV,
+	".ics":      FormatICS,
+	".json":     FormatTaskwarrior,
+}
+
+// columns は CSV のヘッダー名（小文字）と、タスクの項目名の対応です。
+var columns = map[string]string{
+	"todo":      "todo",
+	"task":      "todo",
+	"title":     "todo",
+	"name":      "todo",
+	"タスク":       "todo",
+	"priority":  "priority",
+	"pri":       "priority",
+	"優先度":       "priority",
+	"project":   "project",
+	"projects":  "project",
+	"プロジェクト":    "project",
+	"context":   "context",
+	"contexts":  "context",
+	"コンテキスト":    "context",
+	"due":       "due",
+	"期日":        "due",
+	"created":   "created",
+	"作成日":       "created",
+	"done":      "done",
+	"completed": "done",
+	"status":    "done",
+	"完了":        "done",
+}
+
+var rxChecklist = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(.*\S)\s*$`)
+
+// rxSpace はプロジェクト名やタグの値に使えない空白です。
+var rxSpace = regexp.MustCompile(`\s+`)
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Parser は data を todo.txt 形式のタスクに変換する関数です。
+type Parser func(data []byte) ([]todotxt.Task, error)
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// DetectFormat はファイル名の拡張子から形式名を返します。不明な拡張子の場合は
+// FormatLines を返します。
+func DetectFormat(pathFile string) string {
+	if format, ok := extensions[strings.ToLower(filepath.Ext(pathFile))]; ok {
+		return format
+	}
+
+	return FormatLines
+}
+
+// Formats は登録されている形式名の一覧を名前順に返します。
+func Formats() []string {
+	result := make([]string, 0, len(parsers))
+
+	for name := range parsers {
+		result = append(result, name)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// Parse は format 形式の data を todo.txt 形式のタスクに変換します。
+func Parse(format string, data []byte) ([]todotxt.Task, error) {
+	parser, ok := parsers[strings.ToLower(format)]
+	if !ok {
+		return nil, errors.Errorf(
+			"未対応の形式です: %v（%v のいずれかを指定してください）", format, strings.Join(Formats(), ", "),
+		)
+	}
+
+	return parser(data)
+}
+
+// Register は変換関数 parser を形式名 name で登録します。同じ名前の形式がある場
+// 合は上書きされます。
+func Register(name string, parser Parser) {
+	parsers[strings.ToLower(name)] = parser
+}
+
+// ParseCSV はヘッダー行付きの CSV をタスクに変換します。
+//
+// 列はヘッダー名（大文字・小文字は区別しません）で対応付けられます。タスクの本
+// 文の列（"todo", "task", "title" など）は必須です。その他に "priority",
+// "project", "context", "due", "created", "done" の列に対応しています。プロジェ
+// クトとコンテキストは空白もしくはカンマ区切りで複数指定できます。対応していない
+// 列は無視されます。
+func ParseCSV(data []byte) ([]todotxt.Task, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []todotxt.Task{}, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read CSV header")
+	}
+
+	indexes := map[string]int{}
+
+	for i, name := range header {
+		if field, ok := columns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			if _, exists := indexes[field]; !exists {
+				indexes[field] = i
+			}
+		}
+	}
+
+	if _, ok := indexes["todo"]; !ok {
+		return nil, errors.New("CSV にタスクの本文の列（todo, task, title など）がありません")
+	}
+
+	result := []todotxt.Task{}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read CSV record")
+		}
+
+		value := func(field string) string {
+			if i, ok := indexes[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+
+			return ""
+		}
+
+		if value("todo") == "" {
+			continue
+		}
+
+		task, err := parseLine(lineFromFields(
+			isTruthy(value("done")), value("priority"), value("created"), value("todo"),
+			prefixAll("+", value("project")), prefixAll("@", value("context")), value("due"),
+		))
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, *task)
+	}
+
+	return result, nil
+}
+
+// ParseLines は 1 行 1 タスクのテキストをタスクに変換します。各行は todo.txt 形
+// 式として解釈されます。空行は無視されます。
+func ParseLines(data []byte) ([]todotxt.Task, error) {
+	result := []todotxt.Task{}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+
+		task, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, *task)
+	}
+
+	return result, nil
+}
+
+// ParseMarkdown は Markdown のチェックリストの行（"- [ ] item" および
+// "- [x] item"）をタスクに変換します。チェック済みの項目は完了済みのタスクにな
+// ります。チェックリスト以外の行は無視されます。
+func ParseMarkdown(data []byte) ([]todotxt.Task, error) {
+	result := []todotxt.Task{}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		match := rxChecklist.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		text := match[2]
+		if match[1] != " " {
+			text = "x " + text
+		}
+
+		task, err := parseLine(text)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, *task)
+	}
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// isTruthy は value が完了を表す値の場合に true を返します。
+func isTruthy(value string) bool {
+	switch strings.ToLower(value) {
+	case "x", "1", "true", "yes", "y", "done", "completed", "完了", "済":
+		return true
+	}
+
+	return false
+}
+
+// lineFromFields は項目ごとの値から todo.txt 形式の 1 行を組み立てます。空の項
+// 目は含まれません。
+func lineFromFields(isDone bool, priority, created, text, projects, contexts, due string) string {
+	items := []string{}
+
+	if isDone {
+		items = append(items, "x")
+	}
+
+	// 優先度は英字 1 文字のみ（"high" などは無視する）
+	if priority = strings.ToUpper(strings.Trim(priority, "() ")); len(priority) == 1 && "A" <= priority && priority <= "Z" {
+		items = append(items, "("+priority+")")
+	}
+
+	for _, item := range []string{created, text, projects, contexts} {
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	if due != "" {
+		items = append(items, "due:"+due)
+	}
+
+	return strings.Join(items, " ")
+}
+
+// nameAll は names の各要素の空白を "_" に置き換えた、プロジェクト名もしくは
+// コンテキスト名として使える一覧を返します。空の要素は含まれません。
+func nameAll(names []string) []string {
+	result := []string{}
+
+	for _, name := range names {
+		if name = tagValue(name); name != "" {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
+
+// parseLine は todo.txt 形式の 1 行をタスクに変換します。
+func parseLine(line string) (*todotxt.Task, error) {
+	task, err := todotxt.ParseTask(line)
+	if err != nil {
+		return nil, errors.Wrapf(err, "タスクに変換できません: %v", line)
+	}
+
+	return task, nil
+}
+
+// prefixAll は空白もしくはカンマ区切りの values の各要素に、prefix がなければ付
+// けて空白区切りで返します。
+func prefixAll(prefix string, values string) string {
+	result := []string{}
+
+	for _, value := range strings.FieldsFunc(values, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	}) {
+		result = append(result, prefix+strings.TrimPrefix(value, prefix))
+	}
+
+	return strings.Join(result, " ")
+}
+
+// tagValue は value の前後の空白を取り除き、途中の空白を "_" に置き換えて、タグ
+// の値として使える文字列を返します。
+func tagValue(value string) string {
+	return rxSpace.ReplaceAllString(strings.TrimSpace(value), "_")
+}
@@ -0,0 +1,111 @@
+package importer_test
+
+import (
+	"testing"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stringsOf は tasks を todo.txt 形式の文字列の一覧にして返します。
+func stringsOf(tasks []todotxt.Task) []string {
+	result := []string{}
+
+	for i := range tasks {
+		result = append(result, tasks[i].String())
+	}
+
+	return result
+}
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, importer.FormatMarkdown, importer.DetectFormat("README.md"))
+	assert.Equal(t, importer.FormatMarkdown, importer.DetectFormat("note.Markdown"))
+	assert.Equal(t, importer.FormatCSV, importer.DetectFormat("/path/to/TASKS.CSV"))
+	assert.Equal(t, importer.FormatLines, importer.DetectFormat("memo.txt"))
+	assert.Equal(t, importer.FormatLines, importer.DetectFormat("memo"))
+	assert.Equal(t, importer.FormatICS, importer.DetectFormat("calendar.ics"))
+	assert.Equal(t, importer.FormatTaskwarrior, importer.DetectFormat("export.json"))
+}
+
+func TestParse_markdown(t *testing.T) {
+	data := "# TODO\n\n- [ ] write docs +docs\n  * [x] (A) review\n+ [X] deploy due:2021-02-01\n- not a checklist\n- [ ]\n"
+
+	tasks, err := importer.Parse("MD", []byte(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"write docs +docs",
+		"x (A) review",
+		"x deploy due:2021-02-01",
+	}, stringsOf(tasks))
+	assert.True(t, tasks[1].Completed)
+}
+
+func TestParse_csv(t *testing.T) {
+	data := "\xef\xbb\xbfTitle,Priority,Projects,Context,Due,Done,Memo\n" +
+		"write docs,a,\"docs, web\",home,2021-02-01,,ignored\n" +
+		"review,high,,@work,,yes\n" +
+		",B,,,,,empty title is skipped\n" +
+		"short row\n"
+
+	tasks, err := importer.Parse(importer.FormatCSV, []byte(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"(A) write docs @home +docs +web due:2021-02-01",
+		"x review @work",
+		"short row",
+	}, stringsOf(tasks))
+}
+
+func TestParse_csv_errors(t *testing.T) {
+	_, err := importer.Parse(importer.FormatCSV, []byte("memo,priority\nfoo,A\n"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "タスクの本文の列")
+
+	_, err = importer.Parse(importer.FormatCSV, []byte("todo\n\"broken\n"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read CSV record")
+
+	tasks, err := importer.Parse(importer.FormatCSV, []byte{})
+
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+}
+
+func TestParse_lines(t *testing.T) {
+	tasks, err := importer.Parse(importer.FormatLines, []byte("(B) first +proj\r\n\n  second  \n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"(B) first +proj", "second"}, stringsOf(tasks))
+
+	_, err = importer.Parse(importer.FormatLines, []byte("bad due:2021-13-01\n"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "タスクに変換できません")
+}
+
+func TestParse_unknown_format(t *testing.T) {
+	_, err := importer.Parse("xml", []byte("<task/>"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "未対応の形式です: xml（csv, ics, lines, md, taskwarrior のいずれかを指定してください）")
+}
+
+func TestRegister(t *testing.T) {
+	importer.Register("Upper", func(data []byte) ([]todotxt.Task, error) {
+		return []todotxt.Task{{Todo: string(data)}}, nil
+	})
+
+	assert.Contains(t, importer.Formats(), "upper")
+
+	tasks, err := importer.Parse("upper", []byte("custom"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"custom"}, stringsOf(tasks))
+}
@@ -0,0 +1,170 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/1set/todotxt"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// twPriorities は Taskwarrior の優先度と todo.txt の優先度の対応です。
+var twPriorities = map[string]string{
+	"H": "A",
+	"M": "B",
+	"L": "C",
+}
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// twTask は Taskwarrior の "task export" の 1 タスクのうち、取り込む項目です。
+type twTask struct {
+	UUID        string   `json:"uuid"`
+	Description string   `json:"description"`
+	Status      string   `json:"status"`
+	Entry       string   `json:"entry"`
+	Due         string   `json:"due"`
+	End         string   `json:"end"`
+	Priority    string   `json:"priority"`
+	Project     string   `json:"project"`
+	Tags        []string `json:"tags"`
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// ParseTaskwarrior は Taskwarrior の "task export" の JSON をタスクに変換します。
+// JSON の配列の他に、古いバージョンの 1 行 1 タスクの形式にも対応しています。
+//
+// description は本文、due は期日、entry は作成日、priority（H/M/L）は優先度
+// （"A"〜"C"）、project はプロジェクト、tags はコンテキストになります。status が
+// completed のタスクは完了済みになり、deleted および recurring（繰り返しの親）の
+// タスクは取り込みません。
+//
+// 各タスクには、uuid から作られる出典のタグ（"src:tw:UUID"）が付きます。
+func ParseTaskwarrior(data []byte) ([]todotxt.Task, error) {
+	items, err := decodeTaskwarrior(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []todotxt.Task{}
+
+	for _, item := range items {
+		if item.Status == "deleted" || item.Status == "recurring" {
+			continue
+		}
+
+		task, err := taskFromTaskwarrior(item)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, *task)
+	}
+
+	return result, nil
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// decodeTaskwarrior は JSON の配列もしくは 1 行 1 オブジェクトの data を読み込み
+// ます。
+func decodeTaskwarrior(data []byte) ([]twTask, error) {
+	data = bytes.TrimSpace(data)
+	result := []twTask{}
+
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, errors.Wrap(err, "Taskwarrior の JSON として読み込めません")
+		}
+
+		return result, nil
+	}
+
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if line = bytes.TrimRight(bytes.TrimSpace(line), ","); len(line) == 0 {
+			continue
+		}
+
+		item := twTask{}
+
+		if err := json.Unmarshal(line, &item); err != nil {
+			return nil, errors.Wrap(err, "Taskwarrior の JSON として読み込めません")
+		}
+
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+// parseTWDate は Taskwarrior の日時（"20060102T150405Z"）をローカル時刻の日付に
+// 変換します。
+func parseTWDate(value string) (time.Time, error) {
+	parsed, err := time.Parse("20060102T150405Z", value)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "不正な日時です: %v", value)
+	}
+
+	year, month, day := parsed.Local().Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local), nil
+}
+
+// taskFromTaskwarrior は Taskwarrior のタスクから todo.txt 形式のタスクを作成し
+// ます。
+func taskFromTaskwarrior(item twTask) (*todotxt.Task, error) {
+	task := todotxt.Task{}
+	task.AdditionalTags = map[string]string{}
+	task.Todo = strings.Join(strings.Fields(item.Description), " ")
+
+	if task.Todo == "" {
+		return nil, errors.Errorf("タスクに description がありません（uuid: %v）", item.UUID)
+	}
+
+	task.Priority = twPriorities[strings.ToUpper(item.Priority)]
+	task.Completed = item.Status == "completed"
+	task.Projects = nameAll([]string{item.Project})
+	task.Contexts = nameAll(item.Tags)
+
+	var err error
+
+	for _, date := range []struct {
+		value  string
+		target *time.Time
+	}{
+		{item.Entry, &task.CreatedDate},
+		{item.Due, &task.DueDate},
+		{item.End, &task.CompletedDate},
+	} {
+		if date.value == "" {
+			continue
+		}
+
+		if *date.target, err = parseTWDate(date.value); err != nil {
+			return nil, err
+		}
+	}
+
+	if !task.Completed {
+		task.CompletedDate = time.Time{}
+	}
+
+	if item.UUID != "" {
+		task.AdditionalTags[TagSource] = SourceTaskwarrior + ":" + tagValue(item.UUID)
+	}
+
+	return &task, nil
+}
@@ -0,0 +1,55 @@
+package importer_test
+
+import (
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaskwarrior(t *testing.T) {
+	data := `[
+{"id":1,"description":"write  docs","entry":"20210101T120000Z","due":"20210201T120000Z","priority":"H","project":"home.garden","status":"pending","tags":["home","next step"],"uuid":"a1"},
+{"id":0,"description":"review","end":"20210103T120000Z","status":"completed","uuid":"b2"},
+{"id":0,"description":"removed","status":"deleted","uuid":"c3"},
+{"id":0,"description":"weekly","status":"recurring","uuid":"d4"},
+{"id":2,"description":"waiting","status":"waiting","priority":"L","uuid":"e5"}
+]`
+
+	tasks, err := importer.Parse(importer.FormatTaskwarrior, []byte(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"(A) 2021-01-01 write docs @home @next_step +home.garden src:tw:a1 due:2021-02-01",
+		"x 2021-01-03 review src:tw:b2",
+		"(C) waiting src:tw:e5",
+	}, stringsOf(tasks))
+}
+
+func TestParseTaskwarrior_lines(t *testing.T) {
+	data := "{\"description\":\"first\",\"status\":\"pending\",\"uuid\":\"a1\"},\n" +
+		"{\"description\":\"second\",\"status\":\"pending\",\"priority\":\"M\"}\n"
+
+	tasks, err := importer.ParseTaskwarrior([]byte(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first src:tw:a1", "(B) second"}, stringsOf(tasks))
+}
+
+func TestParseTaskwarrior_errors(t *testing.T) {
+	for _, test := range []struct {
+		data     string
+		msgError string
+	}{
+		{`[{"description":"task",}]`, "Taskwarrior の JSON として読み込めません"},
+		{`not json`, "Taskwarrior の JSON として読み込めません"},
+		{`[{"description":" ","uuid":"a1"}]`, "タスクに description がありません（uuid: a1）"},
+		{`[{"description":"task","due":"2021-02-01"}]`, "不正な日時です: 2021-02-01"},
+	} {
+		_, err := importer.ParseTaskwarrior([]byte(test.data))
+
+		require.Error(t, err, "data: %v", test.data)
+		assert.Contains(t, err.Error(), test.msgError)
+	}
+}
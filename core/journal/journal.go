@@ -0,0 +1,396 @@
+/*
+Package journal はタスクや設定ファイルの変更履歴（ジャーナル）を管理するパッケージです。
+
+ファイルが保存されるたびに、保存前後の差分がファイルと同じワークスペースの
+".qiitask" ディレクトリにある "journal.json" に記録されます。記録された差分を
+使って、変更の取り消し（Undo）およびやり直し（Redo）ができます。
+
+ジャーナルは同じディレクトリの複数のファイル（タスク・リストや設定ファイルな
+ど）で共有されるため、取り消しとやり直しは対象のファイルの履歴ごとに行われます。
+*/
+package journal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/clock"
+	"github.com/Qithub-BOT/QiiTask/core/diff"
+	"github.com/Qithub-BOT/QiiTask/core/safefile"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// NameFile はジャーナルのファイル名です。
+const NameFile = "journal.json"
+
+// MaxEntries はジャーナルに残す履歴の最大数です。これを超えると古い履歴から削除
+// されます。
+const MaxEntries = 100
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// Operation は現在実行中の操作名（コマンド名）です。Record で記録される履歴の操
+// 作名として使われます。通常、root コマンドがサブコマンドの実行前にセットします。
+var Operation = ""
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Change は 1 つの変更区間です。BeforeStart 行目からの Before の行が、AfterStart
+// 行目からの After の行に置き換わったことを表します。（行番号は 0 スタート）
+type Change struct {
+	BeforeStart int      `json:"before_start"`
+	Before      []string `json:"before"`
+	AfterStart  int      `json:"after_start"`
+	After       []string `json:"after"`
+}
+
+// Entry はジャーナルの 1 件の履歴です。Undone が true の履歴は Undo 済みで Redo
+// 可能な履歴です。
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	File      string    `json:"file"`
+	Changes   []Change  `json:"changes"`
+	Undone    bool      `json:"undone,omitempty"`
+}
+
+// Journal は変更履歴の一覧を保持する型です。履歴は古い順に並び、ファイルごとに
+// Undo 済みの履歴は Undo されていない履歴よりも後ろにあります。
+type Journal struct {
+	Entries  []Entry `json:"entries"`
+	pathFile string  // ジャーナル・ファイルのパス
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// Load は pathFileTarget（タスクや設定ファイル）の変更履歴を記録したジャーナル
+// を読み込みます。ジャーナル・ファイルが存在しない場合は空のジャーナルを返します。
+func Load(pathFileTarget string) (*Journal, error) {
+	journal := &Journal{
+		Entries:  []Entry{},
+		pathFile: PathJournal(pathFileTarget),
+	}
+
+	if !util.IsFile(journal.pathFile) {
+		return journal, nil
+	}
+
+	data, err := os.ReadFile(journal.pathFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read journal file")
+	}
+
+	if err := json.Unmarshal(data, journal); err != nil {
+		return nil, errors.Wrap(err, "failed to parse journal file")
+	}
+
+	// 以前の形式（全ファイル共通の位置）で記録されたジャーナルの Undo 済みの履歴
+	legacy := struct {
+		Position *int `json:"position"`
+	}{}
+
+	if err := json.Unmarshal(data, &legacy); err == nil && legacy.Position != nil {
+		for i := *legacy.Position; i >= 0 && i < len(journal.Entries); i++ {
+			journal.Entries[i].Undone = true
+		}
+	}
+
+	return journal, nil
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// PathJournal は pathFileTarget の変更履歴を記録するジャーナル・ファイルのパスを
+// 返します。pathFileTarget が ".qiitask" もしくは "qiitask" ディレクトリ下にある
+// 場合はそのディレクトリ、それ以外は同階層の ".qiitask" ディレクトリ下になります。
+func PathJournal(pathFileTarget string) string {
+	pathDir := filepath.Dir(pathFileTarget)
+
+	switch filepath.Base(pathDir) {
+	case ".qiitask", "qiitask":
+		return filepath.Join(pathDir, NameFile)
+	default:
+		return filepath.Join(pathDir, ".qiitask", NameFile)
+	}
+}
+
+// Record は pathFileTarget が before から after の内容に変更されたことを、ジャー
+// ナルに記録します。内容に変更がない場合は何もしません。
+func Record(pathFileTarget string, before, after []byte) error {
+	if string(before) == string(after) {
+		return nil
+	}
+
+	pathFileAbs, err := filepath.Abs(pathFileTarget)
+	if err != nil {
+		return errors.Wrap(err, "failed to get absolute path")
+	}
+
+	journal, err := Load(pathFileAbs)
+	if err != nil {
+		return err
+	}
+
+	journal.Add(Entry{
+		Time:      clock.TimeNow(),
+		Operation: Operation,
+		File:      pathFileAbs,
+		Changes:   NewChanges(before, after),
+	})
+
+	return journal.Save()
+}
+
+// NewChanges は before と after の差分を Change のスライスで返します。
+func NewChanges(before, after []byte) []Change {
+	linesBefore := splitLines(before)
+	linesAfter := splitLines(after)
+	changes := []Change{}
+
+	for _, hunk := range diff.Lines(linesBefore, linesAfter) {
+		changes = append(changes, Change{
+			BeforeStart: hunk.BeforeStart,
+			Before:      linesBefore[hunk.BeforeStart:hunk.BeforeEnd],
+			AfterStart:  hunk.AfterStart,
+			After:       linesAfter[hunk.AfterStart:hunk.AfterEnd],
+		})
+	}
+
+	return changes
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Add は entry を履歴の最後に追加します。entry と同じファイルの Undo 済みの履歴
+// （Redo 可能な履歴）は破棄されます。
+func (j *Journal) Add(entry Entry) {
+	entries := []Entry{}
+
+	for _, item := range j.Entries {
+		if item.Undone && item.File == entry.File {
+			continue
+		}
+
+		entries = append(entries, item)
+	}
+
+	entries = append(entries, entry)
+
+	if len(entries) > MaxEntries {
+		entries = entries[len(entries)-MaxEntries:]
+	}
+
+	j.Entries = entries
+}
+
+// CanRedo は pathFileTarget に Redo 可能な履歴がある場合に true を返します。
+func (j *Journal) CanRedo(pathFileTarget string) bool {
+	return j.indexRedo(pathFileTarget) >= 0
+}
+
+// CanUndo は pathFileTarget に Undo 可能な履歴がある場合に true を返します。
+func (j *Journal) CanUndo(pathFileTarget string) bool {
+	return j.indexUndo(pathFileTarget) >= 0
+}
+
+// PathFile はジャーナル・ファイルのパスを返します。
+func (j *Journal) PathFile() string {
+	return j.pathFile
+}
+
+// Redo は pathFileTarget に最後に Undo した変更をやり直し、その履歴を返します。
+//
+// ファイルの保存時には numBackup 個までバックアップが作成されます。ファイルの内
+// 容が Undo 時と異なる場合はエラーを返します。
+func (j *Journal) Redo(pathFileTarget string, numBackup int) (*Entry, error) {
+	index := j.indexRedo(pathFileTarget)
+	if index < 0 {
+		return nil, errors.New("やり直せる変更はありません")
+	}
+
+	entry := j.Entries[index]
+
+	if err := entry.apply(false, numBackup); err != nil {
+		return nil, err
+	}
+
+	j.Entries[index].Undone = false
+	entry.Undone = false
+
+	return &entry, j.Save()
+}
+
+// Save はジャーナルをファイルに保存します。
+func (j *Journal) Save() error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal journal")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.pathFile), 0o755); err != nil {
+		return errors.Wrap(err, "failed to create journal directory")
+	}
+
+	return errors.Wrap(safefile.WriteFile(j.pathFile, data, 0), "failed to write journal file")
+}
+
+// Undo は pathFileTarget への最後の変更を取り消し、その履歴を返します。同じジャ
+// ーナルに記録された他のファイルの履歴は変わりません。
+//
+// ファイルの保存時には numBackup 個までバックアップが作成されます。ファイルの内
+// 容が記録時と異なる（記録されていない変更がある）場合はエラーを返します。
+func (j *Journal) Undo(pathFileTarget string, numBackup int) (*Entry, error) {
+	index := j.indexUndo(pathFileTarget)
+	if index < 0 {
+		return nil, errors.New("取り消せる変更はありません")
+	}
+
+	entry := j.Entries[index]
+
+	if err := entry.apply(true, numBackup); err != nil {
+		return nil, err
+	}
+
+	j.Entries[index].Undone = true
+	entry.Undone = true
+
+	return &entry, j.Save()
+}
+
+// indexRedo は pathFileTarget の Redo する履歴（最も古い Undo 済みの履歴）の位置
+// を返します。ない場合は -1 を返します。
+func (j *Journal) indexRedo(pathFileTarget string) int {
+	pathFileAbs := absPath(pathFileTarget)
+
+	for i, entry := range j.Entries {
+		if entry.Undone && entry.File == pathFileAbs {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// indexUndo は pathFileTarget の Undo する履歴（最も新しい Undo されていない履
+// 歴）の位置を返します。ない場合は -1 を返します。
+func (j *Journal) indexUndo(pathFileTarget string) int {
+	pathFileAbs := absPath(pathFileTarget)
+
+	for i := len(j.Entries) - 1; i >= 0; i-- {
+		if entry := j.Entries[i]; !entry.Undone && entry.File == pathFileAbs {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// CountLines は履歴の追加行数と削除行数を返します。
+func (e *Entry) CountLines() (added int, deleted int) {
+	for _, change := range e.Changes {
+		added += len(change.After)
+		deleted += len(change.Before)
+	}
+
+	return added, deleted
+}
+
+// apply は履歴の変更をファイルに適用します。reverse が true の場合は変更を取り
+// 消します。
+func (e *Entry) apply(reverse bool, numBackup int) error {
+	current := []byte{}
+
+	if util.IsFile(e.File) {
+		data, err := os.ReadFile(e.File)
+		if err != nil {
+			return errors.Wrap(err, "failed to read file")
+		}
+
+		current = data
+	}
+
+	patched, err := patch(splitLines(current), e.Changes, reverse)
+	if err != nil {
+		return errors.Wrapf(err, "ファイルが変更されているため適用できません: %v", e.File)
+	}
+
+	return errors.Wrap(
+		safefile.WriteFile(e.File, []byte(strings.Join(patched, "\n")), numBackup),
+		"failed to write file",
+	)
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// absPath は pathFile の絶対パスを返します。取得できない場合は pathFile を整形
+// して返します。
+func absPath(pathFile string) string {
+	pathFileAbs, err := filepath.Abs(pathFile)
+	if err != nil {
+		return filepath.Clean(pathFile)
+	}
+
+	return pathFileAbs
+}
+
+// patch は lines に changes を適用した結果を返します。reverse が true の場合は
+// After を Before に戻します。lines の内容が changes と一致しない場合はエラーを
+// 返します。
+func patch(lines []string, changes []Change, reverse bool) ([]string, error) {
+	result := []string{}
+	pos := 0
+
+	for _, change := range changes {
+		start, from, to := change.BeforeStart, change.Before, change.After
+		if reverse {
+			start, from, to = change.AfterStart, change.After, change.Before
+		}
+
+		if start < pos || start+len(from) > len(lines) {
+			return nil, errors.New("line position mismatch")
+		}
+
+		result = append(result, lines[pos:start]...)
+
+		for i, line := range from {
+			if lines[start+i] != line {
+				return nil, errors.Errorf("line mismatch: %q", lines[start+i])
+			}
+		}
+
+		result = append(result, to...)
+		pos = start + len(from)
+	}
+
+	return append(result, lines[pos:]...), nil
+}
+
+// splitLines は data を改行で分割します。末尾の改行の有無も保持されるため、
+// strings.Join(lines, "\n") で元の内容に戻せます。
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return []string{}
+	}
+
+	return strings.Split(string(data), "\n")
+}
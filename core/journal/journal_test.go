@@ -0,0 +1,208 @@
+package journal_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/journal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------------------------------------
+//  Helper Functions
+// ----------------------------------------------------------------------------
+
+// writeAndRecord は pathFile に data を書き込み、変更をジャーナルに記録します。
+func writeAndRecord(t *testing.T, pathFile string, data string) {
+	t.Helper()
+
+	before, err := os.ReadFile(pathFile)
+	if err != nil {
+		before = []byte{}
+	}
+
+	require.NoError(t, os.WriteFile(pathFile, []byte(data), 0o600))
+	require.NoError(t, journal.Record(pathFile, before, []byte(data)))
+}
+
+func readString(t *testing.T, pathFile string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(pathFile)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+// ----------------------------------------------------------------------------
+//  Tests
+// ----------------------------------------------------------------------------
+
+func TestPathJournal(t *testing.T) {
+	for _, test := range []struct {
+		input  string
+		expect string
+	}{
+		{"/foo/todo.txt", "/foo/.qiitask/journal.json"},
+		{"/foo/.qiitask/todo.txt", "/foo/.qiitask/journal.json"},
+		{"/home/.config/qiitask/todo.txt", "/home/.config/qiitask/journal.json"},
+	} {
+		expect := filepath.FromSlash(test.expect)
+		actual := journal.PathJournal(filepath.FromSlash(test.input))
+
+		assert.Equal(t, expect, actual)
+	}
+}
+
+func TestRecord_undo_redo(t *testing.T) {
+	oldOperation := journal.Operation
+	defer func() {
+		journal.Operation = oldOperation
+	}()
+
+	pathFile := filepath.Join(t.TempDir(), "todo.txt")
+
+	journal.Operation = "sort"
+
+	writeAndRecord(t, pathFile, "task 1\ntask 2\n")
+	writeAndRecord(t, pathFile, "task 2\ntask 1\n")
+	writeAndRecord(t, pathFile, "task 2\ntask 1\n") // no change should not be recorded
+
+	history, err := journal.Load(pathFile)
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 2)
+	assert.Equal(t, "sort", history.Entries[1].Operation)
+
+	// Undo
+	entry, err := history.Undo(pathFile, 0)
+	require.NoError(t, err)
+	assert.Equal(t, pathFile, entry.File)
+	assert.Equal(t, "task 1\ntask 2\n", readString(t, pathFile))
+
+	entry, err = history.Undo(pathFile, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "", readString(t, pathFile))
+
+	_, err = history.Undo(pathFile, 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "取り消せる変更はありません")
+
+	// Redo
+	_, err = history.Redo(pathFile, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "task 1\ntask 2\n", readString(t, pathFile))
+
+	// The state should be saved
+	reloaded, err := journal.Load(pathFile)
+	require.NoError(t, err)
+	assert.False(t, reloaded.Entries[0].Undone)
+	assert.True(t, reloaded.Entries[1].Undone)
+
+	// New record should discard the redo history
+	writeAndRecord(t, pathFile, "task 3\n")
+
+	reloaded, err = journal.Load(pathFile)
+	require.NoError(t, err)
+	assert.Len(t, reloaded.Entries, 2)
+	assert.False(t, reloaded.CanRedo(pathFile))
+}
+
+func TestUndo_other_file(t *testing.T) {
+	pathDir := t.TempDir()
+	pathFileTask := filepath.Join(pathDir, "todo.txt")
+	pathFileList := filepath.Join(pathDir, "work.txt")
+
+	writeAndRecord(t, pathFileTask, "task 1\n")
+	writeAndRecord(t, pathFileTask, "task 1\ntask 2\n")
+	writeAndRecord(t, pathFileList, "work 1\n") // newest, but in another file
+
+	history, err := journal.Load(pathFileTask)
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 3, "the journal should be shared in the directory")
+
+	entry, err := history.Undo(pathFileTask, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, pathFileTask, entry.File, "it should undo the change of the given file")
+	assert.Equal(t, "task 1\n", readString(t, pathFileTask))
+	assert.Equal(t, "work 1\n", readString(t, pathFileList), "other files should not be changed")
+	assert.True(t, history.CanUndo(pathFileList))
+
+	// A new change of the other file should not discard the redo history
+	writeAndRecord(t, pathFileList, "work 2\n")
+
+	reloaded, err := journal.Load(pathFileTask)
+	require.NoError(t, err)
+	require.True(t, reloaded.CanRedo(pathFileTask))
+
+	_, err = reloaded.Redo(pathFileTask, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, "task 1\ntask 2\n", readString(t, pathFileTask))
+	assert.False(t, reloaded.CanRedo(pathFileTask))
+}
+
+func TestLoad_legacy_position(t *testing.T) {
+	pathFile := filepath.Join(t.TempDir(), "todo.txt")
+	pathJournal := journal.PathJournal(pathFile)
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(pathJournal), 0o755))
+	require.NoError(t, os.WriteFile(pathJournal, []byte(`{
+  "position": 1,
+  "entries": [
+    {"operation": "add", "file": "/a/todo.txt"},
+    {"operation": "done", "file": "/a/todo.txt"}
+  ]
+}`), 0o600))
+
+	history, err := journal.Load(pathFile)
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 2)
+
+	assert.False(t, history.Entries[0].Undone)
+	assert.True(t, history.Entries[1].Undone, "entries after the old position should be undone")
+}
+
+func TestUndo_file_modified(t *testing.T) {
+	pathFile := filepath.Join(t.TempDir(), "todo.txt")
+
+	writeAndRecord(t, pathFile, "task 1\n")
+
+	// Modify without recording
+	require.NoError(t, os.WriteFile(pathFile, []byte("task 9\n"), 0o600))
+
+	history, err := journal.Load(pathFile)
+	require.NoError(t, err)
+
+	_, err = history.Undo(pathFile, 0)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ファイルが変更されているため適用できません")
+	assert.Equal(t, "task 9\n", readString(t, pathFile), "the file should not be changed on error")
+}
+
+func TestLoad_malformed(t *testing.T) {
+	pathFile := filepath.Join(t.TempDir(), "todo.txt")
+	pathJournal := journal.PathJournal(pathFile)
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(pathJournal), 0o755))
+	require.NoError(t, os.WriteFile(pathJournal, []byte("{malformed"), 0o600))
+
+	_, err := journal.Load(pathFile)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse journal file")
+}
+
+func TestCountLines(t *testing.T) {
+	entry := journal.Entry{
+		Changes: journal.NewChanges([]byte("a\nb\nc\n"), []byte("a\nB\nc\nd\n")),
+	}
+
+	added, deleted := entry.CountLines()
+
+	assert.Equal(t, 2, added)
+	assert.Equal(t, 1, deleted)
+}
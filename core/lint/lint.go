@@ -0,0 +1,416 @@
+/*
+Package lint は todo.txt 形式のタスク・ファイルの書式を検査するパッケージです。
+
+検査はファイルの行ごとに行われ、問題のある箇所は "ファイル名:行番号" 付きの診断
+（Diagnostic）として返されます。一部の問題は Fix で自動修正できます。
+*/
+package lint
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1set/todotxt"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// 診断の重要度。
+const (
+	// SeverityError はタスクとして読み込めない、もしくは明らかな誤りです。
+	SeverityError Severity = iota
+	// SeverityWarning は読み込めるものの、意図しない可能性のある書式です。
+	SeverityWarning
+)
+
+// 診断の規則名。
+const (
+	RuleScanner   = "scanner"    // 行が長すぎて読み込めない
+	RuleDate      = "date"       // 日付の書式が不正
+	RulePriority  = "priority"   // 優先度の位置や書式が不正
+	RuleDateOrder = "date-order" // 完了日が作成日より前
+	RuleDuplicate = "duplicate"  // 同じ内容のタスクが存在する
+	RuleTagFormat = "tag-format" // key:value の書式や値が不正
+	RuleTagName   = "tag-name"   // QiiTask で使われていないタグ
+)
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// KnownTags は QiiTask および todo.txt で一般的に使われるタグ名と、その値の検査
+// 関数です。検査関数が nil の場合は値を検査しません。
+var KnownTags = map[string]func(value string) error{
+	"id":      nil,
+	"parent":  nil,
+	"dep":     nil,
+	"due":     checkDate,
+	"t":       checkDate,
+	"rec":     nil,
+	"pri":     nil,
+	"spent":   checkDuration,
+	"est":     checkNumber,
+	"src":     nil,
+	"touched": checkDate,
+}
+
+var (
+	rxDateLike = regexp.MustCompile(`^\d{4}-\d{1,2}-\d{1,2}$`)
+	rxPriority = regexp.MustCompile(`^\(([A-Za-z])\)$`)
+	rxTagKey   = regexp.MustCompile(`^[\w-]*$`)
+)
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Severity は診断の重要度です。
+type Severity int
+
+// Diagnostic は 1 件の診断結果です。
+type Diagnostic struct {
+	File     string   // ファイルのパス
+	Line     int      // 行番号（1 スタート）
+	Severity Severity // 重要度
+	Rule     string   // 規則名
+	Message  string   // 診断の内容
+	Fixable  bool     // Fix で自動修正できる場合 true
+}
+
+// line は検査中の 1 行です。
+type line struct {
+	number    int      // 行番号（1 スタート）
+	tokens    []string // 空白で区切った行の要素
+	fixed     []string // 修正後の要素。nil の場合は修正なし
+	isRemoved bool     // 修正で削除する場合 true
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// Check は pathFile のファイルの内容 data を検査し、診断結果を行順に返します。
+func Check(pathFile string, data []byte) []Diagnostic {
+	diagnostics, _ := check(pathFile, data)
+
+	return diagnostics
+}
+
+// CountErrors は diagnostics のうち、重要度が SeverityError の診断の数を返しま
+// す。
+func CountErrors(diagnostics []Diagnostic) int {
+	count := 0
+
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == SeverityError {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Fix は data を検査し、自動修正できる問題を修正した内容と、修正した問題の診断
+// を返します。修正されない行（コメントや空行を含む）は元のまま残ります。
+func Fix(pathFile string, data []byte) ([]byte, []Diagnostic) {
+	diagnostics, lines := check(pathFile, data)
+	fixed := []Diagnostic{}
+
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Fixable {
+			fixed = append(fixed, diagnostic)
+		}
+	}
+
+	if len(fixed) == 0 {
+		return data, fixed
+	}
+
+	rawLines, newline := splitLines(data)
+	result := []string{}
+
+	for i, raw := range rawLines {
+		item, ok := lines[i+1]
+
+		switch {
+		case !ok:
+			result = append(result, raw)
+		case item.isRemoved:
+			continue
+		case item.fixed != nil:
+			result = append(result, strings.Join(item.fixed, " "))
+		default:
+			result = append(result, raw)
+		}
+	}
+
+	return []byte(strings.Join(result, newline)), fixed
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// String は診断を "ファイル:行: 重要度: メッセージ (規則名)" の書式で返します。
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%v:%d: %v: %v (%v)", d.File, d.Line, d.Severity, d.Message, d.Rule)
+}
+
+// String は重要度の名前を返します。
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+
+	return "warning"
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// check は data を検査し、診断結果と、修正内容を含む行番号ごとの行を返します。
+func check(pathFile string, data []byte) ([]Diagnostic, map[int]*line) {
+	diagnostics := []Diagnostic{}
+	lines := map[int]*line{}
+	seen := map[string]int{} // 正規化したタスクと、最初に出現した行番号
+
+	report := func(number int, severity Severity, rule string, fixable bool, format string, args ...interface{}) {
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     pathFile,
+			Line:     number,
+			Severity: severity,
+			Rule:     rule,
+			Message:  fmt.Sprintf(format, args...),
+			Fixable:  fixable,
+		})
+	}
+
+	rawLines, _ := splitLines(data)
+
+	for i, raw := range rawLines {
+		number := i + 1
+		text := strings.TrimSpace(raw)
+
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if len(raw) >= bufio.MaxScanTokenSize {
+			report(number, SeverityError, RuleScanner, false,
+				"行が長すぎるため読み込めません（%d バイト、上限 %d バイト）。改行が抜けていないか確認してください",
+				len(raw), bufio.MaxScanTokenSize)
+
+			continue
+		}
+
+		item := &line{number: number, tokens: strings.Fields(text)}
+		lines[number] = item
+
+		isValidDate := checkHead(item, report)
+		checkTags(item, report)
+
+		if !isValidDate {
+			continue
+		}
+
+		// 重複の検査（修正後の内容で比較する）
+		task, err := todotxt.ParseTask(strings.Join(item.current(), " "))
+		if err != nil {
+			continue
+		}
+
+		key := task.String()
+
+		if first, ok := seen[key]; ok {
+			report(number, SeverityWarning, RuleDuplicate, true, "%d 行目と同じタスクです", first)
+
+			item.isRemoved = true
+
+			continue
+		}
+
+		seen[key] = number
+	}
+
+	return diagnostics, lines
+}
+
+// checkHead は行頭の完了マーク・日付・優先度を検査します。日付の書式が正しい場
+// 合は true を返します。
+func checkHead(
+	item *line,
+	report func(number int, severity Severity, rule string, fixable bool, format string, args ...interface{}),
+) bool {
+	tokens := item.tokens
+	isValid := true
+	isCompleted := tokens[0] == "x"
+	dates := []int{} // 行頭の日付の要素のインデックス
+	index := 0
+
+	if isCompleted {
+		index++
+	}
+
+	if !isCompleted && index < len(tokens) && rxPriority.MatchString(tokens[index]) {
+		index++
+	}
+
+	for ; index < len(tokens) && len(dates) < 2 && rxDateLike.MatchString(tokens[index]); index++ {
+		if err := checkDate(tokens[index]); err != nil {
+			report(item.number, SeverityError, RuleDate, false,
+				"日付の書式が不正です: %q（YYYY-MM-DD で指定してください）", tokens[index])
+
+			isValid = false
+		}
+
+		dates = append(dates, index)
+	}
+
+	// 完了日と作成日の順序
+	if isValid && isCompleted && len(dates) == 2 && tokens[dates[0]] < tokens[dates[1]] {
+		report(item.number, SeverityError, RuleDateOrder, true,
+			"完了日（%v）が作成日（%v）より前です", tokens[dates[0]], tokens[dates[1]])
+
+		fixed := item.current()
+		fixed[dates[0]], fixed[dates[1]] = fixed[dates[1]], fixed[dates[0]]
+		item.fixed = fixed
+	}
+
+	if isCompleted {
+		return isValid
+	}
+
+	// 優先度の書式
+	if match := rxPriority.FindStringSubmatch(tokens[0]); match != nil {
+		if priority := "(" + strings.ToUpper(match[1]) + ")"; tokens[0] != priority {
+			report(item.number, SeverityError, RulePriority, true,
+				"優先度は大文字で指定してください: %v", tokens[0])
+
+			fixed := item.current()
+			fixed[0] = priority
+			item.fixed = fixed
+		}
+
+		return isValid
+	}
+
+	// 優先度の位置。行頭の日付の直後にある場合のみ対象とし、本文中の "(A)" など
+	// は本文として扱う
+	if len(dates) == 0 || index >= len(tokens) {
+		return isValid
+	}
+
+	if match := rxPriority.FindStringSubmatch(tokens[index]); match != nil {
+		report(item.number, SeverityError, RulePriority, true,
+			"優先度は行頭に指定してください: %v", tokens[index])
+
+		fixed := []string{"(" + strings.ToUpper(match[1]) + ")"}
+
+		for j, other := range item.current() {
+			if j != index {
+				fixed = append(fixed, other)
+			}
+		}
+
+		item.fixed = fixed
+	}
+
+	return isValid
+}
+
+// checkTags は key:value 形式のタグを検査します。
+func checkTags(
+	item *line,
+	report func(number int, severity Severity, rule string, fixable bool, format string, args ...interface{}),
+) {
+	for _, token := range item.tokens {
+		index := strings.Index(token, ":")
+		if index < 0 {
+			continue
+		}
+
+		key, value := token[:index], token[index+1:]
+
+		// URL やタグ名に使えない文字を含む場合は本文として扱う
+		if strings.HasPrefix(value, "//") || !rxTagKey.MatchString(key) {
+			continue
+		}
+
+		if key == "" || value == "" {
+			report(item.number, SeverityError, RuleTagFormat, false,
+				"key:value の書式が不正です: %q", token)
+
+			continue
+		}
+
+		checkValue, ok := KnownTags[key]
+		if !ok {
+			report(item.number, SeverityWarning, RuleTagName, false,
+				"QiiTask で使われていないタグです: %q", key)
+
+			continue
+		}
+
+		if checkValue == nil {
+			continue
+		}
+
+		if err := checkValue(value); err != nil {
+			report(item.number, SeverityError, RuleTagFormat, false,
+				"%v: タグの値が不正です: %v", key, err)
+		}
+	}
+}
+
+// splitLines は data を行に分割し、行と改行コードを返します。改行コードは "\r\n"
+// を含む場合は "\r\n"、それ以外は "\n" です。各行の末尾の "\r" は取り除かれ
+// ます。
+func splitLines(data []byte) ([]string, string) {
+	newline := "\n"
+	if strings.Contains(string(data), "\r\n") {
+		newline = "\r\n"
+	}
+
+	return strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"), newline
+}
+
+// current は修正中の行の要素のコピーを返します。
+func (l *line) current() []string {
+	if l.fixed != nil {
+		return append([]string{}, l.fixed...)
+	}
+
+	return append([]string{}, l.tokens...)
+}
+
+// checkDate は value が YYYY-MM-DD 形式の日付でない場合にエラーを返します。
+func checkDate(value string) error {
+	_, err := time.Parse("2006-01-02", value)
+
+	return err
+}
+
+// checkDuration は value が "1h30m" 形式の時間でない場合にエラーを返します。
+func checkDuration(value string) error {
+	_, err := time.ParseDuration(value)
+
+	return err
+}
+
+// checkNumber は value が 0 以上の整数でない場合にエラーを返します。
+func checkNumber(value string) error {
+	number, err := strconv.Atoi(value)
+	if err == nil && number < 0 {
+		return errors.Errorf("負の数です: %v", value)
+	}
+
+	return err
+}
@@ -0,0 +1,124 @@
+package lint_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/lint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rulesOf は diagnostics の "行番号:規則名" の一覧を返します。
+func rulesOf(diagnostics []lint.Diagnostic) []string {
+	result := []string{}
+
+	for _, diagnostic := range diagnostics {
+		result = append(result, fmt.Sprintf("%d:%v", diagnostic.Line, diagnostic.Rule))
+	}
+
+	return result
+}
+
+func TestCheck(t *testing.T) {
+	for _, test := range []struct {
+		input  string
+		expect string // 規則名。空の場合は問題なし
+	}{
+		{"(A) 2021-01-02 task +proj @ctx due:2021-02-01 id:a dep:b spent:1h30m est:3", ""},
+		{"x 2021-01-03 2021-01-02 done task", ""},
+		{"see https://example.com/a:b for details", ""},
+		{"# comment line key:", ""},
+		{"2021-13-01 task", lint.RuleDate},
+		{"(A) 2021-1-5 task", lint.RuleDate},
+		{"(a) task", lint.RulePriority},
+		{"call (A) team about task (b)", ""},
+		{"2021-01-02 (A) task", lint.RulePriority},
+		{"x 2021-01-02 2021-01-03 done task", lint.RuleDateOrder},
+		{"task due:tomorrow", lint.RuleTagFormat},
+		{"task spent:forever", lint.RuleTagFormat},
+		{"task est:-1", lint.RuleTagFormat},
+		{"task touched:yesterday", lint.RuleTagFormat},
+		{"task key:", lint.RuleTagFormat},
+		{"task :value", lint.RuleTagFormat},
+		{"task foo:bar", lint.RuleTagName},
+	} {
+		diagnostics := lint.Check("todo.txt", []byte(test.input))
+
+		if test.expect == "" {
+			assert.Empty(t, diagnostics, "input: %v", test.input)
+
+			continue
+		}
+
+		require.Len(t, diagnostics, 1, "input: %v", test.input)
+		assert.Equal(t, test.expect, diagnostics[0].Rule, "input: %v", test.input)
+		assert.Equal(t, 1, diagnostics[0].Line)
+	}
+}
+
+func TestCheck_duplicate(t *testing.T) {
+	data := "(A) task +b +a\n\n(A) task +a +b\nother\n"
+
+	diagnostics := lint.Check("todo.txt", []byte(data))
+
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, "todo.txt:3: warning: 1 行目と同じタスクです (duplicate)", diagnostics[0].String())
+	assert.Equal(t, 0, lint.CountErrors(diagnostics))
+}
+
+func TestCheck_scanner_error(t *testing.T) {
+	pathFile := filepath.Join("..", "..", "testdata", "error", "scanner_error", "todo.txt")
+
+	data, err := os.ReadFile(pathFile)
+	require.NoError(t, err)
+
+	diagnostics := lint.Check(pathFile, data)
+
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, lint.RuleScanner, diagnostics[0].Rule)
+	assert.Equal(t, lint.SeverityError, diagnostics[0].Severity)
+	assert.False(t, diagnostics[0].Fixable)
+	assert.Contains(t, diagnostics[0].String(), pathFile+":1: error: 行が長すぎるため読み込めません")
+}
+
+func TestFix(t *testing.T) {
+	data := "# header\n(b) lower\n2021-01-02 (c) middle\nx 2021-01-02 2021-01-03 done\ntask foo:bar\n(B) lower\ncall (A) team\n"
+
+	after, fixed := lint.Fix("todo.txt", []byte(data))
+
+	assert.Equal(t,
+		"# header\n(B) lower\n(C) 2021-01-02 middle\nx 2021-01-03 2021-01-02 done\ntask foo:bar\ncall (A) team\n",
+		string(after),
+	)
+	assert.Equal(t, []string{"2:priority", "3:priority", "4:date-order", "6:duplicate"}, rulesOf(fixed))
+
+	// 修正後は修正できない問題のみが残る
+	assert.Equal(t, []string{"5:tag-name"}, rulesOf(lint.Check("todo.txt", after)))
+}
+
+func TestFix_crlf(t *testing.T) {
+	data := "(a) task\r\n2021-01-02 (B) other\nlast\r\n"
+
+	diagnostics := lint.Check("todo.txt", []byte(data))
+	after, fixed := lint.Fix("todo.txt", []byte(data))
+
+	assert.Equal(t, rulesOf(diagnostics), rulesOf(fixed), "check and fix should see the same lines")
+	assert.Equal(t, "(A) task\r\n(B) 2021-01-02 other\r\nlast\r\n", string(after))
+}
+
+func TestFix_nothing_to_fix(t *testing.T) {
+	data := []byte("task\r\nbad date 2021-02-30 due:2021-02-30\r\n")
+
+	after, fixed := lint.Fix("todo.txt", data)
+
+	assert.Empty(t, fixed)
+	assert.Equal(t, data, after)
+}
+
+func TestSeverity_String(t *testing.T) {
+	assert.Equal(t, "error", lint.SeverityError.String())
+	assert.Equal(t, "warning", lint.SeverityWarning.String())
+}
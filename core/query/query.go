@@ -19,6 +19,7 @@ import (
 	_ "embed" // 下記 //go:embed を機能させるために _ で読み込みだけさせておく。
 	"encoding/json"
 	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -33,6 +34,12 @@ type Query struct {
 // Samples は同梱されている全ての質問一覧を保持する構造体です。
 type Samples map[string]Query
 
+// KeyEffort は作業量を比較する質問を含む質問集のキー名です。
+const KeyEffort = "task"
+
+// wordsEffort は作業量を比較する質問を見分けるための語句です。
+var wordsEffort = []string{"作業量", "で終わる"}
+
 // EmbeddedQuery はバイナリに埋め込まれたデフォルトの質問データです。
 //go:embed query.json
 var EmbeddedQuery []byte
@@ -94,3 +101,34 @@ func New(key string) (Query, error) {
 
 	return samples[key], nil
 }
+
+// Effort は KeyEffort の質問集のうち、作業量を比較する質問（"作業量" や "で終わ
+// る" を含む質問）を重複なしで返します。見積り（"estimate" コマンド）のデフォル
+// トの質問に使われます。
+func Effort() []string {
+	result := []string{}
+
+	sample, err := New(KeyEffort)
+	if err != nil {
+		return result
+	}
+
+	found := map[string]bool{}
+
+	for _, question := range append(sample.Objective, sample.Subjective...) {
+		if found[question] {
+			continue
+		}
+
+		for _, word := range wordsEffort {
+			if strings.Contains(question, word) {
+				result = append(result, question)
+				found[question] = true
+
+				break
+			}
+		}
+	}
+
+	return result
+}
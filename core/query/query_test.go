@@ -113,3 +113,12 @@ func Test_unknown_key(t *testing.T) {
 		require.Empty(t, result, "unknown key should return an empty string")
 	}
 }
+
+func TestEffort(t *testing.T) {
+	expect := []string{
+		"作業量が少ないのはどちらですか",
+		"5 分で終わるのはどちらですか",
+	}
+
+	assert.Equal(t, expect, query.Effort(), "effort questions should be taken from the embedded query set without duplicates")
+}
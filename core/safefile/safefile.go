@@ -0,0 +1,241 @@
+/*
+Package safefile はファイルを安全に保存するための関数をまとめたパッケージです。
+
+ファイルは一時ファイルに書き込み・同期（fsync）した後にリネームで置き換えるため、
+書き込み中にアプリが異常終了しても元のファイルが壊れることはありません。また、
+上書き前のファイルは "todo.txt.1", "todo.txt.2" ... のようにローテーションしな
+がらバックアップされます。（数字が小さいほど新しいバックアップです）
+*/
+package safefile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// NumBackupDefault は保存時に残すバックアップ数のデフォルト値です。
+const NumBackupDefault = 3
+
+// PermDefault は新規にファイルを作成する場合のパーミッションです。
+const PermDefault = 0o640
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// OsRename は os.Rename のコピーです。テスト時に os.Rename の動作をモックする為
+// に変数に代入しています。
+var OsRename = os.Rename
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// ListBackup は pathFile のバックアップ・ファイルのパスを新しい順に返します。
+// バックアップが存在しない場合は空のスライスを返します。
+func ListBackup(pathFile string) []string {
+	pathFile = resolveLink(pathFile)
+	list := []string{}
+
+	for index := 1; util.IsFile(PathBackup(pathFile, index)); index++ {
+		list = append(list, PathBackup(pathFile, index))
+	}
+
+	return list
+}
+
+// PathBackup は pathFile の index 番目のバックアップ・ファイルのパスを返します。
+func PathBackup(pathFile string, index int) string {
+	return fmt.Sprintf("%v.%d", pathFile, index)
+}
+
+// Restore は pathFile を index 番目のバックアップの内容に戻します。
+//
+// 復元前の pathFile の内容も WriteFile と同様にバックアップされるため、復元を
+// 取り消すことができます。そのため、復元後はバックアップの番号がずれます。
+func Restore(pathFile string, index int, numBackup int) error {
+	pathFile = resolveLink(pathFile)
+	pathBackup := PathBackup(pathFile, index)
+
+	if !util.IsFile(pathBackup) {
+		return errors.Errorf("backup file not found: %v", pathBackup)
+	}
+
+	data, err := os.ReadFile(pathBackup)
+	if err != nil {
+		return errors.Wrap(err, "failed to read backup file")
+	}
+
+	// 復元前のファイルを残すため、最低 1 つはバックアップする
+	if numBackup < 1 {
+		numBackup = 1
+	}
+
+	return WriteFile(pathFile, data, numBackup)
+}
+
+// WriteFile は data を pathFile にアトミックに書き込みます。
+//
+// 既存のファイルは numBackup 個までバックアップされます。numBackup が 0 以下の
+// 場合はバックアップを作成しません。既存のファイルのパーミッションは維持されま
+// す。
+//
+// pathFile がシンボリック・リンクの場合は、リンクを残したままリンク先のファイル
+// を置き換えます。（一時ファイルとバックアップはリンク先のディレクトリに作成され
+// ます）
+func WriteFile(pathFile string, data []byte, numBackup int) error {
+	pathFile = resolveLink(pathFile)
+	perm := os.FileMode(PermDefault)
+
+	if info, err := os.Stat(pathFile); err == nil {
+		perm = info.Mode().Perm()
+
+		if err := rotate(pathFile, numBackup); err != nil {
+			return errors.Wrap(err, "failed to rotate backup files")
+		}
+	}
+
+	pathDir := filepath.Dir(pathFile)
+
+	fileTmp, err := os.CreateTemp(pathDir, "."+filepath.Base(pathFile)+".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+
+	pathFileTmp := fileTmp.Name()
+
+	if err := writeAndSync(fileTmp, data, perm); err != nil {
+		_ = os.Remove(pathFileTmp)
+
+		return err
+	}
+
+	if err := OsRename(pathFileTmp, pathFile); err != nil {
+		_ = os.Remove(pathFileTmp)
+
+		return errors.Wrap(err, "failed to replace file")
+	}
+
+	syncDir(pathDir)
+
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// resolveLink は pathFile がシンボリック・リンクの場合にリンク先のパスを返しま
+// す。リンクでない場合やリンク先が解決できない場合は pathFile をそのまま返しま
+// す。
+func resolveLink(pathFile string) string {
+	info, err := os.Lstat(pathFile)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return pathFile
+	}
+
+	if pathReal, err := filepath.EvalSymlinks(pathFile); err == nil {
+		return pathReal
+	}
+
+	// リンク先が存在しない場合は、リンク先に新規作成する
+	pathLink, err := os.Readlink(pathFile)
+	if err != nil {
+		return pathFile
+	}
+
+	if !filepath.IsAbs(pathLink) {
+		pathLink = filepath.Join(filepath.Dir(pathFile), pathLink)
+	}
+
+	return pathLink
+}
+
+// rotate は pathFile のバックアップを 1 つずつずらし、pathFile の内容を 1 番目の
+// バックアップとしてコピーします。numBackup を超えるバックアップは削除されます。
+func rotate(pathFile string, numBackup int) error {
+	// 保存数を超えた古いバックアップの削除
+	for index := numBackup + 1; util.IsFile(PathBackup(pathFile, index)); index++ {
+		if err := os.Remove(PathBackup(pathFile, index)); err != nil {
+			return errors.Wrap(err, "failed to remove old backup")
+		}
+	}
+
+	if numBackup < 1 {
+		return nil
+	}
+
+	for index := numBackup - 1; index > 0; index-- {
+		pathFrom := PathBackup(pathFile, index)
+
+		if !util.IsFile(pathFrom) {
+			continue
+		}
+
+		if err := OsRename(pathFrom, PathBackup(pathFile, index+1)); err != nil {
+			return errors.Wrap(err, "failed to rotate backup")
+		}
+	}
+
+	data, err := os.ReadFile(pathFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to read file to backup")
+	}
+
+	info, err := os.Stat(pathFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to get file info to backup")
+	}
+
+	pathBackup := PathBackup(pathFile, 1)
+
+	if err := os.WriteFile(pathBackup, data, info.Mode().Perm()); err != nil {
+		return errors.Wrap(err, "failed to write backup")
+	}
+
+	// バックアップの日時を元のファイルの更新日時にあわせる
+	return os.Chtimes(pathBackup, info.ModTime(), info.ModTime())
+}
+
+// syncDir はディレクトリのエントリ（リネーム結果）をディスクに同期します。
+// 同期に対応していないプラットフォームもあるため、エラーは無視されます。
+func syncDir(pathDir string) {
+	dir, err := os.Open(pathDir)
+	if err != nil {
+		return
+	}
+
+	_ = dir.Sync()
+	_ = dir.Close()
+}
+
+// writeAndSync は data を file に書き込み、ディスクに同期した後に閉じます。
+func writeAndSync(file *os.File, data []byte, perm os.FileMode) error {
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+
+		return errors.Wrap(err, "failed to write temporary file")
+	}
+
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+
+		return errors.Wrap(err, "failed to sync temporary file")
+	}
+
+	if err := file.Chmod(perm); err != nil {
+		_ = file.Close()
+
+		return errors.Wrap(err, "failed to change permission of temporary file")
+	}
+
+	return errors.Wrap(file.Close(), "failed to close temporary file")
+}
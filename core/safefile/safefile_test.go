@@ -0,0 +1,144 @@
+package safefile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/safefile"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------------------------------------
+//  Helper Functions
+// ----------------------------------------------------------------------------
+
+func readString(t *testing.T, pathFile string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(pathFile)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+// ----------------------------------------------------------------------------
+//  Tests
+// ----------------------------------------------------------------------------
+
+func TestWriteFile(t *testing.T) {
+	pathFile := filepath.Join(t.TempDir(), "todo.txt")
+
+	for _, data := range []string{"v1", "v2", "v3", "v4", "v5"} {
+		require.NoError(t, safefile.WriteFile(pathFile, []byte(data), 3))
+	}
+
+	assert.Equal(t, "v5", readString(t, pathFile))
+
+	backups := safefile.ListBackup(pathFile)
+	require.Len(t, backups, 3, "it should keep the given number of backups")
+
+	assert.Equal(t, "v4", readString(t, backups[0]), "the 1st backup should be the newest")
+	assert.Equal(t, "v3", readString(t, backups[1]))
+	assert.Equal(t, "v2", readString(t, backups[2]))
+
+	// Reduce the number of backups
+	require.NoError(t, safefile.WriteFile(pathFile, []byte("v6"), 1))
+
+	backups = safefile.ListBackup(pathFile)
+	require.Len(t, backups, 1, "exceeding backups should be removed")
+	assert.Equal(t, "v5", readString(t, backups[0]))
+}
+
+func TestWriteFile_keep_permission(t *testing.T) {
+	pathFile := filepath.Join(t.TempDir(), "todo.txt")
+
+	require.NoError(t, os.WriteFile(pathFile, []byte("v1"), 0o600))
+	require.NoError(t, safefile.WriteFile(pathFile, []byte("v2"), 0))
+
+	info, err := os.Stat(pathFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	assert.Empty(t, safefile.ListBackup(pathFile), "zero backup should not create backups")
+}
+
+func TestWriteFile_symlink(t *testing.T) {
+	pathDirReal := t.TempDir()
+	pathFileReal := filepath.Join(pathDirReal, "todo.txt")
+	pathFileLink := filepath.Join(t.TempDir(), "todo.txt")
+
+	require.NoError(t, os.WriteFile(pathFileReal, []byte("v1"), 0o600))
+	require.NoError(t, os.Symlink(pathFileReal, pathFileLink))
+
+	require.NoError(t, safefile.WriteFile(pathFileLink, []byte("v2"), 1))
+
+	info, err := os.Lstat(pathFileLink)
+	require.NoError(t, err)
+
+	assert.NotZero(t, info.Mode()&os.ModeSymlink, "the symbolic link should be kept")
+	assert.Equal(t, "v2", readString(t, pathFileReal), "it should write to the target of the link")
+	pathFileResolved, err := filepath.EvalSymlinks(pathFileReal)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{pathFileResolved + ".1"}, safefile.ListBackup(pathFileLink),
+		"the backup should be next to the target")
+
+	// Dangling link
+	pathFileNew := filepath.Join(pathDirReal, "new.txt")
+	pathFileDangling := filepath.Join(t.TempDir(), "new.txt")
+
+	require.NoError(t, os.Symlink(pathFileNew, pathFileDangling))
+	require.NoError(t, safefile.WriteFile(pathFileDangling, []byte("new"), 1))
+
+	assert.Equal(t, "new", readString(t, pathFileNew))
+}
+
+func TestWriteFile_fail_rename(t *testing.T) {
+	oldOsRename := safefile.OsRename
+	defer func() {
+		safefile.OsRename = oldOsRename
+	}()
+
+	safefile.OsRename = func(oldpath, newpath string) error {
+		return errors.New("forced error")
+	}
+
+	pathDir := t.TempDir()
+	pathFile := filepath.Join(pathDir, "todo.txt")
+
+	err := safefile.WriteFile(pathFile, []byte("v1"), 3)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to replace file: forced error")
+	assert.NoFileExists(t, pathFile)
+
+	entries, err := os.ReadDir(pathDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "temporary file should be removed on error")
+}
+
+func TestRestore(t *testing.T) {
+	pathFile := filepath.Join(t.TempDir(), "todo.txt")
+
+	for _, data := range []string{"v1", "v2", "v3"} {
+		require.NoError(t, safefile.WriteFile(pathFile, []byte(data), 3))
+	}
+
+	require.NoError(t, safefile.Restore(pathFile, 2, 3))
+
+	assert.Equal(t, "v1", readString(t, pathFile))
+	assert.Equal(t, "v3", readString(t, safefile.PathBackup(pathFile, 1)),
+		"the content before restoring should be backed up")
+}
+
+func TestRestore_backup_not_found(t *testing.T) {
+	pathFile := filepath.Join(t.TempDir(), "todo.txt")
+
+	err := safefile.Restore(pathFile, 1, 3)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "backup file not found")
+}
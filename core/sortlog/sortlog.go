@@ -0,0 +1,150 @@
+/*
+Package sortlog はタスクの並べ替え（'sort'）の実行の記録を管理するパッケージです。
+
+並べ替えの記録はタスク・ファイルと同じワークスペースの ".qiitask" ディレクトリに
+ある "sortlog.json" に記録されます。記録には実行日時と、比較の回答に使われた質問
+ごとの回数が含まれます。
+*/
+package sortlog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/clock"
+	"github.com/Qithub-BOT/QiiTask/core/journal"
+	"github.com/Qithub-BOT/QiiTask/core/safefile"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// NameFile は並べ替えの記録ファイルのファイル名です。
+const NameFile = "sortlog.json"
+
+// MaxSessions は記録に残す並べ替えの最大数です。これを超えると古い記録から削除
+// されます。
+const MaxSessions = 1000
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Session は 1 回分の並べ替えの記録です。
+type Session struct {
+	Time        time.Time      `json:"time"`
+	File        string         `json:"file"`                // タスク・ファイルの絶対パス
+	Comparisons int            `json:"comparisons"`         // 回答した比較の回数
+	Questions   map[string]int `json:"questions,omitempty"` // 回答に使われた質問ごとの回数
+}
+
+// Log は並べ替えの記録の一覧を保持する型です。
+type Log struct {
+	Sessions []Session `json:"sessions"`
+	pathFile string    // 記録ファイルのパス
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// Load は pathFileTarget（タスク・ファイル）の並べ替えの記録を読み込みます。記
+// 録ファイルが存在しない場合は空の記録を返します。
+func Load(pathFileTarget string) (*Log, error) {
+	log := &Log{
+		Sessions: []Session{},
+		pathFile: PathLog(pathFileTarget),
+	}
+
+	if !util.IsFile(log.pathFile) {
+		return log, nil
+	}
+
+	data, err := os.ReadFile(log.pathFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read sort log file")
+	}
+
+	if err := json.Unmarshal(data, log); err != nil {
+		return nil, errors.Wrap(err, "failed to parse sort log file")
+	}
+
+	return log, nil
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// PathLog は pathFileTarget の並べ替えを記録するファイルのパスを返します。記録
+// ファイルはジャーナルと同じディレクトリに置かれます。
+func PathLog(pathFileTarget string) string {
+	return filepath.Join(filepath.Dir(journal.PathJournal(pathFileTarget)), NameFile)
+}
+
+// Record は pathFileTask のタスクを並べ替えたことを記録ファイルに追記します。
+// questions は比較の回答に使われた質問ごとの回数です。
+func Record(pathFileTask string, questions map[string]int) error {
+	log, err := Load(pathFileTask)
+	if err != nil {
+		return err
+	}
+
+	pathFileAbs, err := filepath.Abs(pathFileTask)
+	if err != nil {
+		return errors.Wrap(err, "failed to get absolute path")
+	}
+
+	session := Session{
+		Time:      clock.TimeNow(),
+		File:      pathFileAbs,
+		Questions: map[string]int{},
+	}
+
+	for question, count := range questions {
+		session.Questions[question] += count
+		session.Comparisons += count
+	}
+
+	log.Add(session)
+
+	return log.Save()
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Add は session を記録に追加します。記録が MaxSessions を超えた場合は古い記録
+// から削除されます。
+func (l *Log) Add(session Session) {
+	l.Sessions = append(l.Sessions, session)
+
+	if len(l.Sessions) > MaxSessions {
+		l.Sessions = l.Sessions[len(l.Sessions)-MaxSessions:]
+	}
+}
+
+// PathFile は記録ファイルのパスを返します。
+func (l *Log) PathFile() string {
+	return l.pathFile
+}
+
+// Save は記録をファイルに保存します。
+func (l *Log) Save() error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal sort log")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.pathFile), 0o755); err != nil {
+		return errors.Wrap(err, "failed to create sort log directory")
+	}
+
+	return errors.Wrap(safefile.WriteFile(l.pathFile, data, 0), "failed to write sort log file")
+}
@@ -0,0 +1,75 @@
+package sortlog_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Qithub-BOT/QiiTask/core/sortlog"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathLog(t *testing.T) {
+	for _, test := range []struct {
+		input  string
+		expect string
+	}{
+		{"/foo/todo.txt", "/foo/.qiitask/sortlog.json"},
+		{"/foo/.qiitask/todo.txt", "/foo/.qiitask/sortlog.json"},
+	} {
+		assert.Equal(t, filepath.FromSlash(test.expect), sortlog.PathLog(filepath.FromSlash(test.input)))
+	}
+}
+
+func TestRecord(t *testing.T) {
+	timeSort := time.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	testutil.MockTimeNow(t, timeSort)
+
+	pathFileTask := filepath.Join(t.TempDir(), "todo.txt")
+
+	log, err := sortlog.Load(pathFileTask)
+	require.NoError(t, err)
+	assert.Empty(t, log.Sessions, "it should be empty if the log file does not exist")
+
+	require.NoError(t, sortlog.Record(pathFileTask, map[string]int{"Q1": 2, "Q2": 1}))
+	require.NoError(t, sortlog.Record(pathFileTask, nil))
+
+	log, err = sortlog.Load(pathFileTask)
+	require.NoError(t, err)
+	require.Len(t, log.Sessions, 2)
+
+	assert.True(t, timeSort.Equal(log.Sessions[0].Time))
+	assert.Equal(t, pathFileTask, log.Sessions[0].File)
+	assert.Equal(t, 3, log.Sessions[0].Comparisons)
+	assert.Equal(t, map[string]int{"Q1": 2, "Q2": 1}, log.Sessions[0].Questions)
+	assert.Equal(t, 0, log.Sessions[1].Comparisons)
+	assert.FileExists(t, log.PathFile())
+}
+
+func TestAdd_max_sessions(t *testing.T) {
+	log, err := sortlog.Load(filepath.Join(t.TempDir(), "todo.txt"))
+	require.NoError(t, err)
+
+	for i := 0; i < sortlog.MaxSessions+5; i++ {
+		log.Add(sortlog.Session{Comparisons: i})
+	}
+
+	require.Len(t, log.Sessions, sortlog.MaxSessions)
+	assert.Equal(t, 5, log.Sessions[0].Comparisons, "the oldest sessions should be removed")
+}
+
+func TestLoad_malformed(t *testing.T) {
+	pathFileTask := filepath.Join(t.TempDir(), "todo.txt")
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(sortlog.PathLog(pathFileTask)), 0o755))
+	require.NoError(t, os.WriteFile(sortlog.PathLog(pathFileTask), []byte("{"), 0o600))
+
+	_, err := sortlog.Load(pathFileTask)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse sort log file")
+}
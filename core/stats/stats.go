@@ -0,0 +1,394 @@
+/*
+Package stats はタスクと並べ替えの記録を集計するパッケージです。
+
+完了数の推移、タスクの経過日数、プロジェクトやコンテキストごとのタスク数、並べ
+替えの回数と質問ごとの使用回数を集計します。
+*/
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/agenda"
+	"github.com/Qithub-BOT/QiiTask/core/clock"
+	"github.com/Qithub-BOT/QiiTask/core/estimate"
+	"github.com/Qithub-BOT/QiiTask/core/sortlog"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+const (
+	// DaysDefault は日ごとに集計する日数のデフォルトです。
+	DaysDefault = 14
+	// WeeksDefault は週ごとに集計する週数のデフォルトです。
+	WeeksDefault = 8
+)
+
+// NameNoDate は作成日のないタスクを集計する際の経過日数の区分名です。
+const NameNoDate = "(作成日なし)"
+
+// levelsSparkline はスパークラインに使う文字です。値の小さい順に並んでいます。
+const levelsSparkline = "_.:-=+*#"
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// ageRanges は未完了タスクの経過日数の区分です。Min 日以上のタスクが、該当する最
+// 後の区分で数えられます。
+var ageRanges = []struct {
+	Min  int
+	Name string
+}{
+	{0, "0〜6 日"},
+	{7, "7〜29 日"},
+	{30, "30〜89 日"},
+	{90, "90〜364 日"},
+	{365, "365 日以上"},
+}
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Options は集計の設定です。0 以下の値はデフォルト値になります。
+type Options struct {
+	Days  int // 日ごとに集計する日数
+	Weeks int // 週ごとに集計する週数
+}
+
+// Count は名前（日付や区分）ごとの件数です。
+type Count struct {
+	Name  string
+	Count int
+}
+
+// Tally はプロジェクトもしくはコンテキストごとのタスク数です。
+type Tally struct {
+	Name string // "+" や "@" を含まない名前
+	Open int    // 未完了のタスク数
+	Done int    // 完了済みのタスク数
+}
+
+// Report は集計の結果です。
+type Report struct {
+	Open        int       // 未完了のタスク数
+	Done        int       // 完了済みのタスク数
+	DoneByDay   []Count   // 日ごとの完了数（古い順、今日まで）
+	DoneByWeek  []Count   // 週（月曜はじまり）ごとの完了数（古い順、今週まで）
+	AgeOpen     float64   // 未完了のタスクの、作成日からの平均日数
+	LeadTime    float64   // 完了済みのタスクの、作成日から完了日までの平均日数
+	AgeRanges   []Count   // 未完了のタスクの、経過日数の区分ごとのタスク数
+	Projects    []Tally   // プロジェクトごとのタスク数（多い順）
+	Contexts    []Tally   // コンテキストごとのタスク数（多い順）
+	Sorts       int       // 並べ替えの回数
+	SortsByDay  []Count   // 日ごとの並べ替えの回数（古い順、今日まで）
+	SortsRecent [2]int    // 過去 7 日と過去 30 日の並べ替えの回数
+	Comparisons int       // 並べ替えで回答した比較の回数
+	Questions   []Count   // 質問ごとの使用回数（多い順）
+	Today       time.Time // 集計した日
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// New は tasks（todo.txt と done.txt のタスク）と sessions（並べ替えの記録）を
+// 集計した結果を返します。
+func New(tasks []todotxt.Task, sessions []sortlog.Session, opts Options) *Report {
+	if opts.Days <= 0 {
+		opts.Days = DaysDefault
+	}
+
+	if opts.Weeks <= 0 {
+		opts.Weeks = WeeksDefault
+	}
+
+	today := agenda.Date(clock.TimeNow())
+	report := &Report{
+		DoneByDay:  countsByDay(today, opts.Days),
+		DoneByWeek: countsByWeek(today, opts.Weeks),
+		AgeRanges:  make([]Count, len(ageRanges)),
+		SortsByDay: countsByDay(today, opts.Days),
+		Today:      today,
+	}
+
+	for i, ageRange := range ageRanges {
+		report.AgeRanges[i].Name = ageRange.Name
+	}
+
+	report.addTasks(tasks)
+	report.addSessions(sessions)
+
+	return report
+}
+
+// Bar は maxValue に対する value の割合を、width 文字を最大とする "#" の棒で返します。
+// value が 0 より大きい場合は 1 文字以上になります。
+func Bar(value, maxValue, width int) string {
+	if value <= 0 || maxValue <= 0 {
+		return ""
+	}
+
+	length := value * width / maxValue
+	if length < 1 {
+		length = 1
+	}
+
+	return strings.Repeat("#", length)
+}
+
+// Sparkline は values の推移を、1 つの値を 1 文字で表す ASCII 文字のグラフで返し
+// ます。0 は "_"、最大値は "#" になります。
+func Sparkline(values []int) string {
+	maxValue := 0
+
+	for _, value := range values {
+		if value > maxValue {
+			maxValue = value
+		}
+	}
+
+	var builder strings.Builder
+
+	numLevels := len(levelsSparkline) - 1 // "_" 以外の段階の数
+
+	for _, value := range values {
+		index := 0
+
+		if value > 0 {
+			index = 1 + (value*numLevels-1)/maxValue
+		}
+
+		builder.WriteByte(levelsSparkline[index])
+	}
+
+	return builder.String()
+}
+
+// Values は counts の件数の一覧を返します。
+func Values(counts []Count) []int {
+	result := make([]int, len(counts))
+
+	for i, count := range counts {
+		result[i] = count.Count
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// addSessions は並べ替えの記録を集計に加えます。
+func (r *Report) addSessions(sessions []sortlog.Session) {
+	questions := map[string]int{}
+
+	for _, session := range sessions {
+		r.Sorts++
+		r.Comparisons += session.Comparisons
+
+		days := todo.DaysBetween(session.Time.Local(), r.Today)
+
+		addToDay(r.SortsByDay, days)
+
+		if days >= 0 && days < 7 {
+			r.SortsRecent[0]++
+		}
+
+		if days >= 0 && days < 30 {
+			r.SortsRecent[1]++
+		}
+
+		for question, count := range session.Questions {
+			questions[question] += count
+		}
+	}
+
+	for question, count := range questions {
+		r.Questions = append(r.Questions, Count{question, count})
+	}
+
+	sort.Slice(r.Questions, func(a, b int) bool {
+		if r.Questions[a].Count != r.Questions[b].Count {
+			return r.Questions[a].Count > r.Questions[b].Count
+		}
+
+		return r.Questions[a].Name < r.Questions[b].Name
+	})
+}
+
+// addTasks はタスクを集計に加えます。
+func (r *Report) addTasks(tasks []todotxt.Task) {
+	var sumAge, numAge, sumLead, numLead int
+
+	projects := map[string]*Tally{}
+	contexts := map[string]*Tally{}
+	countNoDate := 0
+
+	for i := range tasks {
+		task := &tasks[i]
+
+		tally(projects, task.Projects, task.Completed)
+		tally(contexts, task.Contexts, task.Completed)
+
+		if task.Completed {
+			r.Done++
+
+			if !task.HasCompletedDate() {
+				continue
+			}
+
+			days := todo.DaysBetween(task.CompletedDate, r.Today)
+
+			addToDay(r.DoneByDay, days)
+			addToWeek(r.DoneByWeek, r.Today, task.CompletedDate)
+
+			if task.HasCreatedDate() {
+				sumLead += todo.DaysBetween(task.CreatedDate, task.CompletedDate)
+				numLead++
+			}
+
+			continue
+		}
+
+		r.Open++
+
+		if !task.HasCreatedDate() {
+			countNoDate++
+
+			continue
+		}
+
+		age := todo.DaysBetween(task.CreatedDate, r.Today)
+
+		sumAge += age
+		numAge++
+
+		for j := len(ageRanges) - 1; j >= 0; j-- {
+			if age >= ageRanges[j].Min {
+				r.AgeRanges[j].Count++
+
+				break
+			}
+		}
+	}
+
+	if countNoDate > 0 {
+		r.AgeRanges = append(r.AgeRanges, Count{NameNoDate, countNoDate})
+	}
+
+	if numAge > 0 {
+		r.AgeOpen = float64(sumAge) / float64(numAge)
+	}
+
+	if numLead > 0 {
+		r.LeadTime = float64(sumLead) / float64(numLead)
+	}
+
+	r.Projects = sortTallies(projects)
+	r.Contexts = sortTallies(contexts)
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// addToDay は今日から days 日前の件数を 1 つ増やします。集計の範囲外の場合は何
+// もしません。
+func addToDay(counts []Count, days int) {
+	if index := len(counts) - 1 - days; days >= 0 && index >= 0 {
+		counts[index].Count++
+	}
+}
+
+// addToWeek は date を含む週の件数を 1 つ増やします。集計の範囲外の場合は何もし
+// ません。
+func addToWeek(counts []Count, today, date time.Time) {
+	weeks := todo.DaysBetween(weekStart(date), weekStart(today)) / 7
+
+	addToDay(counts, weeks)
+}
+
+// countsByDay は today までの days 日分の、件数が 0 の Count を古い順に返しま
+// す。Name は日付です。
+func countsByDay(today time.Time, days int) []Count {
+	result := make([]Count, days)
+
+	for i := range result {
+		result[i].Name = today.AddDate(0, 0, i-days+1).Format(todotxt.DateLayout)
+	}
+
+	return result
+}
+
+// countsByWeek は today を含む週までの weeks 週分の、件数が 0 の Count を古い順
+// に返します。Name は週のはじめ（月曜）の日付です。
+func countsByWeek(today time.Time, weeks int) []Count {
+	result := make([]Count, weeks)
+	start := weekStart(today)
+
+	for i := range result {
+		result[i].Name = start.AddDate(0, 0, (i-weeks+1)*7).Format(todotxt.DateLayout) + "〜"
+	}
+
+	return result
+}
+
+// sortTallies は tallies をタスク数の多い順（同数の場合は名前順）に並べて返しま
+// す。
+func sortTallies(tallies map[string]*Tally) []Tally {
+	result := []Tally{}
+
+	for _, item := range tallies {
+		result = append(result, *item)
+	}
+
+	sort.Slice(result, func(a, b int) bool {
+		totalA, totalB := result[a].Open+result[a].Done, result[b].Open+result[b].Done
+
+		if totalA != totalB {
+			return totalA > totalB
+		}
+
+		return result[a].Name < result[b].Name
+	})
+
+	return result
+}
+
+// tally は names（プロジェクトもしくはコンテキスト）ごとのタスク数を 1 つ増やし
+// ます。names が空の場合は estimate.NameNoProject で数えます。
+func tally(tallies map[string]*Tally, names []string, isDone bool) {
+	if len(names) == 0 {
+		names = []string{estimate.NameNoProject}
+	}
+
+	for _, name := range names {
+		item, ok := tallies[name]
+		if !ok {
+			item = &Tally{Name: name}
+			tallies[name] = item
+		}
+
+		if isDone {
+			item.Done++
+		} else {
+			item.Open++
+		}
+	}
+}
+
+// weekStart は date を含む週のはじめ（月曜）の日付を返します。
+func weekStart(date time.Time) time.Time {
+	date = agenda.Date(date)
+
+	return date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+}
@@ -0,0 +1,126 @@
+package stats_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/sortlog"
+	"github.com/Qithub-BOT/QiiTask/core/stats"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseTasks は data を todo.txt 形式のタスクの一覧に変換します。
+func parseTasks(t *testing.T, data string) []todotxt.Task {
+	t.Helper()
+
+	tasks := todotxt.NewTaskList()
+
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		task, err := todotxt.ParseTask(line)
+		require.NoError(t, err)
+
+		tasks.AddTask(task)
+	}
+
+	return tasks
+}
+
+func TestNew(t *testing.T) {
+	testutil.MockTimeNow(t, testutil.Now)
+
+	tasks := parseTasks(t, ""+
+		"2021-01-06 today +docs @home\n"+
+		"2020-12-27 ten days +docs\n"+
+		"2019-12-01 old +code\n"+
+		"no date\n"+
+		"x 2021-01-06 2021-01-01 done today +docs\n"+
+		"x 2021-01-04 2021-01-03 done monday @home\n"+
+		"x 2021-01-03 done last sunday\n"+
+		"x 2020-01-01 done last year\n"+
+		"x no completed date\n")
+
+	report := stats.New(tasks, nil, stats.Options{Days: 3, Weeks: 2})
+
+	assert.Equal(t, 4, report.Open)
+	assert.Equal(t, 5, report.Done)
+
+	assert.Equal(t, []stats.Count{
+		{"2021-01-04", 1},
+		{"2021-01-05", 0},
+		{"2021-01-06", 1},
+	}, report.DoneByDay)
+
+	assert.Equal(t, []stats.Count{
+		{"2020-12-28〜", 1},
+		{"2021-01-04〜", 2},
+	}, report.DoneByWeek, "weeks should start on Monday")
+
+	assert.InDelta(t, (0.0+10.0+402.0)/3, report.AgeOpen, 0.001, "tasks without created date should be ignored")
+	assert.InDelta(t, (5.0+1.0)/2, report.LeadTime, 0.001)
+
+	assert.Equal(t, []stats.Count{
+		{"0〜6 日", 1},
+		{"7〜29 日", 1},
+		{"30〜89 日", 0},
+		{"90〜364 日", 0},
+		{"365 日以上", 1},
+		{stats.NameNoDate, 1},
+	}, report.AgeRanges)
+
+	assert.Equal(t, []stats.Tally{
+		{"(なし)", 1, 4},
+		{"docs", 2, 1},
+		{"code", 1, 0},
+	}, report.Projects)
+
+	assert.Equal(t, []stats.Tally{
+		{"(なし)", 3, 4},
+		{"home", 1, 1},
+	}, report.Contexts)
+}
+
+func TestNew_sessions(t *testing.T) {
+	testutil.MockTimeNow(t, testutil.Now)
+
+	at := func(day int) time.Time {
+		return time.Date(2021, 1, day, 20, 0, 0, 0, time.Local)
+	}
+
+	report := stats.New(nil, []sortlog.Session{
+		{Time: at(6), Comparisons: 3, Questions: map[string]int{"Q1": 2, "Q2": 1}},
+		{Time: at(6), Comparisons: 1, Questions: map[string]int{"Q2": 1}},
+		{Time: at(1), Comparisons: 0},
+		{Time: time.Date(2020, 12, 1, 0, 0, 0, 0, time.Local), Comparisons: 2, Questions: map[string]int{"Q3": 2}},
+		{Time: at(7), Comparisons: 0}, // 集計日より後
+	}, stats.Options{Days: 2})
+
+	assert.Equal(t, 5, report.Sorts)
+	assert.Equal(t, 6, report.Comparisons)
+	assert.Equal(t, [2]int{3, 3}, report.SortsRecent)
+	assert.Equal(t, []stats.Count{{"2021-01-05", 0}, {"2021-01-06", 2}}, report.SortsByDay)
+	assert.Equal(t, []stats.Count{{"Q1", 2}, {"Q2", 2}, {"Q3", 2}}, report.Questions,
+		"questions should be sorted by count then by name")
+	assert.Len(t, report.DoneByWeek, stats.WeeksDefault)
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "", stats.Sparkline(nil))
+	assert.Equal(t, "___", stats.Sparkline([]int{0, 0, 0}))
+	assert.Equal(t, "_.-#", stats.Sparkline([]int{0, 1, 3, 7}))
+	assert.Equal(t, "_.#", stats.Sparkline([]int{0, 1, 100}), "small values should be visible")
+}
+
+func TestBar(t *testing.T) {
+	assert.Equal(t, "", stats.Bar(0, 10, 20))
+	assert.Equal(t, "#", stats.Bar(1, 100, 20), "it should be at least one character")
+	assert.Equal(t, "##########", stats.Bar(5, 10, 20))
+	assert.Equal(t, "", stats.Bar(1, 0, 20))
+}
+
+func TestValues(t *testing.T) {
+	assert.Equal(t, []int{1, 0, 3}, stats.Values([]stats.Count{{"a", 1}, {"b", 0}, {"c", 3}}))
+}
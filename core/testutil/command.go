@@ -0,0 +1,63 @@
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/appinfo"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/kami-zh/go-capturer"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// NewAppInfo はローカルのタスク・ファイルに data を書き込んだ一時ディレクトリと、
+// 空の一時ディレクトリ（グローバル）のタスクを読み込んだ AppInfo を返します。
+// 現在時刻は Now に固定されます。
+//
+// ローカルのタスク・ファイルのパスは appInfo.Tasks.Local.FileUsed() で取得でき
+// ます。
+func NewAppInfo(t *testing.T, data string) *appinfo.AppInfo {
+	t.Helper()
+
+	MockTimeNow(t, Now)
+
+	pathDirLocal := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, todo.NameFile), []byte(data), 0o600))
+
+	appInfo, err := appinfo.New(pathDirLocal, t.TempDir(), "")
+	require.NoError(t, err)
+
+	return appInfo
+}
+
+// Execute は cmd を args の引数で実行し、出力（標準出力および標準エラー出力）と
+// エラーを返します。cmd は各コマンドの New もしくは cmdroot.New で作成したもの
+// です。
+func Execute(cmd *cobra.Command, args ...string) (string, error) {
+	var err error
+
+	cmd.SetArgs(args)
+
+	out := capturer.CaptureOutput(func() {
+		err = cmd.Execute()
+	})
+
+	return out, err
+}
+
+// ReadFile は pathFile の内容を返します。（タスク・ファイルの確認用）
+func ReadFile(t *testing.T, pathFile string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(pathFile)
+	require.NoError(t, err)
+
+	return string(data)
+}
@@ -0,0 +1,56 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2"
+	surveyCore "github.com/AlecAivazis/survey/v2/core"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// MockAnswers は cui.SurveyAskOne を、質問に answers の順に回答する関数に置き換
+// えます。回答は質問の種類にあわせて、選択・入力には string を、確認には bool
+// を指定します。answers より多く質問された場合はテストを失敗させます。
+//
+// 元の関数はテストの終了時に t.Cleanup で戻されます。
+func MockAnswers(t *testing.T, answers ...interface{}) {
+	t.Helper()
+
+	MockAskOne(t, func(p survey.Prompt) (interface{}, error) {
+		require.NotEmpty(t, answers, "unexpected question")
+
+		answer := answers[0]
+		answers = answers[1:]
+
+		return answer, nil
+	})
+}
+
+// MockAskOne は cui.SurveyAskOne を、質問 p ごとに answer の戻り値で回答する関
+// 数に置き換えます。answer がエラーを返した場合は、質問が中断されたものとして扱
+// われます。
+//
+// 元の関数はテストの終了時に t.Cleanup で戻されます。
+func MockAskOne(t *testing.T, answer func(p survey.Prompt) (interface{}, error)) {
+	t.Helper()
+
+	oldSurveyAskOne := cui.SurveyAskOne
+
+	t.Cleanup(func() {
+		cui.SurveyAskOne = oldSurveyAskOne
+	})
+
+	cui.SurveyAskOne = func(p survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+		value, err := answer(p)
+		if err != nil {
+			return err
+		}
+
+		return surveyCore.WriteAnswer(response, "", value)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Package testutil は各パッケージのテストで共通して使うヘルパー関数を定義します。
+
+テスト専用のパッケージです。テスト以外のコードからインポートしないでください。
+*/
+package testutil
+
+import (
+	"time"
+
+	"github.com/Qithub-BOT/QiiTask/core/clock"
+)
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// Now は NewAppInfo で固定される現在時刻です。保存したタスクの "touched:" タグは
+// "touched:2021-01-06" になります。
+var Now = time.Date(2021, 1, 6, 12, 0, 0, 0, time.Local)
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// MockTimeNow は clock.TimeNow を常に now を返す関数に置き換えます。元の関数は
+// テストの終了時に t.Cleanup で戻されます。
+func MockTimeNow(t interface{ Cleanup(func()) }, now time.Time) {
+	MockTimeNowFunc(t, func() time.Time {
+		return now
+	})
+}
+
+// MockTimeNowFunc は clock.TimeNow を timeNow に置き換えます。呼び出されるごと
+// に時刻を進める場合などに使います。元の関数はテストの終了時に t.Cleanup で戻さ
+// れます。
+func MockTimeNowFunc(t interface{ Cleanup(func()) }, timeNow func() time.Time) {
+	oldTimeNow := clock.TimeNow
+
+	t.Cleanup(func() {
+		clock.TimeNow = oldTimeNow
+	})
+
+	clock.TimeNow = timeNow
+}
@@ -0,0 +1,46 @@
+package testutil_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Qithub-BOT/QiiTask/core/clock"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockTimeNow(t *testing.T) {
+	now := time.Date(2021, 1, 6, 12, 0, 0, 0, time.UTC)
+
+	t.Run("mocked", func(t *testing.T) {
+		testutil.MockTimeNow(t, now)
+
+		assert.Equal(t, now, clock.TimeNow())
+	})
+
+	assert.NotEqual(t, now, clock.TimeNow(), "it should be restored after the test")
+}
+
+func TestMockAnswers(t *testing.T) {
+	testutil.MockAnswers(t, "b", true)
+
+	ui := cui.New()
+
+	answer, err := ui.Select("select", []string{"a", "b"}, "a", "")
+	require.NoError(t, err)
+	assert.Equal(t, "b", answer)
+
+	yes, err := ui.Confirm("confirm")
+	require.NoError(t, err)
+	assert.True(t, yes)
+}
+
+func TestNewAppInfo(t *testing.T) {
+	appInfo := testutil.NewAppInfo(t, "task 1\ntask 2\n")
+
+	assert.Equal(t, 2, appInfo.Tasks.Local.Len())
+	assert.Empty(t, appInfo.Tasks.Global.FileUsed(), "global task file should not exist")
+	assert.Equal(t, "task 1\ntask 2\n", testutil.ReadFile(t, appInfo.Tasks.Local.FileUsed()))
+}
@@ -0,0 +1,244 @@
+/*
+Package timelog はタスクの作業時間（セッション）の記録を管理するパッケージです。
+
+作業セッションはタスク・ファイルと同じワークスペースの ".qiitask" ディレクトリに
+ある "timelog.json" に記録されます。セッションの合計時間は、タスクの "spent:" タ
+グにも加算されます。
+*/
+package timelog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/1set/todotxt"
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/clock"
+	"github.com/Qithub-BOT/QiiTask/core/journal"
+	"github.com/Qithub-BOT/QiiTask/core/safefile"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// NameFile は作業時間の記録ファイルのファイル名です。
+const NameFile = "timelog.json"
+
+// TagSpent はタスクの作業時間の合計を記録するタグ名です。（例: "spent:1h30m"）
+const TagSpent = "spent"
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Session は 1 回分の作業の記録です。End が nil の場合は計測中です。
+type Session struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"`
+	Task  string     `json:"task"` // タスクの Todo（タグを除いた本文）
+	File  string     `json:"file"` // タスク・ファイルの絶対パス
+}
+
+// Log は作業セッションの一覧を保持する型です。
+type Log struct {
+	Sessions []Session `json:"sessions"`
+	pathFile string    // 記録ファイルのパス
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// Load は pathFileTarget（タスク・ファイル）の作業時間の記録を読み込みます。記録
+// ファイルが存在しない場合は空の記録を返します。
+func Load(pathFileTarget string) (*Log, error) {
+	log := &Log{
+		Sessions: []Session{},
+		pathFile: PathLog(pathFileTarget),
+	}
+
+	if !util.IsFile(log.pathFile) {
+		return log, nil
+	}
+
+	data, err := os.ReadFile(log.pathFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read time log file")
+	}
+
+	if err := json.Unmarshal(data, log); err != nil {
+		return nil, errors.Wrap(err, "failed to parse time log file")
+	}
+
+	return log, nil
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// AddSpent は task の "spent:" タグに duration を加算し、加算後の合計を返します。
+func AddSpent(task *todotxt.Task, duration time.Duration) (time.Duration, error) {
+	total, err := Spent(task)
+	if err != nil {
+		return 0, err
+	}
+
+	total += duration
+
+	if task.AdditionalTags == nil {
+		task.AdditionalTags = map[string]string{}
+	}
+
+	task.AdditionalTags[TagSpent] = FormatSpent(total)
+
+	return total, nil
+}
+
+// FindRunning は pathFilesTarget（タスク・ファイル）の作業時間の記録から、計測
+// 中のセッションとその記録を返します。計測中のセッションがない場合は nil を返し
+// ます。
+func FindRunning(pathFilesTarget ...string) (*Log, *Session, error) {
+	for _, pathFile := range pathFilesTarget {
+		log, err := Load(pathFile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if running := log.Running(); running != nil {
+			return log, running, nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// FormatSpent は duration を分単位に丸めた "1h30m" の書式で返します。
+func FormatSpent(duration time.Duration) string {
+	result := duration.Round(time.Minute).String()
+
+	if strings.HasSuffix(result, "m0s") {
+		result = strings.TrimSuffix(result, "0s")
+	}
+
+	if strings.HasSuffix(result, "h0m") {
+		result = strings.TrimSuffix(result, "0m")
+	}
+
+	if result == "0s" {
+		result = "0m"
+	}
+
+	return result
+}
+
+// PathLog は pathFileTarget の作業時間を記録するファイルのパスを返します。記録
+// ファイルはジャーナルと同じディレクトリに置かれます。
+func PathLog(pathFileTarget string) string {
+	return filepath.Join(filepath.Dir(journal.PathJournal(pathFileTarget)), NameFile)
+}
+
+// Spent は task の "spent:" タグの作業時間を返します。タグがない場合は 0 を返し
+// ます。
+func Spent(task *todotxt.Task) (time.Duration, error) {
+	value := task.AdditionalTags[TagSpent]
+	if value == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "不正な作業時間です: %v:%v", TagSpent, value)
+	}
+
+	return duration, nil
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Add は終了済みの session を記録に追加します。
+func (l *Log) Add(session Session) {
+	l.Sessions = append(l.Sessions, session)
+}
+
+// PathFile は記録ファイルのパスを返します。
+func (l *Log) PathFile() string {
+	return l.pathFile
+}
+
+// Running は計測中のセッションを返します。計測中のセッションがない場合は nil を
+// 返します。
+func (l *Log) Running() *Session {
+	for i := len(l.Sessions) - 1; i >= 0; i-- {
+		if l.Sessions[i].End == nil {
+			return &l.Sessions[i]
+		}
+	}
+
+	return nil
+}
+
+// Save は記録をファイルに保存します。
+func (l *Log) Save() error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal time log")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.pathFile), 0o755); err != nil {
+		return errors.Wrap(err, "failed to create time log directory")
+	}
+
+	return errors.Wrap(safefile.WriteFile(l.pathFile, data, 0), "failed to write time log file")
+}
+
+// Start は pathFileTask の task の計測を開始します。すでに計測中のセッションが
+// ある場合はエラーを返します。
+func (l *Log) Start(task *todotxt.Task, pathFileTask string) (*Session, error) {
+	if running := l.Running(); running != nil {
+		return nil, errors.Errorf("すでに計測中のタスクがあります: %v", running.Task)
+	}
+
+	pathFileAbs, err := filepath.Abs(pathFileTask)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get absolute path")
+	}
+
+	l.Add(Session{
+		Start: clock.TimeNow(),
+		Task:  task.Todo,
+		File:  pathFileAbs,
+	})
+
+	return &l.Sessions[len(l.Sessions)-1], nil
+}
+
+// Stop は計測中のセッションを終了して返します。計測中のセッションがない場合は
+// エラーを返します。
+func (l *Log) Stop() (*Session, error) {
+	running := l.Running()
+	if running == nil {
+		return nil, errors.New("計測中のタスクはありません")
+	}
+
+	timeEnd := clock.TimeNow()
+	running.End = &timeEnd
+
+	return running, nil
+}
+
+// Duration はセッションの作業時間を返します。計測中の場合は現在までの時間です。
+func (s *Session) Duration() time.Duration {
+	if s.End == nil {
+		return clock.TimeNow().Sub(s.Start)
+	}
+
+	return s.End.Sub(s.Start)
+}
@@ -0,0 +1,128 @@
+package timelog_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/Qithub-BOT/QiiTask/core/timelog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTimeNow は clock.TimeNow を times の順に時刻を返す関数に置き換えます。
+func mockTimeNow(t *testing.T, times ...time.Time) {
+	t.Helper()
+
+	testutil.MockTimeNowFunc(t, func() time.Time {
+		result := times[0]
+
+		if len(times) > 1 {
+			times = times[1:]
+		}
+
+		return result
+	})
+}
+
+func TestPathLog(t *testing.T) {
+	for _, test := range []struct {
+		input  string
+		expect string
+	}{
+		{"/foo/todo.txt", "/foo/.qiitask/timelog.json"},
+		{"/foo/.qiitask/todo.txt", "/foo/.qiitask/timelog.json"},
+		{"/home/.config/qiitask/todo.txt", "/home/.config/qiitask/timelog.json"},
+	} {
+		expect := filepath.FromSlash(test.expect)
+		actual := timelog.PathLog(filepath.FromSlash(test.input))
+
+		assert.Equal(t, expect, actual)
+	}
+}
+
+func TestFormatSpent(t *testing.T) {
+	for _, test := range []struct {
+		input  time.Duration
+		expect string
+	}{
+		{0, "0m"},
+		{29 * time.Second, "0m"},
+		{45 * time.Minute, "45m"},
+		{90*time.Minute + 20*time.Second, "1h30m"},
+		{2 * time.Hour, "2h"},
+	} {
+		assert.Equal(t, test.expect, timelog.FormatSpent(test.input), "input: %v", test.input)
+	}
+}
+
+func TestAddSpent(t *testing.T) {
+	task, err := todotxt.ParseTask("write docs spent:1h10m")
+	require.NoError(t, err)
+
+	total, err := timelog.AddSpent(task, 50*time.Minute)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2*time.Hour, total)
+	assert.Equal(t, "2h", task.AdditionalTags[timelog.TagSpent])
+
+	// Task without tags
+	task = &todotxt.Task{Todo: "no tags"}
+
+	total, err = timelog.AddSpent(task, 5*time.Minute)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5*time.Minute, total)
+	assert.Equal(t, "5m", task.AdditionalTags[timelog.TagSpent])
+}
+
+func TestSpent_malformed(t *testing.T) {
+	task, err := todotxt.ParseTask("write docs spent:soon")
+	require.NoError(t, err)
+
+	_, err = timelog.AddSpent(task, time.Minute)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "不正な作業時間です")
+}
+
+func TestLog_start_stop(t *testing.T) {
+	timeStart := time.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	mockTimeNow(t, timeStart, timeStart.Add(25*time.Minute))
+
+	pathFileTask := filepath.Join(t.TempDir(), "todo.txt")
+
+	log, err := timelog.Load(pathFileTask)
+	require.NoError(t, err)
+	assert.Nil(t, log.Running())
+
+	_, err = log.Stop()
+	require.Error(t, err, "stopping without running session should be an error")
+
+	task := &todotxt.Task{Todo: "write docs"}
+
+	session, err := log.Start(task, pathFileTask)
+	require.NoError(t, err)
+	assert.Equal(t, "write docs", session.Task)
+	assert.Equal(t, pathFileTask, session.File)
+
+	_, err = log.Start(task, pathFileTask)
+	require.Error(t, err, "it should not start two sessions at once")
+
+	require.NoError(t, log.Save())
+
+	// Reload from file
+	log, err = timelog.Load(pathFileTask)
+	require.NoError(t, err)
+	require.NotNil(t, log.Running())
+
+	session, err = log.Stop()
+	require.NoError(t, err)
+
+	assert.Equal(t, 25*time.Minute, session.Duration())
+	assert.Nil(t, log.Running())
+	assert.FileExists(t, log.PathFile())
+}
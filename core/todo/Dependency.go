@@ -0,0 +1,253 @@
+package todo
+
+import (
+	"strings"
+
+	"github.com/1set/todotxt"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// TagDep は依存先のタスクの id を指定するタグ名です。複数の場合はカンマ区切りで
+// 指定します。（例: "dep:design,review"）
+const TagDep = "dep"
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// depGraph は "dep:" タグによるタスクの依存関係（有向グラフ）です。
+type depGraph struct {
+	deps    map[*todotxt.Task][]*todotxt.Task        // タスクごとの依存先のタスク
+	reached map[*todotxt.Task]map[*todotxt.Task]bool // dependsOn のキャッシュ
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// newDepGraph は tasks の依存関係のグラフを返します。存在しない id への依存は無
+// 視されます。
+func newDepGraph(tasks []*todotxt.Task) *depGraph {
+	byID := map[string]*todotxt.Task{}
+
+	for _, task := range tasks {
+		if id := task.AdditionalTags[TagID]; id != "" {
+			if _, ok := byID[id]; !ok {
+				byID[id] = task
+			}
+		}
+	}
+
+	graph := &depGraph{
+		deps:    map[*todotxt.Task][]*todotxt.Task{},
+		reached: map[*todotxt.Task]map[*todotxt.Task]bool{},
+	}
+
+	for _, task := range tasks {
+		for _, id := range splitDep(task.AdditionalTags[TagDep]) {
+			if dep, ok := byID[id]; ok {
+				graph.deps[task] = append(graph.deps[task], dep)
+			}
+		}
+	}
+
+	return graph
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Blockers は task が依存している未完了のタスクを返します。戻り値が空でない場合、
+// task はブロックされています。
+func (t *Todo) Blockers(task *todotxt.Task) []*todotxt.Task {
+	result := []*todotxt.Task{}
+
+	for _, dep := range newDepGraph(t.taskPointers()).deps[task] {
+		if !dep.Completed {
+			result = append(result, dep)
+		}
+	}
+
+	return result
+}
+
+// CheckDependency は "dep:" タグの依存関係が循環している場合にエラーを返します。
+func (t *Todo) CheckDependency() error {
+	return newDepGraph(t.taskPointers()).checkCycle()
+}
+
+// taskPointers は TaskList の各要素のポインタを返します。
+func (t *Todo) taskPointers() []*todotxt.Task {
+	tasks := []todotxt.Task(*t.TaskList)
+	result := make([]*todotxt.Task, len(tasks))
+
+	for i := range tasks {
+		result[i] = &tasks[i]
+	}
+
+	return result
+}
+
+// checkCycle は依存関係が循環している場合に、循環している id を含むエラーを返し
+// ます。
+func (g *depGraph) checkCycle() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := map[*todotxt.Task]int{}
+	path := []*todotxt.Task{}
+
+	var visit func(task *todotxt.Task) []*todotxt.Task
+
+	visit = func(task *todotxt.Task) []*todotxt.Task {
+		state[task] = visiting
+		path = append(path, task)
+
+		for _, dep := range g.deps[task] {
+			switch state[dep] {
+			case visiting:
+				for i := range path {
+					if path[i] == dep {
+						return append(append([]*todotxt.Task{}, path[i:]...), dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[task] = visited
+
+		return nil
+	}
+
+	for task := range g.deps {
+		if state[task] != unvisited {
+			continue
+		}
+
+		if cycle := visit(task); cycle != nil {
+			ids := make([]string, len(cycle))
+
+			for i, item := range cycle {
+				ids[i] = item.AdditionalTags[TagID]
+			}
+
+			return errors.Errorf("タスクの依存関係が循環しています: %v", strings.Join(ids, " -> "))
+		}
+	}
+
+	return nil
+}
+
+// dependsOn は未完了のタスク a が、未完了のタスク b に（間接的にでも）依存して
+// いる場合に true を返します。完了済みのタスクとの依存関係は解消済みとして扱わ
+// れます。
+func (g *depGraph) dependsOn(a, b *todotxt.Task) bool {
+	if a.Completed || b.Completed {
+		return false
+	}
+
+	if reached, ok := g.reached[a]; ok {
+		return reached[b]
+	}
+
+	reached := map[*todotxt.Task]bool{}
+	stack := []*todotxt.Task{a}
+
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, dep := range g.deps[current] {
+			if dep.Completed || reached[dep] {
+				continue
+			}
+
+			reached[dep] = true
+			stack = append(stack, dep)
+		}
+	}
+
+	g.reached[a] = reached
+
+	return reached[b]
+}
+
+// nodeDependsOn はノード a（とその子孫）のいずれかのタスクが、ノード b（とその
+// 子孫）のいずれかのタスクに依存している場合に true を返します。
+func (g *depGraph) nodeDependsOn(a, b *Node) bool {
+	tasksB := Flatten([]*Node{b})
+
+	for _, nodeA := range Flatten([]*Node{a}) {
+		for _, nodeB := range tasksB {
+			if g.dependsOn(nodeA.Task, nodeB.Task) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// order は nodes の順序をできるだけ保ったまま、依存先のノードが先に並ぶように並
+// べ替えます。
+//
+// 子孫のタスク同士の依存関係により兄弟のノード間で制約が循環する場合は、その時
+// 点で先頭のノードを優先します。
+func (g *depGraph) order(nodes []*Node) {
+	remaining := append([]*Node{}, nodes...)
+
+	for i := range nodes {
+		pick := 0
+
+		for j, candidate := range remaining {
+			isReady := true
+
+			for k, other := range remaining {
+				if k != j && g.nodeDependsOn(candidate, other) {
+					isReady = false
+
+					break
+				}
+			}
+
+			if isReady {
+				pick = j
+
+				break
+			}
+		}
+
+		nodes[i] = remaining[pick]
+		remaining = append(remaining[:pick], remaining[pick+1:]...)
+	}
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// splitDep は "dep:" タグの値をカンマで区切った id の一覧を返します。
+func splitDep(value string) []string {
+	result := []string{}
+
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
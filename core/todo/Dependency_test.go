@@ -0,0 +1,112 @@
+package todo_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/1set/todotxt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockers(t *testing.T) {
+	obj := openLossless(t, "design id:d\nx review id:r\nbuild dep:d,r,unknown\nrelease dep:r\n")
+
+	build, err := obj.GetTask(3)
+	require.NoError(t, err)
+
+	blockers := obj.Blockers(build)
+
+	require.Len(t, blockers, 1, "completed and unknown dependencies should not block")
+	assert.Equal(t, "design", blockers[0].Todo)
+
+	release, err := obj.GetTask(4)
+	require.NoError(t, err)
+
+	assert.Empty(t, obj.Blockers(release))
+}
+
+func TestCheckDependency(t *testing.T) {
+	obj := openLossless(t, "task A id:a dep:c\ntask B id:b dep:a\ntask C id:c dep:b\nother\n")
+
+	err := obj.CheckDependency()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "タスクの依存関係が循環しています")
+
+	for _, id := range []string{"a", "b", "c"} {
+		assert.Contains(t, err.Error(), id)
+	}
+}
+
+func TestCheckDependency_self(t *testing.T) {
+	obj := openLossless(t, "task A id:a dep:a\n")
+
+	err := obj.CheckDependency()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "a -> a")
+}
+
+func TestCheckDependency_no_cycle(t *testing.T) {
+	obj := openLossless(t, "task A id:a\ntask B id:b dep:a\ntask C dep:a,b\n")
+
+	assert.NoError(t, obj.CheckDependency())
+}
+
+func TestSortTree_dependency(t *testing.T) {
+	obj := openLossless(t, "deploy id:deploy dep:test\nwrite docs\ntest id:test dep:build\nbuild id:build\n")
+
+	compared := []string{}
+
+	// Always answer that the later task comes first
+	err := obj.SortTree(func(a, b *todotxt.Task) bool {
+		compared = append(compared, a.Todo+" <> "+b.Todo)
+
+		return a.Todo > b.Todo
+	})
+	require.NoError(t, err)
+
+	order := []string{}
+
+	for _, task := range *obj.TaskList {
+		order = append(order, task.Todo)
+	}
+
+	assert.Equal(t, []string{"write docs", "build", "test", "deploy"}, order)
+
+	for _, pair := range compared {
+		assert.True(t, strings.Contains(pair, "write docs"),
+			"tasks with dependency should never be compared: %v", pair)
+	}
+}
+
+func TestSortTree_dependency_cycle(t *testing.T) {
+	data := "task A id:a dep:b\ntask B id:b dep:a\n"
+	obj := openLossless(t, data)
+
+	err := obj.SortTree(func(a, b *todotxt.Task) bool {
+		t.Fatal("it should not ask when the dependency is cyclic")
+
+		return true
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, data, obj.String(), "tasks should be left unsorted")
+}
+
+func TestSortTree_dependency_completed(t *testing.T) {
+	obj := openLossless(t, "task B id:b dep:a\nx task A id:a\n")
+
+	asked := false
+
+	err := obj.SortTree(func(a, b *todotxt.Task) bool {
+		asked = true
+
+		return !a.Completed
+	})
+	require.NoError(t, err)
+
+	assert.True(t, asked, "dependency on a completed task should be ignored")
+	assert.Equal(t, "task B", (*obj.TaskList)[0].Todo)
+}
@@ -0,0 +1,253 @@
+package todo
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/1set/todotxt"
+	"github.com/pkg/errors"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// SimilarityDefault は重複候補とみなすタスクの類似度のデフォルトの閾値です。
+const SimilarityDefault = 0.8
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Duplicate は重複候補のタスクの組です。A は B よりタスク一覧の上にあります。
+type Duplicate struct {
+	A          *todotxt.Task
+	B          *todotxt.Task
+	Similarity float64 // 正規化したタスクの本文の類似度（0〜1）
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// NormalizeText は表記ゆれを吸収するために text を正規化した文字列を返します。
+//
+// 全角英数字・記号は半角に、半角カナは全角に（NFKC）、ひらがなはカタカナに、英
+// 字は小文字に変換され、空白と記号は取り除かれます。
+func NormalizeText(text string) string {
+	var result strings.Builder
+
+	for _, r := range norm.NFKC.String(text) {
+		switch {
+		case unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r):
+			continue
+		case 'ぁ' <= r && r <= 'ゖ', r == 'ゝ', r == 'ゞ':
+			r += 'ァ' - 'ぁ'
+		}
+
+		result.WriteRune(unicode.ToLower(r))
+	}
+
+	return result.String()
+}
+
+// Similarity は正規化した a と b の類似度を 0〜1 で返します。1 は正規化後に同じ
+// 文字列であることを表します。類似度は編集距離（レーベンシュタイン距離）を長い
+// 方の文字数で割った値を 1 から引いたものです。
+func Similarity(a string, b string) float64 {
+	runesA := []rune(NormalizeText(a))
+	runesB := []rune(NormalizeText(b))
+
+	lenMax := len(runesA)
+	if len(runesB) > lenMax {
+		lenMax = len(runesB)
+	}
+
+	if lenMax == 0 {
+		return 1
+	}
+
+	return 1 - float64(distance(runesA, runesB))/float64(lenMax)
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// FindDuplicates は未完了のタスクのうち、本文の類似度が threshold 以上のタスク
+// の組を、類似度の高い順に返します。
+func (t *Todo) FindDuplicates(threshold float64) []Duplicate {
+	tasks := []*todotxt.Task{}
+
+	for _, task := range t.taskPointers() {
+		if !task.Completed {
+			tasks = append(tasks, task)
+		}
+	}
+
+	result := []Duplicate{}
+
+	for i, a := range tasks {
+		for _, b := range tasks[i+1:] {
+			if similarity := Similarity(a.Todo, b.Todo); similarity >= threshold {
+				result = append(result, Duplicate{A: a, B: b, Similarity: similarity})
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Similarity > result[j].Similarity
+	})
+
+	return result
+}
+
+// MergeTask は drop を keep に統合し、drop をタスク一覧から削除します。
+//
+// keep には drop のプロジェクト・コンテキスト・タグが追加されます。優先度・作成
+// 日・期日は keep にない場合のみ drop のものを使います。drop の "id:" を参照する
+// 他のタスクの "parent:" と "dep:" は keep の "id:" に置き換えられます。
+//
+// 削除によりタスク一覧が詰められるため、呼び出し後は keep を含むタスクのポイン
+// タは使わないでください。
+func (t *Todo) MergeTask(keep *todotxt.Task, drop *todotxt.Task) error {
+	if keep == drop {
+		return errors.New("同じタスクは統合できません")
+	}
+
+	if keep.AdditionalTags == nil {
+		keep.AdditionalTags = map[string]string{}
+	}
+
+	keep.Projects = appendMissing(keep.Projects, drop.Projects)
+	keep.Contexts = appendMissing(keep.Contexts, drop.Contexts)
+
+	if !keep.HasPriority() {
+		keep.Priority = drop.Priority
+	}
+
+	if !keep.HasCreatedDate() {
+		keep.CreatedDate = drop.CreatedDate
+	}
+
+	if !keep.HasDueDate() {
+		keep.DueDate = drop.DueDate
+	}
+
+	idKeep := keep.AdditionalTags[TagID]
+	idDrop := drop.AdditionalTags[TagID]
+
+	for key, value := range drop.AdditionalTags {
+		switch key {
+		case TagID:
+			if idKeep == "" {
+				idKeep = value
+				keep.AdditionalTags[TagID] = value
+			}
+		case TagDep:
+			keep.AdditionalTags[TagDep] = strings.Join(
+				appendMissing(splitDep(keep.AdditionalTags[TagDep]), splitDep(value)), ",",
+			)
+		default:
+			if _, ok := keep.AdditionalTags[key]; !ok {
+				keep.AdditionalTags[key] = value
+			}
+		}
+	}
+
+	if idDrop != "" && idDrop != idKeep {
+		t.replaceTagID(idDrop, idKeep)
+	}
+
+	// 統合で自分自身に依存しないようにする
+	if deps := removeItem(splitDep(keep.AdditionalTags[TagDep]), idKeep); len(deps) > 0 {
+		keep.AdditionalTags[TagDep] = strings.Join(deps, ",")
+	} else {
+		delete(keep.AdditionalTags, TagDep)
+	}
+
+	return errors.Wrap(t.RemoveTaskByID(drop.ID), "failed to remove merged task")
+}
+
+// replaceTagID は "parent:" と "dep:" タグで参照している id の from を to に置き
+// 換えます。
+func (t *Todo) replaceTagID(from string, to string) {
+	for _, task := range t.taskPointers() {
+		if task.AdditionalTags[TagParent] == from {
+			task.AdditionalTags[TagParent] = to
+		}
+
+		deps := splitDep(task.AdditionalTags[TagDep])
+		isFound := false
+
+		for i, id := range deps {
+			if id == from {
+				deps[i] = to
+				isFound = true
+			}
+		}
+
+		if isFound {
+			task.AdditionalTags[TagDep] = strings.Join(appendMissing([]string{}, deps), ",")
+		}
+	}
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// distance は a と b の編集距離（レーベンシュタイン距離）を返します。
+func distance(a []rune, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := range a {
+		current[0] = i + 1
+
+		for j := range b {
+			cost := 1
+			if a[i] == b[j] {
+				cost = 0
+			}
+
+			current[j+1] = minInt(previous[j+1]+1, current[j]+1, previous[j]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+// minInt は values の最小値を返します。
+func minInt(values ...int) int {
+	result := values[0]
+
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+
+	return result
+}
+
+// removeItem は items から item を取り除いた一覧を返します。
+func removeItem(items []string, item string) []string {
+	result := []string{}
+
+	for _, value := range items {
+		if value != item {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
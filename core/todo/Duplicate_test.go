@@ -0,0 +1,93 @@
+package todo_test
+
+import (
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeText(t *testing.T) {
+	for _, test := range []struct {
+		input  string
+		expect string
+	}{
+		{"Ｑｉｉｔａｓｋ　２０２１", "qiitask2021"},
+		{"ﾃｽﾄを ｶﾞﾝﾊﾞﾙ", "テストヲガンバル"},
+		{"てすと", "テスト"},
+		{"いすゞ こゝろ", "イスヾコヽロ"},
+		{"Write the README, please!", "writethereadmeplease"},
+		{"メール（返信）", "メール返信"},
+	} {
+		assert.Equal(t, test.expect, todo.NormalizeText(test.input), "input: %v", test.input)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, todo.Similarity("レビューする", "れびゅーする"))
+	assert.Equal(t, 1.0, todo.Similarity("ＰＲ確認", "pr 確認"))
+	assert.Equal(t, 1.0, todo.Similarity("", " "))
+	assert.InDelta(t, 0.8, todo.Similarity("buy milk", "buy mild"), 0.1)
+	assert.Less(t, todo.Similarity("買い物", "fix bug"), todo.SimilarityDefault)
+}
+
+func TestFindDuplicates(t *testing.T) {
+	obj := openLossless(t, "ﾚﾋﾞｭｰする +a\nfix bug\nレビューする +b\nx 2021-01-01 fix bug\nfix bugs\n")
+
+	duplicates := obj.FindDuplicates(todo.SimilarityDefault)
+
+	require.Len(t, duplicates, 2, "completed tasks should be ignored")
+
+	assert.Equal(t, 1, duplicates[0].A.ID)
+	assert.Equal(t, 3, duplicates[0].B.ID)
+	assert.Equal(t, 1.0, duplicates[0].Similarity)
+
+	assert.Equal(t, 2, duplicates[1].A.ID)
+	assert.Equal(t, 5, duplicates[1].B.ID)
+	assert.Less(t, duplicates[1].Similarity, 1.0)
+}
+
+func TestMergeTask(t *testing.T) {
+	obj := openLossless(t, ""+
+		"(B) review @work +a id:r1 est:3\n"+
+		"2021-01-01 review +b @home id:r2 due:2021-02-01 spent:1h dep:other\n"+
+		"step parent:r2\n"+
+		"after dep:r2,other\n"+
+		"other id:other\n",
+	)
+
+	keep, err := obj.GetTask(1)
+	require.NoError(t, err)
+
+	drop, err := obj.GetTask(2)
+	require.NoError(t, err)
+
+	require.NoError(t, obj.MergeTask(keep, drop))
+
+	expect := "" +
+		"(B) 2021-01-01 review @home @work +a +b dep:other est:3 id:r1 spent:1h due:2021-02-01\n" +
+		"step parent:r1\n" +
+		"after dep:r1,other\n" +
+		"other id:other\n"
+
+	assert.Equal(t, expect, obj.String())
+}
+
+func TestMergeTask_self_dependency(t *testing.T) {
+	obj := openLossless(t, "review dep:r2\nreview id:r2\n")
+
+	keep, err := obj.GetTask(1)
+	require.NoError(t, err)
+
+	drop, err := obj.GetTask(2)
+	require.NoError(t, err)
+
+	require.NoError(t, obj.MergeTask(keep, drop))
+
+	assert.Equal(t, "review id:r2\n", obj.String(), "it should not depend on itself after merging")
+
+	err = obj.MergeTask(keep, keep)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "同じタスクは統合できません")
+}
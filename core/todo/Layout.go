@@ -0,0 +1,174 @@
+package todo
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/1set/todotxt"
+)
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// layout はタスク・ファイルの読み込み時の行の並びです。
+//
+// todotxt.TaskList.String() で保存すると、空行やコメント行は削除され、タグの順
+// 序なども正規化されてしまいます。layout は元の行をそのまま保持し、保存時には
+// 変更のないタスクは元の行を、変更のあったタスクのみを再シリアライズした行を出
+// 力します。
+//
+// コメント行・空行・タスクとして解釈できない行は、その直後のタスクに付随する行
+// として保持されます。そのため、タスクを並べ替えたり追加・削除しても、これらの
+// 行は元のタスクの前に出力されます。
+type layout struct {
+	order      []int            // 読み込み時のタスク ID の並び
+	before     map[int][]string // タスク ID ごとの、そのタスクの直前のタスク以外の行
+	trailer    []string         // 最後のタスクより後のタスク以外の行
+	raw        map[int]string   // タスク ID ごとの元の行
+	origin     map[int]string   // タスク ID ごとの読み込み時の task.String()（変更検知用）
+	newline    string           // 改行コード（"\n" もしくは "\r\n"）
+	eofNewline bool             // ファイルの末尾が改行で終わっていた場合 true
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// newLayout はタスク・ファイルが存在しない場合の空の layout を返します。
+func newLayout() *layout {
+	return &layout{
+		order:      []int{},
+		before:     map[int][]string{},
+		trailer:    []string{},
+		raw:        map[int]string{},
+		origin:     map[int]string{},
+		newline:    "\n",
+		eofNewline: true,
+	}
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// line は task を出力する行を返します。読み込み時から変更のないタスクの場合は元
+// の行を返します。
+func (l *layout) line(task todotxt.Task) string {
+	if raw, ok := l.raw[task.ID]; ok && l.origin[task.ID] == task.String() {
+		return raw
+	}
+
+	return task.String()
+}
+
+// render は tasks を layout にあわせて出力します。
+//
+// 各タスクの前には、読み込み時にそのタスクの直前にあったコメントや空行が出力さ
+// れます。削除されたタスクの前にあった行は、読み込み時の並びで次に残っているタ
+// スクの前（残っていない場合は末尾）に出力されます。追加されたタスクの前には何
+// も出力されません。
+func (l *layout) render(tasks todotxt.TaskList) string {
+	present := map[int]bool{}
+
+	for _, task := range tasks {
+		if _, ok := l.raw[task.ID]; ok {
+			present[task.ID] = true
+		}
+	}
+
+	// 削除されたタスクのコメントを、次に残っているタスクに引き継ぐ
+	attached := map[int][]string{}
+	pending := []string{}
+
+	for _, id := range l.order {
+		pending = append(pending, l.before[id]...)
+
+		if present[id] {
+			attached[id] = pending
+			pending = []string{}
+		}
+	}
+
+	lines := []string{}
+
+	for _, task := range tasks {
+		lines = append(lines, attached[task.ID]...)
+		lines = append(lines, l.line(task))
+
+		delete(attached, task.ID)
+	}
+
+	lines = append(append(lines, pending...), l.trailer...)
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	result := strings.Join(lines, l.newline)
+
+	if l.eofNewline {
+		result += l.newline
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// parseTaskList は todo.txt 形式の data をパースしてタスクのリストと、元の行の並
+// びを返します。
+//
+// 空行、コメント行（todotxt.IgnoreComments が true の場合）、タスクとして解釈で
+// きない行（不正な日付など）はタスクに含まれませんが、layout には元のまま保持さ
+// れます。
+func parseTaskList(data []byte) (todotxt.TaskList, *layout, error) {
+	tasklist := todotxt.NewTaskList()
+	layoutData := newLayout()
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	if bytes.Contains(data, []byte("\r\n")) {
+		layoutData.newline = "\r\n"
+	}
+
+	layoutData.eofNewline = len(data) == 0 || bytes.HasSuffix(data, []byte("\n"))
+
+	pending := []string{}
+	taskID := 1
+
+	for scanner.Scan() {
+		raw := strings.TrimSuffix(scanner.Text(), "\r")
+		text := strings.TrimSpace(raw)
+
+		if text == "" || (todotxt.IgnoreComments && strings.HasPrefix(text, "#")) {
+			pending = append(pending, raw)
+
+			continue
+		}
+
+		task, err := todotxt.ParseTask(text)
+		if err != nil {
+			pending = append(pending, raw)
+
+			continue
+		}
+
+		task.ID = taskID
+		tasklist = append(tasklist, *task)
+
+		layoutData.order = append(layoutData.order, taskID)
+		layoutData.before[taskID] = pending
+		layoutData.raw[taskID] = raw
+		layoutData.origin[taskID] = task.String()
+
+		pending = []string{}
+		taskID++
+	}
+
+	layoutData.trailer = pending
+
+	return tasklist, layoutData, scanner.Err()
+}
@@ -0,0 +1,148 @@
+package todo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 正規化されると順序や書式が変わる行、コメント、空行を含むタスク・ファイル
+const dataLossless = "# Header comment\n" +
+	"\n" +
+	"(B) task 1 +proj @ctx key:value\n" +
+	"  task 2 with indent\n" +
+	"# Section\n" +
+	"task 3 due:2021-02-03\n" +
+	"(A) task 4 @home\n"
+
+func openLossless(t *testing.T, data string) *todo.Todo {
+	t.Helper()
+
+	pathFile := filepath.Join(t.TempDir(), todo.NameFile)
+
+	require.NoError(t, os.WriteFile(pathFile, []byte(data), 0o600))
+
+	obj, err := todo.Open(pathFile)
+	require.NoError(t, err)
+
+	return obj
+}
+
+func TestString_round_trip(t *testing.T) {
+	obj := openLossless(t, dataLossless)
+
+	assert.Equal(t, 4, obj.Len(), "comments and blank lines should not be tasks")
+	assert.Equal(t, dataLossless, obj.String(), "unchanged tasks should be output as is")
+}
+
+func TestString_round_trip_crlf_and_no_eof_newline(t *testing.T) {
+	data := "# comment\r\ntask 1 +b +a\r\ntask 2"
+
+	obj := openLossless(t, data)
+
+	assert.Equal(t, data, obj.String())
+}
+
+func TestString_sorted(t *testing.T) {
+	obj := openLossless(t, dataLossless)
+
+	// Reverse the order
+	obj.CustomSort(func(a, b int) bool {
+		return a > b
+	})
+
+	expect := "(A) task 4 @home\n" +
+		"# Section\n" +
+		"task 3 due:2021-02-03\n" +
+		"  task 2 with indent\n" +
+		"# Header comment\n" +
+		"\n" +
+		"(B) task 1 +proj @ctx key:value\n"
+
+	assert.Equal(t, expect, obj.String(), "comments should move with the task that follows them")
+}
+
+func TestString_modified_task_only(t *testing.T) {
+	obj := openLossless(t, dataLossless)
+
+	task, err := obj.GetTask(1)
+	require.NoError(t, err)
+
+	task.Completed = true
+
+	newTask, err := todotxt.ParseTask("task 5")
+	require.NoError(t, err)
+
+	obj.AddTask(newTask)
+	require.NoError(t, obj.RemoveTaskByID(4))
+
+	expect := "# Header comment\n" +
+		"\n" +
+		"x (B) task 1 @ctx +proj key:value\n" +
+		"  task 2 with indent\n" +
+		"# Section\n" +
+		"task 3 due:2021-02-03\n" +
+		"task 5\n"
+
+	assert.Equal(t, expect, obj.String(), "only the changed task should be re-serialized")
+}
+
+func TestString_removed_task_keeps_comments(t *testing.T) {
+	obj := openLossless(t, dataLossless)
+
+	require.NoError(t, obj.RemoveTaskByID(3))
+
+	expect := "# Header comment\n" +
+		"\n" +
+		"(B) task 1 +proj @ctx key:value\n" +
+		"  task 2 with indent\n" +
+		"# Section\n" +
+		"(A) task 4 @home\n"
+
+	assert.Equal(t, expect, obj.String(), "comments of the removed task should move to the next task")
+}
+
+func TestString_split_keeps_section(t *testing.T) {
+	data := "# Work\n" +
+		"write report +work\n" +
+		"\n" +
+		"# Home\n" +
+		"clean room @home\n"
+
+	obj := openLossless(t, data)
+
+	task, err := obj.GetTask(1)
+	require.NoError(t, err)
+
+	_, err = obj.Split(task, []string{"draft", "review"})
+	require.NoError(t, err)
+
+	expect := "# Work\n" +
+		"write report +work id:t1\n" +
+		"draft +work parent:t1\n" +
+		"review +work parent:t1\n" +
+		"\n" +
+		"# Home\n" +
+		"clean room @home\n"
+
+	assert.Equal(t, expect, obj.String(), "split tasks should stay under their own section")
+}
+
+func TestString_unparsable_line(t *testing.T) {
+	data := "task 1\n# comment\ntask 2 due:2021-13-45\ntask 3\n"
+
+	obj := openLossless(t, data)
+
+	assert.Equal(t, 2, obj.Len(), "unparsable line should not be a task")
+	assert.Equal(t, data, obj.String(), "unparsable line should be kept as is")
+
+	require.NoError(t, obj.RemoveTaskByID(2))
+
+	assert.Equal(t, "task 1\n# comment\ntask 2 due:2021-13-45\n", obj.String(),
+		"unparsable line should stay even if the following task is removed")
+}
@@ -3,18 +3,36 @@ package todo
 import (
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/safefile"
 	"github.com/pkg/errors"
 )
 
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+const (
+	// PrefixIDLocal はローカルのタスクの ID に付ける接頭辞です。（例: "L3"）
+	PrefixIDLocal = "L"
+	// PrefixIDGlobal はグローバルのタスクの ID に付ける接頭辞です。（例: "G7"）
+	PrefixIDGlobal = "G"
+)
+
 // ----------------------------------------------------------------------------
 //  型の定義
 // ----------------------------------------------------------------------------
 
 // Set はローカルおよびグローバルのタスクをセットで保持するための型です。
 type Set struct {
-	Local  *Todo // Local: カレントディレクトリにあるタスク
-	Global *Todo // Global: ユーザのホームディレクトリにあるタスク
+	Local       *Todo  // Local: カレントディレクトリにあるタスク
+	Global      *Todo  // Global: ユーザのホームディレクトリにあるタスク
+	pathDirCurr string // Local タスクの検索開始ディレクトリ
+	pathDirHome string // Global タスクのディレクトリ
+	isFileLocal bool   // Local タスクのファイルが明示的に指定された場合 true
 }
 
 // ----------------------------------------------------------------------------
@@ -39,10 +57,17 @@ var OsUserHomeDir = os.UserHomeDir
 
 // NewSet は新規 Set インスタンスを返します。Set はカレントディレクト
 // リのタスク（Local タスク）およびユーザーのホームディレクトリ （Global タスク）
-// を含みます。
+// を含みます。Local タスクは Find と同様に親ディレクトリを遡って検索されます。
 //
-// 引数はファイルの検索先のパスとして指定する必要があります。
-func NewSet(pathDirCurr, pathDirHome string) (*Set, error) {
+// 引数はファイルの検索先のパスとして指定する必要があります。pathFileLocal が
+// "" 以外の場合、Local タスクは検索せずに pathFileLocal のファイルを使います。
+//
+// Local タスクが Global タスクと同じファイルだった場合（ホームディレクトリで実行
+// した場合など）、Local タスクはファイルのない空のタスクになります。
+//
+// タスク・ファイルが存在するものの読み込めなかった場合（LoadError）はエラーを返
+// さず、そのタスクは空になります。この場合のエラーは LoadErr で取得できます。
+func NewSet(pathDirCurr, pathDirHome, pathFileLocal string) (*Set, error) {
 	var err error
 
 	pathDirCurrAbs, err := normalizePathDirCurr(pathDirCurr)
@@ -55,19 +80,197 @@ func NewSet(pathDirCurr, pathDirHome string) (*Set, error) {
 		return nil, errors.Wrap(err, "failed to get absolute path")
 	}
 
-	list := new(Set)
+	list := &Set{
+		pathDirCurr: pathDirCurrAbs,
+		pathDirHome: pathDirHomeAbs,
+		isFileLocal: pathFileLocal != "",
+	}
+
+	if pathFileLocal != "" {
+		list.Local, err = Open(pathFileLocal)
+	} else {
+		list.Local, err = Find(pathDirCurrAbs)
+	}
 
-	if list.Local, err = New(pathDirCurrAbs); err != nil {
+	if list.Local, err = orBroken(list.Local, err); err != nil {
 		return nil, err
 	}
 
-	if list.Global, err = New(pathDirHomeAbs); err != nil {
+	if list.Global, err = orBroken(New(pathDirHomeAbs)); err != nil {
 		return nil, err
 	}
 
+	list.detachLocal()
+
 	return list, nil
 }
 
+// SelectList は "todo.txt" の代わりに nameFile のファイル名の名前付きリストを、
+// Local および Global のタスクとして読み込み直します。
+//
+// NewSet で Local タスクのファイルが明示的に指定されていた場合、Local タスクは
+// そのまま維持されます。
+func (s *Set) SelectList(nameFile string) error {
+	local, global := s.Local, s.Global
+
+	var err error
+
+	if !s.isFileLocal {
+		if local, err = orBroken(FindFile(s.pathDirCurr, nameFile)); err != nil {
+			return err
+		}
+	}
+
+	if global, err = orBroken(NewFile(s.pathDirHome, nameFile)); err != nil {
+		return err
+	}
+
+	local.NumBackup, global.NumBackup = s.Local.NumBackup, s.Global.NumBackup
+	s.Local, s.Global = local, global
+
+	s.detachLocal()
+
+	return nil
+}
+
+// LoadErr は Local もしくは Global のタスク・ファイルの読み込みに失敗していた場
+// 合に、そのエラーを返します。両方とも失敗していた場合は Local のエラーを返しま
+// す。
+func (s *Set) LoadErr() error {
+	if err := s.Local.LoadErr(); err != nil {
+		return err
+	}
+
+	return s.Global.LoadErr()
+}
+
+// SetNumBackup はローカルおよびグローバルのタスクの保存時に残すバックアップ・
+// ファイルの数をセットします。
+func (s *Set) SetNumBackup(numBackup int) {
+	s.Local.NumBackup = numBackup
+	s.Global.NumBackup = numBackup
+}
+
+// Target はコマンドの操作対象となるタスクを返します。
+//
+// isGlobal が true の場合、ローカルのタスク・ファイルが存在しない場合、もしくは
+// ローカルとグローバルが同じファイルの場合はグローバルのタスクを返します。
+func (s *Set) Target(isGlobal bool) *Todo {
+	if isGlobal || s.Local.FileUsed() == "" || s.IsShared() {
+		return s.Global
+	}
+
+	return s.Local
+}
+
+// IsShared は Local と Global のタスクが同じファイルの場合に true を返します。
+// この場合、Local のタスクは Global のタスクとして扱われます。
+func (s *Set) IsShared() bool {
+	if s.Local == s.Global {
+		return true
+	}
+
+	return isSameFile(s.Local.FileUsed(), s.Global.FileUsed())
+}
+
+// FormatID は Local と Global で ID が重複しないように、タスクの ID に接頭辞を
+// 付けた文字列を返します。（例: "L3", "G7"）
+func (s *Set) FormatID(taskList *Todo, id int) string {
+	if taskList == s.Global {
+		return PrefixIDGlobal + strconv.Itoa(id)
+	}
+
+	return PrefixIDLocal + strconv.Itoa(id)
+}
+
+// Resolve は key で指定されたタスクと、そのタスクを含む Todo を返します。
+//
+// key は FormatID の書式（"L3", "G7"、大文字・小文字は区別しません）もしくは数
+// 字のみの ID です。数字のみの場合は Target(isGlobal) のタスクから検索します。
+func (s *Set) Resolve(key string, isGlobal bool) (*Todo, *todotxt.Task, error) {
+	taskList := s.Target(isGlobal)
+	keyID := strings.TrimSpace(key)
+
+	switch {
+	case strings.HasPrefix(strings.ToUpper(keyID), PrefixIDLocal) && s.IsShared():
+		return nil, nil, errors.Errorf("ローカルのタスクはグローバルと同じファイルです。G の ID を指定してください: %v", key)
+	case strings.HasPrefix(strings.ToUpper(keyID), PrefixIDLocal):
+		taskList, keyID = s.Local, keyID[len(PrefixIDLocal):]
+	case strings.HasPrefix(strings.ToUpper(keyID), PrefixIDGlobal):
+		taskList, keyID = s.Global, keyID[len(PrefixIDGlobal):]
+	}
+
+	id, err := strconv.Atoi(keyID)
+	if err != nil || id < 1 {
+		return nil, nil, errors.Errorf("不正なタスク ID です: %v", key)
+	}
+
+	task, err := taskList.GetTask(id)
+	if err != nil {
+		return nil, nil, errors.Errorf("タスクが見つかりません: %v", key)
+	}
+
+	return taskList, task, nil
+}
+
+// detachLocal は Local が Global と同じタスク・ファイルの場合に、Local をファイ
+// ルのない空のタスクにします。保存先は Local タスクの検索開始ディレクトリです。
+func (s *Set) detachLocal() {
+	if !isSameFile(s.Local.FileUsed(), s.Global.FileUsed()) {
+		return
+	}
+
+	taskList := todotxt.NewTaskList()
+
+	s.Local = &Todo{
+		TaskList:  &taskList,
+		NumBackup: s.Local.NumBackup,
+		pathDir:   s.pathDirCurr,
+		nameFile:  s.Local.nameFile,
+		layout:    newLayout(),
+		search:    s.Local.search,
+	}
+}
+
+// orBroken は err が LoadError の場合に、読み込めなかったタスク・ファイルを指す
+// 空のタスクを返します。LoadError 以外のエラーはそのまま返します。
+func orBroken(obj *Todo, err error) (*Todo, error) {
+	var errLoad *LoadError
+
+	if err == nil || !errors.As(err, &errLoad) {
+		return obj, err
+	}
+
+	taskList := todotxt.NewTaskList()
+
+	return &Todo{
+		TaskList:  &taskList,
+		NumBackup: safefile.NumBackupDefault,
+		pathDir:   filepath.Dir(errLoad.PathFile),
+		pathFile:  errLoad.PathFile,
+		nameFile:  filepath.Base(errLoad.PathFile),
+		layout:    newLayout(),
+		errLoad:   err,
+	}, nil
+}
+
+// isSameFile は pathFileA と pathFileB が同じファイルを指す場合に true を返しま
+// す。どちらかが "" の場合は false を返します。
+func isSameFile(pathFileA, pathFileB string) bool {
+	if pathFileA == "" || pathFileB == "" {
+		return false
+	}
+
+	infoA, errA := os.Stat(pathFileA)
+	infoB, errB := os.Stat(pathFileB)
+
+	if errA == nil && errB == nil {
+		return os.SameFile(infoA, infoB)
+	}
+
+	return filepath.Clean(pathFileA) == filepath.Clean(pathFileB)
+}
+
 func normalizePathDirCurr(path string) (string, error) {
 	var err error
 
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
 	"github.com/Qithub-BOT/QiiTask/core/todo"
 
 	"github.com/stretchr/testify/assert"
@@ -23,7 +25,7 @@ func ExampleNewSet() {
 	pathToCurrentDir := "../../testdata/golden/working_with_task_and_conf/" // Usually "./"
 	pathToUserHomeDir := "../../testdata/golden/user_home_with_task"        // Usually "~/"
 
-	taskList, err := todo.NewSet(pathToCurrentDir, pathToUserHomeDir)
+	taskList, err := todo.NewSet(pathToCurrentDir, pathToUserHomeDir, "")
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -81,7 +83,7 @@ func TestNewSet(t *testing.T) {
 		pathHome := GetPathFromRoot(t, test.pathHome)
 		require.NotEmpty(t, pathHome)
 
-		obj, err := todo.NewSet(pathCurr, pathHome)
+		obj, err := todo.NewSet(pathCurr, pathHome, "")
 		require.NoError(t, err)
 
 		// Test task in current dir (as local task)
@@ -103,7 +105,11 @@ func TestNewSet(t *testing.T) {
 }
 
 func TestNewSet_empty_arg(t *testing.T) {
-	obj, err := todo.NewSet("", "")
+	// Move to an empty dir so that no task file is found in the parent dirs
+	deferReturn := util.ChDir(t.TempDir())
+	defer deferReturn()
+
+	obj, err := todo.NewSet("", "", "")
 	require.NoError(t, err, "empty args should not be error")
 
 	{
@@ -127,7 +133,7 @@ func TestNewSet_empty_arg(t *testing.T) {
 func TestNewSet_empty_dir(t *testing.T) {
 	pathDirCurrDummy := t.TempDir() // Assign empty dir
 	pathDirHomeDummy := t.TempDir() // Assign empty dir
-	obj, err := todo.NewSet(pathDirCurrDummy, pathDirHomeDummy)
+	obj, err := todo.NewSet(pathDirCurrDummy, pathDirHomeDummy, "")
 
 	require.NoError(t, err)
 
@@ -160,7 +166,7 @@ func TestNewSet_fail_to_get_user_home_path(t *testing.T) {
 		return "", errors.New("forced fail")
 	}
 
-	tasks, err := todo.NewSet("", "")
+	tasks, err := todo.NewSet("", "", "")
 
 	require.Nil(t, tasks, "on error returned object should be nil")
 	require.Error(t, err)
@@ -179,7 +185,7 @@ func TestNewSet_fail_to_get_working_path(t *testing.T) {
 		return "", errors.New("forced fail")
 	}
 
-	tasks, err := todo.NewSet("", "")
+	tasks, err := todo.NewSet("", "", "")
 
 	require.Nil(t, tasks, "on error returned object should be nil")
 	require.Error(t, err)
@@ -204,23 +210,23 @@ func TestNewSet_invalid_absolute_path(t *testing.T) {
 
 	{
 		// Error on local task (current dir)
-		result, err := todo.NewSet("assert error", ".")
+		result, err := todo.NewSet("assert error", ".", "")
 		require.Nil(t, result, "on error the object should be nil")
 		require.Error(t, err)
 	}
 	{
 		// Error on global task (user home dir)
-		result, err := todo.NewSet(".", "assert error")
+		result, err := todo.NewSet(".", "assert error", "")
 		require.Nil(t, result, "on error the object should be nil")
 		require.Error(t, err)
 	}
 }
 
 func TestNewSet_not_singleton(t *testing.T) {
-	obj1, err := todo.NewSet(t.TempDir(), t.TempDir())
+	obj1, err := todo.NewSet(t.TempDir(), t.TempDir(), "")
 	require.NoError(t, err)
 
-	obj2, err := todo.NewSet(t.TempDir(), t.TempDir())
+	obj2, err := todo.NewSet(t.TempDir(), t.TempDir(), "")
 	require.NoError(t, err)
 
 	assert.NotSame(t, obj1, obj2, "pointers should not reference the same object")
@@ -251,9 +257,146 @@ func TestNewSet_scan_error(t *testing.T) {
 		pathHome := GetPathFromRoot(t, test.pathGlobal)
 		require.NotEmpty(t, pathHome)
 
-		obj, err := todo.NewSet(pathCurr, pathHome)
+		obj, err := todo.NewSet(pathCurr, pathHome, "")
+		require.NoError(t, err, "unreadable task file should not fail the set")
+
+		err = obj.LoadErr()
 		require.Error(t, err)
-		require.Nil(t, obj, "on error object should be nil")
 		assert.Contains(t, err.Error(), test.expectErrContain)
+
+		var errLoad *todo.LoadError
+
+		require.True(t, errors.As(err, &errLoad), "it should be a LoadError")
+		assert.Contains(t, errLoad.PathFile, filepath.Join("scanner_error", "todo.txt"))
+
+		// 読み込めなかったファイルを空のタスクで上書きしない
+		broken := obj.Local
+		if obj.Local.LoadErr() == nil {
+			broken = obj.Global
+		}
+
+		assert.Equal(t, 0, broken.Len())
+		assert.Error(t, broken.OverWrite(cui.New()), "broken task file should not be overwritten")
+	}
+}
+
+func TestSet_FormatID(t *testing.T) {
+	obj, err := todo.NewSet(t.TempDir(), t.TempDir(), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "L3", obj.FormatID(obj.Local, 3))
+	assert.Equal(t, "G7", obj.FormatID(obj.Global, 7))
+}
+
+func TestSet_Resolve(t *testing.T) {
+	pathDirLocal := t.TempDir()
+	pathDirHome := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, todo.NameFile), []byte("local task\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirHome, todo.NameFile), []byte("global 1\nglobal 2\n"), 0o600))
+
+	obj, err := todo.NewSet(pathDirLocal, pathDirHome, "")
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		key      string
+		isGlobal bool
+		expect   *todo.Todo
+		todo     string
+	}{
+		{"L1", false, obj.Local, "local task"},
+		{"g2", false, obj.Global, "global 2"},
+		{"1", false, obj.Local, "local task"},
+		{"1", true, obj.Global, "global 1"},
+	} {
+		taskList, task, err := obj.Resolve(test.key, test.isGlobal)
+		require.NoError(t, err, "key: %v", test.key)
+
+		assert.Same(t, test.expect, taskList, "key: %v", test.key)
+		assert.Equal(t, test.todo, task.Todo, "key: %v", test.key)
+	}
+
+	for _, key := range []string{"", "L", "X1", "L0", "L2", "G3"} {
+		_, _, err := obj.Resolve(key, false)
+		assert.Error(t, err, "key: %q", key)
+	}
+}
+
+func TestNewSet_local_is_global(t *testing.T) {
+	pathDirHome := t.TempDir()
+	pathDirSub := filepath.Join(pathDirHome, "proj", "sub")
+
+	require.NoError(t, os.MkdirAll(pathDirSub, 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(pathDirHome, ".qiitask"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirHome, ".qiitask", todo.NameFile), []byte("global\n"), 0o600))
+
+	for _, pathDirCurr := range []string{pathDirSub, pathDirHome} {
+		obj, err := todo.NewSet(pathDirCurr, pathDirHome, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, filepath.Join(pathDirHome, ".qiitask", todo.NameFile), obj.Global.FileUsed())
+		assert.Empty(t, obj.Local.FileUsed(), "the global file should not be used as local: %v", pathDirCurr)
+		assert.Empty(t, *obj.Local.TaskList)
+		assert.Same(t, obj.Global, obj.Target(false), "global tasks should be the target")
 	}
 }
+
+func TestSet_IsShared(t *testing.T) {
+	pathDirHome := t.TempDir()
+	pathFileGlobal := filepath.Join(pathDirHome, todo.NameFile)
+
+	require.NoError(t, os.WriteFile(pathFileGlobal, []byte("global 1\n"), 0o600))
+
+	obj, err := todo.NewSet(t.TempDir(), pathDirHome, "")
+	require.NoError(t, err)
+	require.False(t, obj.IsShared())
+
+	obj.Local, err = todo.Open(pathFileGlobal)
+	require.NoError(t, err)
+
+	assert.True(t, obj.IsShared())
+	assert.Same(t, obj.Global, obj.Target(false), "global tasks should be the target")
+
+	_, _, err = obj.Resolve("L1", false)
+
+	require.Error(t, err, "the same task should not be resolved by two IDs")
+	assert.Contains(t, err.Error(), "G の ID を指定してください")
+
+	taskList, _, err := obj.Resolve("1", false)
+
+	require.NoError(t, err)
+	assert.Same(t, obj.Global, taskList)
+}
+
+func TestSet_SelectList(t *testing.T) {
+	pathDirLocal := t.TempDir()
+	pathDirHome := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, todo.NameFile), []byte("default\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirLocal, "work.txt"), []byte("work 1\nwork 2\n"), 0o600))
+
+	obj, err := todo.NewSet(pathDirLocal, pathDirHome, "")
+	require.NoError(t, err)
+
+	obj.SetNumBackup(5)
+
+	require.NoError(t, obj.SelectList("work.txt"))
+
+	assert.Equal(t, filepath.Join(pathDirLocal, "work.txt"), obj.Local.FileUsed())
+	assert.Equal(t, 2, obj.Local.Len())
+	assert.Equal(t, "work.txt", obj.Global.File())
+	assert.Empty(t, obj.Global.FileUsed())
+	assert.Equal(t, 5, obj.Local.NumBackup, "the number of backups should be kept")
+}
+
+func TestSet_SelectList_explicit_file(t *testing.T) {
+	pathFileLocal := filepath.Join(t.TempDir(), "mine.txt")
+
+	obj, err := todo.NewSet(t.TempDir(), t.TempDir(), pathFileLocal)
+	require.NoError(t, err)
+
+	require.NoError(t, obj.SelectList("work.txt"))
+
+	assert.Equal(t, "mine.txt", obj.Local.File(), "explicitly given file should be kept")
+	assert.Equal(t, "work.txt", obj.Global.File())
+}
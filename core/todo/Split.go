@@ -0,0 +1,271 @@
+package todo
+
+import (
+	"fmt"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// AskSplit で分割後の元のタスクの扱いを問い合わせる際の選択肢です。
+const (
+	// AnsSplitParent は元のタスクを分割後のタスクの親タスクとして残す選択肢です。
+	AnsSplitParent = "親タスクとして残す"
+	// AnsSplitDone は元のタスクを完了にする選択肢です。
+	AnsSplitDone = "完了にする"
+)
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Split は task を titles の子タスクに分割し、追加した子タスクを返します。
+//
+// task に "id:" タグがない場合は、未使用の id（"t1", "t2", ...）が付与されます。
+// 子タスクは "parent:" タグで task の子になり、task の優先度・プロジェクト・コン
+// テキストを引き継ぎます。子タスクは task（とその子孫）の直後に追加されます。
+func (t *Todo) Split(task *todotxt.Task, titles []string) ([]*todotxt.Task, error) {
+	if len(titles) == 0 {
+		return nil, errors.New("分割後のタスクがありません")
+	}
+
+	children := make([]todotxt.Task, len(titles))
+
+	for i, title := range titles {
+		child, err := todotxt.ParseTask(title)
+		if err != nil {
+			return nil, errors.Wrapf(err, "不正なタスクです: %v", title)
+		}
+
+		children[i] = *child
+	}
+
+	idMax := 0
+	position := -1
+
+	for i, item := range *t.TaskList {
+		if item.ID > idMax {
+			idMax = item.ID
+		}
+
+		if &(*t.TaskList)[i] == task {
+			position = i + 1
+		}
+	}
+
+	if position < 0 {
+		return nil, errors.New("タスクが見つかりません")
+	}
+
+	if task.AdditionalTags == nil {
+		task.AdditionalTags = map[string]string{}
+	}
+
+	if task.AdditionalTags[TagID] == "" {
+		task.AdditionalTags[TagID] = t.newTagID()
+	}
+
+	for _, descendant := range t.Descendants(task) {
+		for i := range *t.TaskList {
+			if &(*t.TaskList)[i] == descendant && i+1 > position {
+				position = i + 1
+			}
+		}
+	}
+
+	for i := range children {
+		inherit(&children[i], task)
+
+		children[i].ID = idMax + i + 1
+	}
+
+	// task のポインタは挿入後に無効になる可能性があるため、挿入は最後に行う
+	tasks := append(todotxt.TaskList{}, (*t.TaskList)[:position]...)
+	tasks = append(tasks, children...)
+	tasks = append(tasks, (*t.TaskList)[position:]...)
+
+	*t.TaskList = tasks
+
+	result := make([]*todotxt.Task, len(children))
+
+	for i := range children {
+		result[i] = &(*t.TaskList)[position+i]
+	}
+
+	return result, nil
+}
+
+// SplitAsDone は Split と同様に task を titles のタスクに分割した後、task を完了
+// にします。分割後のタスクは task の子タスクにはならず、"parent:" タグは付きませ
+// ん。
+func (t *Todo) SplitAsDone(task *todotxt.Task, titles []string) ([]*todotxt.Task, error) {
+	id := task.ID
+	hasTagID := task.AdditionalTags[TagID] != ""
+
+	children, err := t.Split(task, titles)
+	if err != nil {
+		return nil, err
+	}
+
+	// Split で task のポインタが無効になっている可能性があるため取得し直す
+	parent, err := t.GetTask(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get split task")
+	}
+
+	parent.Complete()
+
+	if !hasTagID {
+		delete(parent.AdditionalTags, TagID)
+	}
+
+	for _, child := range children {
+		delete(child.AdditionalTags, TagParent)
+	}
+
+	return children, nil
+}
+
+// AskSplit は分割後のタスクと元のタスクの扱いを ui でユーザに問い合わせ、task
+// を分割します。戻り値は結果のメッセージです。保存は呼び出し側で行う必要があり
+// ます。
+//
+// 分割後はタスクの並びが変わるため、task のポインタは使わないでください。
+func (t *Todo) AskSplit(ui *cui.UI, task *todotxt.Task) (string, error) {
+	if task.Completed {
+		return "", errors.Errorf("完了済みのタスクは分割できません: %v", task.Todo)
+	}
+
+	titles, err := ui.InputLines(
+		fmt.Sprintf("分割後のタスクを 1 行に 1 つずつ入力してください: %v", task.Todo),
+		"入力したタスクは、優先度・プロジェクト・コンテキストを引き継ぎます。",
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "error during input")
+	}
+
+	if len(titles) == 0 {
+		return "", errors.New("分割後のタスクが入力されませんでした（タスクに変更はありません）")
+	}
+
+	answer, err := ui.Select(
+		"元のタスクをどうしますか",
+		[]string{AnsSplitParent, AnsSplitDone},
+		AnsSplitParent,
+		"「親タスクとして残す」を選ぶと、分割後のタスクは \"parent:\" タグで元のタスクの子タスクになります。",
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "error during selection")
+	}
+
+	todoOriginal := task.Todo
+	split := t.Split
+
+	if answer == AnsSplitDone {
+		split = t.SplitAsDone
+	}
+
+	children, err := split(task, titles)
+	if err != nil {
+		return "", err
+	}
+
+	msg := fmt.Sprintf("タスクを %d 件に分割しました: %v", len(children), todoOriginal)
+
+	if answer == AnsSplitDone {
+		msg += "\n    元のタスクは完了にしました"
+	}
+
+	return msg, nil
+}
+
+// AssignTagIDs は "id:" タグのないすべてのタスクに未使用の id を付与し、付与し
+// たタスクの数を返します。id の付与のみでは、タスクは変更されたとみなされず
+// "touched:" タグは更新されません。
+func (t *Todo) AssignTagIDs() int {
+	if t.TaskList == nil {
+		return 0
+	}
+
+	count := 0
+
+	for i := range *t.TaskList {
+		task := &(*t.TaskList)[i]
+
+		if task.AdditionalTags[TagID] != "" {
+			continue
+		}
+
+		if task.AdditionalTags == nil {
+			task.AdditionalTags = map[string]string{}
+		}
+
+		task.AdditionalTags[TagID] = t.newTagID()
+		t.carried = append(t.carried, task.String())
+		count++
+	}
+
+	return count
+}
+
+// newTagID は TaskList で未使用の "id:" タグの値を返します。
+func (t *Todo) newTagID() string {
+	used := map[string]bool{}
+
+	for _, task := range *t.TaskList {
+		used[task.AdditionalTags[TagID]] = true
+	}
+
+	for i := 1; ; i++ {
+		if id := fmt.Sprintf("t%d", i); !used[id] {
+			return id
+		}
+	}
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// inherit は child を parent の子タスクにし、parent の優先度・プロジェクト・コン
+// テキストを引き継ぎます。child に指定済みのものは上書きしません。
+func inherit(child *todotxt.Task, parent *todotxt.Task) {
+	if child.AdditionalTags == nil {
+		child.AdditionalTags = map[string]string{}
+	}
+
+	child.AdditionalTags[TagParent] = parent.AdditionalTags[TagID]
+
+	if !child.HasPriority() && parent.HasPriority() {
+		child.Priority = parent.Priority
+	}
+
+	child.Projects = appendMissing(child.Projects, parent.Projects)
+	child.Contexts = appendMissing(child.Contexts, parent.Contexts)
+}
+
+// appendMissing は items に含まれていない adds の要素を items に追加して返します。
+func appendMissing(items []string, adds []string) []string {
+	for _, add := range adds {
+		isFound := false
+
+		for _, item := range items {
+			if item == add {
+				isFound = true
+
+				break
+			}
+		}
+
+		if !isFound {
+			items = append(items, add)
+		}
+	}
+
+	return items
+}
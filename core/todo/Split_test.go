@@ -0,0 +1,118 @@
+package todo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	obj := openLossless(t, "(A) write book +book @home\nother task\n")
+
+	task, err := obj.GetTask(1)
+	require.NoError(t, err)
+
+	children, err := obj.Split(task, []string{"chapter 1", "(B) chapter 2 +draft"})
+	require.NoError(t, err)
+	require.Len(t, children, 2)
+
+	assert.Equal(t, 3, children[0].ID)
+	assert.Equal(t, 4, children[1].ID)
+
+	expect := "(A) write book @home +book id:t1\n" +
+		"(A) chapter 1 @home +book parent:t1\n" +
+		"(B) chapter 2 @home +book +draft parent:t1\n" +
+		"other task\n"
+
+	assert.Equal(t, expect, obj.String())
+}
+
+func TestSplit_existing_children(t *testing.T) {
+	obj := openLossless(t, "epic id:epic\nstep 1 parent:epic\nother id:t1\n")
+
+	task, err := obj.GetTask(1)
+	require.NoError(t, err)
+
+	_, err = obj.Split(task, []string{"step 2"})
+	require.NoError(t, err)
+
+	expect := "epic id:epic\n" +
+		"step 1 parent:epic\n" +
+		"step 2 parent:epic\n" +
+		"other id:t1\n"
+
+	assert.Equal(t, expect, obj.String(), "new children should follow the existing ones")
+
+	// Unused id should be assigned
+	task, err = obj.GetTask(2)
+	require.NoError(t, err)
+
+	_, err = obj.Split(task, []string{"step 1-1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "t2", task.AdditionalTags["id"])
+}
+
+func TestSplit_no_titles(t *testing.T) {
+	obj := openLossless(t, "task\n")
+
+	task, err := obj.GetTask(1)
+	require.NoError(t, err)
+
+	_, err = obj.Split(task, []string{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "分割後のタスクがありません")
+}
+
+func TestSplitAsDone(t *testing.T) {
+	obj := openLossless(t, "(A) write book +book @home\nother task parent:t9\n")
+
+	task, err := obj.GetTask(1)
+	require.NoError(t, err)
+
+	children, err := obj.SplitAsDone(task, []string{"chapter 1", "chapter 2"})
+	require.NoError(t, err)
+	require.Len(t, children, 2)
+
+	assert.Equal(t, "(A) chapter 1 @home +book", children[0].String())
+	assert.Equal(t, "(A) chapter 2 @home +book", children[1].String())
+
+	parent, err := obj.GetTask(1)
+	require.NoError(t, err)
+
+	assert.True(t, parent.Completed, "the split task should be completed")
+	assert.NotContains(t, parent.AdditionalTags, "id", "the assigned id should be removed")
+
+	other, err := obj.GetTask(2)
+	require.NoError(t, err)
+
+	assert.Equal(t, "t9", other.AdditionalTags["parent"], "other tasks should not be changed")
+
+	// Existing id should be kept
+	obj = openLossless(t, "epic id:epic\n")
+
+	task, err = obj.GetTask(1)
+	require.NoError(t, err)
+
+	_, err = obj.SplitAsDone(task, []string{"step"})
+	require.NoError(t, err)
+
+	parent, err = obj.GetTask(1)
+	require.NoError(t, err)
+
+	assert.Equal(t, "epic", parent.AdditionalTags["id"])
+
+	_, err = obj.SplitAsDone(parent, []string{})
+	require.Error(t, err)
+}
+
+func TestAssignTagIDs(t *testing.T) {
+	obj := openLossless(t, "task a id:t1\ntask b\nx task c\n")
+
+	assert.Equal(t, 2, obj.AssignTagIDs())
+	assert.Equal(t, 0, obj.AssignTagIDs(), "tasks with id should not be changed")
+
+	assert.Equal(t, "task a id:t1\ntask b id:t2\nx task c id:t3\n", obj.String())
+}
@@ -1,13 +1,21 @@
 package todo
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/1set/todotxt"
 	"github.com/KEINOS/go-utiles/util"
 	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/diff"
+	"github.com/Qithub-BOT/QiiTask/core/journal"
+	"github.com/Qithub-BOT/QiiTask/core/safefile"
+	"github.com/Qithub-BOT/QiiTask/core/workspace"
 	"github.com/pkg/errors"
 )
 
@@ -15,9 +23,24 @@ import (
 //  Constants
 // ----------------------------------------------------------------------------
 
-// NameFile はタスクのファイル名です。この定数値がファイルの読み込みに使われます。
+// NameFile はデフォルトのタスクのファイル名です。名前付きリストを指定しない場合
+// に、この定数値がファイルの読み込みに使われます。
 const NameFile = "todo.txt"
 
+// NameFileDone は完了済みのタスクを保管（アーカイブ）するファイル名です。タス
+// ク・ファイルと同じディレクトリに置かれます。
+const NameFileDone = "done.txt"
+
+// タスク・ファイルが外部で変更されていた場合の選択肢です。
+const (
+	// AnsMerge は外部での変更を現在のタスクにマージして保存する選択肢です。
+	AnsMerge = "マージして保存する"
+	// AnsOverWrite は外部での変更を破棄して上書き保存する選択肢です。
+	AnsOverWrite = "上書きして保存する（外部の変更は破棄されます）"
+	// AnsAbort は保存を中止する選択肢です。
+	AnsAbort = "保存を中止する"
+)
+
 // ----------------------------------------------------------------------------
 //  型の定義
 // ----------------------------------------------------------------------------
@@ -25,8 +48,34 @@ const NameFile = "todo.txt"
 // Todo は todotxt.TaskList 型（github.com/1set/todotxt）を埋め込んだ拡張型です。
 type Todo struct {
 	*todotxt.TaskList
-	pathDir  string // タスクの保存先ディレクトリ
-	pathFile string // タスク・ファイルのパス（存在した場合）
+	NumBackup int               // 保存時に残すバックアップ・ファイルの数
+	modTime   time.Time         // 読み込み（保存）時のタスク・ファイルの更新日時
+	pathDir   string            // タスクの保存先ディレクトリ
+	pathFile  string            // タスク・ファイルのパス（存在した場合）
+	hash      string            // 読み込み（保存）時のタスク・ファイルのハッシュ値
+	lines     []string          // 読み込み（保存）時のタスク（3-way マージの base）
+	carried   []string          // 移動・コピーで追加したタスク（保存時に変更とみなさない）
+	nameFile  string            // タスク・ファイルのファイル名
+	layout    *layout           // 読み込み時のタスク・ファイルの行の並び（コメントや空行を含む）
+	search    *workspace.Result // Find で検索した場合の検索結果
+	errLoad   error             // NewSet でタスク・ファイルの読み込みに失敗した場合のエラー
+}
+
+// LoadError はタスク・ファイルが存在するものの、読み込みに失敗した場合のエラー
+// です。
+type LoadError struct {
+	PathFile string // 読み込みに失敗したタスク・ファイルのパス
+	Err      error  // 読み込みに失敗した原因
+}
+
+// Error は error インターフェースの実装です。
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("%v: %v", e.PathFile, e.Err)
+}
+
+// Unwrap は読み込みに失敗した原因のエラーを返します。
+func (e *LoadError) Unwrap() error {
+	return e.Err
 }
 
 // ----------------------------------------------------------------------------
@@ -40,9 +89,20 @@ type Todo struct {
 // 数を使ってローカルおよびグローバルにあるタスクがセットになったオブジェクトを
 // 使います。
 func New(pathDir string) (*Todo, error) {
+	return NewFile(pathDir, NameFile)
+}
+
+// NewFile は New と同じですが、"todo.txt" の代わりに nameFile のファイル名のタス
+// ク・ファイルをロードします。（"work.txt" などの名前付きリスト用）
+func NewFile(pathDir, nameFile string) (*Todo, error) {
 	taskList := todotxt.NewTaskList()
 
-	obj := &Todo{&taskList, "", ""}
+	obj := &Todo{
+		TaskList:  &taskList,
+		NumBackup: safefile.NumBackupDefault,
+		nameFile:  nameFile,
+		layout:    newLayout(),
+	}
 
 	if err := obj.loadTask(pathDir); err != nil {
 		return nil, errors.Wrap(err, "fail to load task")
@@ -51,6 +111,69 @@ func New(pathDir string) (*Todo, error) {
 	return obj, nil
 }
 
+// Find は pathDirStart から親ディレクトリを遡ってタスク・ファイルを検索し、見つ
+// けたタスクを読み込んだ Todo の新規オブジェクトを返します。
+//
+// 検索は git と同様に、タスク・ファイルもしくは ".qiitask" ディレクトリのある
+// ディレクトリ、バージョン管理システムのルート、ファイル・システムのルートのい
+// ずれかで終了します。検索の経路は SearchResult() で取得できます。
+func Find(pathDirStart string) (*Todo, error) {
+	return FindFile(pathDirStart, NameFile)
+}
+
+// FindFile は Find と同じですが、"todo.txt" の代わりに nameFile のファイル名のタ
+// スク・ファイルを検索します。検索は "todo.txt" のあるディレクトリでも終了しま
+// す。
+func FindFile(pathDirStart, nameFile string) (*Todo, error) {
+	result := workspace.Search(pathDirStart, nameFile, NameFile)
+
+	obj, err := NewFile(result.Root, nameFile)
+	if err != nil {
+		return nil, err
+	}
+
+	obj.search = result
+
+	// 見つからなかった場合の保存先は検索の開始ディレクトリ
+	if obj.FileUsed() == "" {
+		obj.pathDir = result.Chain[0].Dir
+	}
+
+	return obj, nil
+}
+
+// Open は pathFile のタスク・ファイルを読み込んだ Todo の新規オブジェクトを返し
+// ます。New や Find と異なり、タスク・ファイルの検索は行いません。
+//
+// pathFile が存在しない場合はタスクが空のオブジェクトを返します。この場合、
+// OverWrite での新規保存先は pathFile になります。
+func Open(pathFile string) (*Todo, error) {
+	pathFileAbs, err := FilePathAbs(pathFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get absolute path")
+	}
+
+	taskList := todotxt.NewTaskList()
+
+	obj := &Todo{
+		TaskList:  &taskList,
+		NumBackup: safefile.NumBackupDefault,
+		nameFile:  filepath.Base(pathFileAbs),
+		pathDir:   filepath.Dir(pathFileAbs),
+		layout:    newLayout(),
+	}
+
+	if !util.IsFile(pathFileAbs) {
+		return obj, nil
+	}
+
+	if err := obj.loadFile(pathFileAbs); err != nil {
+		return nil, errors.Wrap(err, "fail to load task")
+	}
+
+	return obj, nil
+}
+
 // ----------------------------------------------------------------------------
 //  Methods
 // ----------------------------------------------------------------------------
@@ -70,7 +193,7 @@ func (t *Todo) Dir() string {
 
 // File はタスクの読み込み・書き込みをする際に使われるファイル名を返します。
 func (t *Todo) File() string {
-	return NameFile
+	return t.nameFile
 }
 
 // FileUsed は読み込んだタスク・ファイルのパスを返します。
@@ -79,23 +202,29 @@ func (t *Todo) FileUsed() string {
 	return t.pathFile
 }
 
+// LoadErr はタスク・ファイルの読み込みに失敗していた場合に、そのエラーを返しま
+// す。Set の Local および Global のみが、読み込みに失敗した状態になり得ます。
+func (t *Todo) LoadErr() error {
+	return t.errLoad
+}
+
 // findFileTask は Todo.pathDir 以下にあるタスク・ファイルを検索して、最初に見つ
 // けたファイルのパスを返します。ファイルが見つからない場合は "" を返します。
 func (t *Todo) findFileTask() (pathFile string) {
 	// 現在のディレクトリ直下
-	pathFile = filepath.Join(t.pathDir, NameFile)
+	pathFile = filepath.Join(t.pathDir, t.File())
 	if util.IsFile(pathFile) {
 		return pathFile
 	}
 
 	// .qiitask 下
-	pathFile = filepath.Join(t.pathDir, ".qiitask", NameFile)
+	pathFile = filepath.Join(t.pathDir, ".qiitask", t.File())
 	if util.IsFile(pathFile) {
 		return pathFile
 	}
 
 	// .config/qiitask 下
-	pathFile = filepath.Join(t.pathDir, ".config", "qiitask", NameFile)
+	pathFile = filepath.Join(t.pathDir, ".config", "qiitask", t.File())
 	if util.IsFile(pathFile) {
 		return pathFile
 	}
@@ -126,6 +255,32 @@ func (t *Todo) IsKeyDone(key int) bool {
 	return []todotxt.Task(*t.TaskList)[key].Completed
 }
 
+// IsModified は読み込み（保存）後に、タスク・ファイルが外部で変更されていた場合
+// に true を返します。ファイルが読み込まれていない場合は false を返します。
+//
+// 更新日時が読み込み時と異なる場合のみ、ファイルのハッシュ値を比較します。
+func (t *Todo) IsModified() (bool, error) {
+	if t.FileUsed() == "" || !util.IsFile(t.FileUsed()) {
+		return false, nil
+	}
+
+	info, err := os.Stat(t.FileUsed())
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get task file info")
+	}
+
+	if info.ModTime().Equal(t.modTime) {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(t.FileUsed())
+	if err != nil {
+		return false, errors.Wrap(err, "failed to read task file")
+	}
+
+	return hashData(data) != t.hash, nil
+}
+
 // Len は現在のタスクの長さ（len）を返します。
 // タスクもタスクのファイルも存在しない場合は -1 を返します。
 func (t *Todo) Len() int {
@@ -140,6 +295,47 @@ func (t *Todo) Len() int {
 	return length
 }
 
+// lineList は現在のタスクを todo.txt 形式の行のスライスで返します。
+func (t *Todo) lineList() []string {
+	if t.TaskList == nil {
+		return []string{}
+	}
+
+	lines := make([]string, len(*t.TaskList))
+
+	for i, task := range *t.TaskList {
+		lines[i] = task.String()
+	}
+
+	return lines
+}
+
+// loadFile は pathFileTarget のタスク・ファイルを読み込みます。
+func (t *Todo) loadFile(pathFileTarget string) error {
+	data, err := os.ReadFile(pathFileTarget)
+	if err != nil {
+		return &LoadError{
+			PathFile: pathFileTarget,
+			Err:      errors.Wrap(err, "task file found but failed to read"),
+		}
+	}
+
+	tasklist, layoutData, err := parseTaskList(data)
+	if err != nil {
+		return &LoadError{
+			PathFile: pathFileTarget,
+			Err:      errors.Wrap(err, "task file found but another error was produced"),
+		}
+	}
+
+	t.TaskList = &tasklist
+	t.layout = layoutData
+	t.pathDir = filepath.Dir(pathFileTarget)
+	t.pathFile = pathFileTarget
+
+	return t.recordState(data)
+}
+
 // loadTask はタスク・ファイルを読み込みます。
 // ファイルが存在するも、読み込みに失敗した場合は error を返します。ファイルが存
 // 在しない場合は error を返さず何もしません。
@@ -147,6 +343,7 @@ func (t *Todo) loadTask(pathDir string) error {
 	// 保存先・読み込み先のディレクトリとファイルのパスをリセット
 	t.pathDir = pathDir
 	t.pathFile = ""
+	t.layout = newLayout()
 
 	// タスク・ファイルの検索（pathDir 以下を検索します）
 	pathFileTarget := t.findFileTask()
@@ -154,25 +351,70 @@ func (t *Todo) loadTask(pathDir string) error {
 		return nil
 	}
 
-	// タスク・ファイルが存在した場合は読み込み
-	tasklist, err := todotxt.LoadFromPath(pathFileTarget)
+	return t.loadFile(pathFileTarget)
+}
+
+// merge は外部で変更されたタスク・ファイルの内容を、現在のタスクにマージします。
+func (t *Todo) merge() error {
+	data, err := os.ReadFile(t.FileUsed())
+	if err != nil {
+		return errors.Wrap(err, "failed to read modified task file")
+	}
+
+	theirs, layoutTheirs, err := parseTaskList(data)
 	if err != nil {
-		return errors.Wrap(err,
-			"task file found but another error was produced")
+		return errors.Wrap(err, "failed to parse modified task file")
+	}
+
+	linesTheirs := make([]string, len(theirs))
+	for i, task := range theirs {
+		linesTheirs[i] = task.String()
 	}
 
+	merged := diff.Merge3(t.lines, t.lineList(), linesTheirs)
+
+	tasklist, _, err := parseTaskList([]byte(strings.Join(merged, "\n")))
+	if err != nil {
+		return errors.Wrap(err, "failed to parse merged tasks")
+	}
+
+	// コメントや空行は外部で変更された側の並びを使う
 	t.TaskList = &tasklist
-	t.pathDir = filepath.Dir(pathFileTarget)
-	t.pathFile = pathFileTarget
+	t.layout = layoutTheirs
 
 	return nil
 }
 
 // OverWrite は現在状態のタスクを読み込み元のファイルに上書きします。
 // 新規保存の場合は SaveAs を使います。
+//
+// 読み込み（保存）時から変更されたタスクと追加されたタスクは、"touched:" タグが
+// 今日の日付になります。
 func (t *Todo) OverWrite(ui *cui.UI) error {
+	pathFileNew, err := t.prepareSave(ui)
+	if err != nil {
+		return err
+	}
+
+	return t.write(pathFileNew)
+}
+
+// prepareSave は OverWrite の保存前の確認を行います。ファイルへの書き込みは行い
+// ません。
+//
+// タスク・ファイルがない場合は新規作成の確認を行い、その保存先を返します。読み込
+// み後にタスク・ファイルが外部で変更されていた場合は、どうするかを問い合わせま
+// す。保存が中止された場合はエラーを返します。
+func (t *Todo) prepareSave(ui *cui.UI) (string, error) {
+	// 読み込めなかったファイルを空のタスクで上書きしない
+	if t.errLoad != nil {
+		return "", t.errLoad
+	}
+
+	t.touch()
+
 	if t.FileUsed() == "" || !util.IsFile(t.FileUsed()) {
-		pathFileTask := NameFile
+		pathFileTask := filepath.Join(t.Dir(), t.File())
 
 		// 保存の問い合わせ
 		yes, err := ui.Confirm(fmt.Sprintf(
@@ -180,15 +422,177 @@ func (t *Todo) OverWrite(ui *cui.UI) error {
 			pathFileTask,
 		))
 		if err != nil {
-			return errors.Wrap(err, "task save has been canceled")
+			return "", errors.Wrap(err, "task save has been canceled")
 		}
 
 		if !yes {
-			return errors.New("保存しませんでした。（タスクは破棄されました）")
+			return "", errors.New("保存しませんでした。（タスクは破棄されました）")
+		}
+
+		return pathFileTask, nil
+	}
+
+	isModified, err := t.IsModified()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to check task file modification")
+	}
+
+	if isModified {
+		if err := t.resolveConflict(ui); err != nil {
+			return "", err
 		}
+	}
 
-		return t.WriteToPath(pathFileTask)
+	return "", nil
+}
+
+// PathDone は完了済みのタスクを保管するファイル（"done.txt"）のパスを返します。
+func (t *Todo) PathDone() string {
+	return filepath.Join(filepath.Dir(t.PathSave()), NameFileDone)
+}
+
+// PathSave はタスクの保存先のファイルのパスを返します。タスク・ファイルが存在
+// しない場合は、OverWrite で新規作成されるファイルのパスを返します。
+func (t *Todo) PathSave() string {
+	if t.FileUsed() != "" {
+		return t.FileUsed()
 	}
 
-	return t.WriteToPath(t.FileUsed())
+	return filepath.Join(t.Dir(), t.File())
+}
+
+// recordState は data をタスク・ファイルの内容として、変更検知用のハッシュ値、更
+// 新日時および 3-way マージ用の base の行を記録します。
+func (t *Todo) recordState(data []byte) error {
+	info, err := os.Stat(t.FileUsed())
+	if err != nil {
+		return errors.Wrap(err, "failed to get task file info")
+	}
+
+	t.hash = hashData(data)
+	t.modTime = info.ModTime()
+	t.lines = t.lineList()
+	t.carried = nil
+
+	return nil
+}
+
+// Reload はタスク・ファイルを読み込み直します。読み込み後に作成もしくは削除さ
+// れたタスク・ファイルも反映されます。（削除された場合はタスクが空になります）
+// エラーの場合は読み込み前の状態を維持します。
+func (t *Todo) Reload() error {
+	backup := *t
+	taskList := todotxt.NewTaskList()
+
+	t.TaskList = &taskList
+	t.hash = ""
+	t.lines = nil
+	t.carried = nil
+	t.modTime = time.Time{}
+
+	if err := t.loadTask(t.pathDir); err != nil {
+		*t = backup
+
+		return errors.Wrap(err, "failed to reload task")
+	}
+
+	return nil
+}
+
+// resolveConflict はタスク・ファイルが外部で変更されていた場合に、ユーザーに中止、
+// 上書き、マージのいずれかを問い合わせます。中止された場合は error を返します。
+func (t *Todo) resolveConflict(ui *cui.UI) error {
+	msg := fmt.Sprintf(
+		"タスク・ファイルが読み込み後に変更されています。どうしますか？ (%v)",
+		t.FileUsed(),
+	)
+	helpMsg := util.HereDoc(`
+		ソート中などに、他のユーザーやエディタでタスク・ファイルが変更されました。
+		マージを選択すると、外部での変更を現在のタスクに反映して保存します。
+	`)
+
+	answer, err := ui.Select(msg, []string{AnsMerge, AnsOverWrite, AnsAbort}, AnsAbort, helpMsg)
+	if err != nil {
+		return errors.Wrap(err, "task save has been canceled")
+	}
+
+	switch answer {
+	case AnsMerge:
+		return t.merge()
+	case AnsOverWrite:
+		return nil
+	default:
+		return errors.New("保存を中止しました。（タスク・ファイルは外部で変更されています）")
+	}
+}
+
+// save は現在のタスクを読み込み元のファイルに保存し、変更検知用の情報を更新し
+// ます。
+func (t *Todo) save() error {
+	if err := t.SaveAs(t.FileUsed()); err != nil {
+		return err
+	}
+
+	return t.recordState([]byte(t.String()))
+}
+
+// write は prepareSave で確認済みのタスクを保存します。pathFileNew は prepareSave
+// が返した新規作成の保存先です。
+func (t *Todo) write(pathFileNew string) error {
+	if pathFileNew != "" {
+		return t.SaveAs(pathFileNew)
+	}
+
+	return t.save()
+}
+
+// SaveAs は現在のタスクを pathFile に保存します。
+//
+// 保存は一時ファイルを経由してアトミックに行われ、既存のファイルは NumBackup 個
+// までバックアップされます。また、保存前後の差分はジャーナルに記録されます。
+func (t *Todo) SaveAs(pathFile string) error {
+	before := []byte{}
+
+	if util.IsFile(pathFile) {
+		data, err := os.ReadFile(pathFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to read task file before saving")
+		}
+
+		before = data
+	}
+
+	after := []byte(t.String())
+
+	if err := safefile.WriteFile(pathFile, after, t.NumBackup); err != nil {
+		return errors.Wrap(err, "failed to write task file")
+	}
+
+	return errors.Wrap(journal.Record(pathFile, before, after), "failed to record journal")
+}
+
+// SearchResult は Find でタスク・ファイルを検索した際の検索結果を返します。
+// New で生成された場合は nil を返します。
+func (t *Todo) SearchResult() *workspace.Result {
+	return t.search
+}
+
+// String は現在のタスクを todo.txt 形式で返します。
+//
+// todotxt.TaskList.String() と異なり、読み込み時のコメント行や空行、タスクとし
+// て解釈できない行も出力されます。これらの行は直後のタスクとともに移動し、直後
+// のタスクが削除された場合は次のタスクの前に残ります。また、読み込み時から変更
+// のないタスクは元の行のまま出力されるため、保存時の差分は変更したタスクの行の
+// みになります。
+func (t *Todo) String() string {
+	return t.layout.render(*t.TaskList)
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// hashData は data の SHA-256 ハッシュ値を 16 進数の文字列で返します。
+func hashData(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))
 }
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/1set/todotxt"
 	"github.com/KEINOS/go-utiles/util"
@@ -15,6 +16,36 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestFind(t *testing.T) {
+	pathDirRoot := t.TempDir()
+	pathDirSub := filepath.Join(pathDirRoot, "foo", "bar")
+	pathFileTask := filepath.Join(pathDirRoot, ".qiitask", todo.NameFile)
+
+	require.NoError(t, os.MkdirAll(pathDirSub, 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(pathFileTask), 0o755))
+	require.NoError(t, os.WriteFile(pathFileTask, []byte("task in parent\n"), 0o600))
+
+	obj, err := todo.Find(pathDirSub)
+	require.NoError(t, err)
+
+	assert.Equal(t, pathFileTask, obj.FileUsed(), "it should find the task file in the parent dir")
+	assert.Equal(t, pathDirRoot, obj.SearchResult().Root)
+	assert.Len(t, obj.SearchResult().Chain, 3)
+}
+
+func TestFind_not_found(t *testing.T) {
+	pathDirRoot := t.TempDir()
+
+	// Stop searching at the dummy VCS root
+	require.NoError(t, os.Mkdir(filepath.Join(pathDirRoot, ".git"), 0o755))
+
+	obj, err := todo.Find(pathDirRoot)
+	require.NoError(t, err)
+
+	assert.Empty(t, obj.FileUsed())
+	assert.Equal(t, pathDirRoot, obj.Dir(), "it should use the start dir if not found")
+}
+
 func TestGetTodoByKey_key_not_exist(t *testing.T) {
 	pathDirTask := GetPathFromRoot(t, "testdata/sort/random_num_no_priority/")
 
@@ -106,6 +137,46 @@ func TestIsKeyDone(t *testing.T) {
 	}
 }
 
+func TestIsModified(t *testing.T) {
+	pathDirTmp := t.TempDir()
+	pathFileTask := filepath.Join(pathDirTmp, todo.NameFile)
+
+	require.NoError(t, os.WriteFile(pathFileTask, []byte("task 1\ntask 2\n"), 0o600))
+
+	obj, err := todo.New(pathDirTmp)
+	require.NoError(t, err)
+
+	isModified, err := obj.IsModified()
+	require.NoError(t, err)
+	require.False(t, isModified, "it should be false right after loading")
+
+	// Touch only (same content)
+	future := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(pathFileTask, future, future))
+
+	isModified, err = obj.IsModified()
+	require.NoError(t, err)
+	require.False(t, isModified, "it should be false if only the mtime changed")
+
+	// Change content
+	require.NoError(t, os.WriteFile(pathFileTask, []byte("task 1\ntask 3\n"), 0o600))
+	require.NoError(t, os.Chtimes(pathFileTask, future.Add(time.Hour), future.Add(time.Hour)))
+
+	isModified, err = obj.IsModified()
+	require.NoError(t, err)
+	assert.True(t, isModified, "it should be true if the file was changed after loading")
+}
+
+func TestIsModified_no_file_loaded(t *testing.T) {
+	obj, err := todo.New(t.TempDir())
+	require.NoError(t, err)
+
+	isModified, err := obj.IsModified()
+
+	require.NoError(t, err)
+	assert.False(t, isModified)
+}
+
 func TestLen(t *testing.T) {
 	pathDirTask := GetPathFromRoot(t, "testdata/golden/working_with_task_and_conf/")
 
@@ -126,8 +197,53 @@ func TestLen_no_task_loaded(t *testing.T) {
 	assert.Equal(t, expect, actual, "it should be -1 if no task file was loaded")
 }
 
+func TestOpen(t *testing.T) {
+	pathDirTmp := t.TempDir()
+	pathFileTask := filepath.Join(pathDirTmp, "work.txt")
+
+	require.NoError(t, os.WriteFile(pathFileTask, []byte("task 1\ntask 2\n"), 0o600))
+
+	obj, err := todo.Open(pathFileTask)
+	require.NoError(t, err)
+
+	assert.Equal(t, pathFileTask, obj.FileUsed())
+	assert.Equal(t, "work.txt", obj.File())
+	assert.Equal(t, 2, obj.Len())
+}
+
+func TestPathDone(t *testing.T) {
+	pathDirTmp := t.TempDir()
+
+	obj, err := todo.Open(filepath.Join(pathDirTmp, "work.txt"))
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(pathDirTmp, todo.NameFileDone), obj.PathDone(),
+		"it should be in the same directory as the task file")
+}
+
+func TestOpen_not_exist(t *testing.T) {
+	pathDirTmp := t.TempDir()
+	pathFileTask := filepath.Join(pathDirTmp, "work.txt")
+
+	obj, err := todo.Open(pathFileTask)
+	require.NoError(t, err, "missing file should not be an error")
+
+	assert.Empty(t, obj.FileUsed())
+	assert.Equal(t, pathDirTmp, obj.Dir())
+
+	ui := cui.New()
+	ui.ForceTrue = true
+
+	require.NoError(t, obj.OverWrite(ui))
+	assert.FileExists(t, pathFileTask, "it should be saved as the given file name")
+}
+
 func TestOverWrite(t *testing.T) {
-	pathDirTask := GetPathFromRoot(t, "testdata/golden/working_with_task_and_conf/")
+	pathDirGolden := GetPathFromRoot(t, "testdata/golden/working_with_task_and_conf/")
+	pathDirTask := t.TempDir()
+	pathFileTask := filepath.Join(pathDirTask, todo.NameFile)
+
+	require.NoError(t, util.CopyFile(filepath.Join(pathDirGolden, todo.NameFile), pathFileTask))
 
 	obj, err := todo.New(pathDirTask)
 	require.NoError(t, err, "failed to create object during test")
@@ -137,6 +253,8 @@ func TestOverWrite(t *testing.T) {
 
 	err = obj.OverWrite(ui)
 	require.NoError(t, err, "overwriting the loaded file should not be an error")
+
+	assert.FileExists(t, pathFileTask+".1", "the previous task file should be backed up")
 }
 
 func TestOverWrite_accept_overwrite(t *testing.T) {
@@ -185,3 +303,123 @@ func TestOverWrite_task_not_loaded(t *testing.T) {
 		"using OverWrite method when no task was loaded then it should be an error")
 	assert.Contains(t, err.Error(), "task save has been canceled: forced error")
 }
+
+// prepareModifiedTask は todo.txt を読み込んだ後に、タスクを逆順に並べ替え、外部
+// でファイルが変更された状態のオブジェクトを返します。
+func prepareModifiedTask(t *testing.T) (*todo.Todo, string) {
+	t.Helper()
+
+	pathDirTmp := t.TempDir()
+	pathFileTask := filepath.Join(pathDirTmp, todo.NameFile)
+
+	require.NoError(t, os.WriteFile(pathFileTask, []byte("task 1\ntask 2\ntask 3\n"), 0o600))
+
+	obj, err := todo.New(pathDirTmp)
+	require.NoError(t, err)
+
+	obj.CustomSort(func(a, b int) bool {
+		return a > b
+	})
+
+	// External modification
+	require.NoError(t, os.WriteFile(pathFileTask, []byte("task 1\ntask 2 edited\ntask 3\ntask 4\n"), 0o600))
+
+	future := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(pathFileTask, future, future))
+
+	return obj, pathFileTask
+}
+
+func TestOverWrite_modified_abort(t *testing.T) {
+	obj, pathFileTask := prepareModifiedTask(t)
+
+	ui := cui.New()
+	ui.ForceString = todo.AnsAbort
+
+	err := obj.OverWrite(ui)
+
+	require.Error(t, err, "aborting the save should return an error")
+	assert.Contains(t, err.Error(), "保存を中止しました")
+
+	actual, err := os.ReadFile(pathFileTask)
+	require.NoError(t, err)
+	assert.Equal(t, "task 1\ntask 2 edited\ntask 3\ntask 4\n", string(actual),
+		"the externally modified file should be kept as is")
+}
+
+func TestOverWrite_modified_forced_error(t *testing.T) {
+	obj, _ := prepareModifiedTask(t)
+
+	ui := cui.New()
+	ui.ForceError = true
+
+	err := obj.OverWrite(ui)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "task save has been canceled: forced error")
+}
+
+func TestOverWrite_modified_merge(t *testing.T) {
+	obj, pathFileTask := prepareModifiedTask(t)
+
+	ui := cui.New()
+	ui.ForceString = todo.AnsMerge
+
+	require.NoError(t, obj.OverWrite(ui))
+
+	actual, err := os.ReadFile(pathFileTask)
+	require.NoError(t, err)
+	assert.Equal(t, "task 3\ntask 2 edited\ntask 1\ntask 4\n", string(actual),
+		"external edits should be merged into the sorted result")
+
+	isModified, err := obj.IsModified()
+	require.NoError(t, err)
+	assert.False(t, isModified, "it should not be modified right after saving")
+}
+
+func TestOverWrite_modified_overwrite(t *testing.T) {
+	obj, pathFileTask := prepareModifiedTask(t)
+
+	ui := cui.New()
+	ui.ForceString = todo.AnsOverWrite
+
+	require.NoError(t, obj.OverWrite(ui))
+
+	actual, err := os.ReadFile(pathFileTask)
+	require.NoError(t, err)
+	assert.Equal(t, "task 3\ntask 2\ntask 1\n", string(actual),
+		"external edits should be discarded")
+}
+
+func TestReload(t *testing.T) {
+	pathDirTmp := t.TempDir()
+	pathFileTask := filepath.Join(pathDirTmp, todo.NameFile)
+
+	obj, err := todo.New(pathDirTmp)
+	require.NoError(t, err)
+	require.Equal(t, -1, obj.Len())
+
+	// Created after loading
+	require.NoError(t, os.WriteFile(pathFileTask, []byte("task 1\ntask 2\n"), 0o600))
+	require.NoError(t, obj.Reload())
+
+	assert.Equal(t, pathFileTask, obj.FileUsed())
+	assert.Equal(t, 2, obj.Len())
+
+	// Changed after loading
+	require.NoError(t, os.WriteFile(pathFileTask, []byte("task 3\n"), 0o600))
+	require.NoError(t, obj.Reload())
+
+	assert.Equal(t, "task 3\n", obj.String())
+
+	isModified, err := obj.IsModified()
+	require.NoError(t, err)
+	assert.False(t, isModified, "it should record the state of the reloaded file")
+
+	// Removed after loading
+	require.NoError(t, os.Remove(pathFileTask))
+	require.NoError(t, obj.Reload())
+
+	assert.Empty(t, obj.FileUsed())
+	assert.Equal(t, -1, obj.Len(), "it should be empty if the task file was removed")
+}
@@ -0,0 +1,139 @@
+package todo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/clock"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// TagTouched はタスクを最後に変更した日付を記録するタグ名です。タスクを変更して
+// 保存すると、自動的に保存した日付になります。（例: "touched:2021-01-06"）
+const TagTouched = "touched"
+
+// daysWeek は ParseDays で "w"（週）の期間を日数にする際の、1 週間の日数です。
+const daysWeek = 7
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// Touched は task を最後に変更した日付を返します。"touched:" タグがない（もしく
+// は日付として不正な）場合は作成日を返します。どちらもない場合は false を返しま
+// す。
+func Touched(task *todotxt.Task) (time.Time, bool) {
+	if value := task.AdditionalTags[TagTouched]; value != "" {
+		if date, err := time.ParseInLocation(todotxt.DateLayout, value, time.Local); err == nil {
+			return date, true
+		}
+	}
+
+	if task.HasCreatedDate() {
+		return task.CreatedDate, true
+	}
+
+	return time.Time{}, false
+}
+
+// Age は task に最後に手を付けてから today までの経過日数を返します。手を付けた
+// 日は Touched の日付です。分からない場合は false を返します。
+func Age(task *todotxt.Task, today time.Time) (int, bool) {
+	touched, ok := Touched(task)
+	if !ok {
+		return 0, false
+	}
+
+	return DaysBetween(touched, today), true
+}
+
+// FormatAge は task の Age を "12 日前" の形式で返します。今日の場合は "今日"
+// で、手を付けた日が分からない場合は空の文字列を返します。
+func FormatAge(task *todotxt.Task, today time.Time) string {
+	age, ok := Age(task, today)
+
+	switch {
+	case !ok:
+		return ""
+	case age <= 0:
+		return "今日"
+	}
+
+	return fmt.Sprintf("%d 日前", age)
+}
+
+// ParseDays は "30d"（日）、"4w"（週）、"30"（日）形式の期間を日数で返します。
+func ParseDays(value string) (int, error) {
+	unit := 1
+	number := strings.TrimSpace(value)
+
+	switch {
+	case strings.HasSuffix(number, "d"):
+		number = strings.TrimSuffix(number, "d")
+	case strings.HasSuffix(number, "w"):
+		number = strings.TrimSuffix(number, "w")
+		unit = daysWeek
+	}
+
+	days, err := strconv.Atoi(number)
+	if err != nil || days < 1 {
+		return 0, errors.Errorf("invalid period: %q", value)
+	}
+
+	return days * unit, nil
+}
+
+// DaysBetween は from の日付から to の日付までの日数を返します。時刻は無視され、
+// to が from より前の場合は負の値になります。
+func DaysBetween(from, to time.Time) int {
+	// 夏時間で 1 日が 24 時間でない場合があるため、UTC の日付で計算する
+	fromUTC := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toUTC := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+
+	return int(toUTC.Sub(fromUTC).Hours() / 24)
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// touch は読み込み（保存）時から変更されたタスクと、追加されたタスクの
+// "touched:" タグを今日の日付にします。並べ替えただけのタスクや、他のリストから
+// 移動・コピーしたタスクは変更されたとみなしません。
+func (t *Todo) touch() {
+	if t.TaskList == nil {
+		return
+	}
+
+	base := map[string]int{}
+
+	for _, line := range append(append([]string{}, t.lines...), t.carried...) {
+		base[line]++
+	}
+
+	today := clock.TimeNow().Format(todotxt.DateLayout)
+
+	for i := range *t.TaskList {
+		task := &(*t.TaskList)[i]
+		line := task.String()
+
+		if base[line] > 0 {
+			base[line]--
+
+			continue
+		}
+
+		if task.AdditionalTags == nil {
+			task.AdditionalTags = map[string]string{}
+		}
+
+		task.AdditionalTags[TagTouched] = today
+	}
+}
@@ -0,0 +1,154 @@
+package todo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/testutil"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTouched(t *testing.T) {
+	for _, test := range []struct {
+		input  string
+		expect string
+	}{
+		{"2020-12-01 task touched:2021-01-02", "2021-01-02"},
+		{"2020-12-01 task", "2020-12-01"},
+		{"2020-12-01 task touched:yesterday", "2020-12-01"}, // 不正な日付は作成日
+		{"x 2021-01-03 2020-12-01 done touched:2021-01-03", "2021-01-03"},
+	} {
+		task, err := todotxt.ParseTask(test.input)
+		require.NoError(t, err)
+
+		date, ok := todo.Touched(task)
+
+		require.True(t, ok, "input: %v", test.input)
+		assert.Equal(t, test.expect, date.Format(todotxt.DateLayout), "input: %v", test.input)
+	}
+
+	task, err := todotxt.ParseTask("no date touched:bad")
+	require.NoError(t, err)
+
+	_, ok := todo.Touched(task)
+
+	assert.False(t, ok, "it should be false if the task has neither a valid touched: tag nor created date")
+}
+
+func TestAge(t *testing.T) {
+	today := time.Date(2021, 3, 29, 23, 0, 0, 0, time.Local)
+
+	tasks := parseTasks(t, "2021-03-01 created", "no date", "2021-01-01 touched touched:2021-03-22")
+
+	age, ok := todo.Age(tasks[0], today)
+
+	assert.True(t, ok)
+	assert.Equal(t, 28, age)
+
+	_, ok = todo.Age(tasks[1], today)
+
+	assert.False(t, ok, "it should be false if the task has no created date")
+
+	age, ok = todo.Age(tasks[2], today)
+
+	assert.True(t, ok)
+	assert.Equal(t, 7, age, "the touched: date should have priority over the created date")
+}
+
+func TestFormatAge(t *testing.T) {
+	today := time.Date(2021, 3, 29, 23, 0, 0, 0, time.Local)
+
+	tasks := parseTasks(t, "2021-03-01 created", "no date", "new touched:2021-03-29")
+
+	assert.Equal(t, "28 日前", todo.FormatAge(tasks[0], today))
+	assert.Equal(t, "", todo.FormatAge(tasks[1], today))
+	assert.Equal(t, "今日", todo.FormatAge(tasks[2], today))
+}
+
+func TestParseDays(t *testing.T) {
+	for _, test := range []struct {
+		value  string
+		expect int
+	}{
+		{"30d", 30},
+		{"4w", 28},
+		{"14", 14},
+	} {
+		days, err := todo.ParseDays(test.value)
+
+		require.NoError(t, err, test.value)
+		assert.Equal(t, test.expect, days, test.value)
+	}
+
+	for _, value := range []string{"", "d", "0d", "-3", "2m"} {
+		_, err := todo.ParseDays(value)
+
+		assert.Error(t, err, value)
+	}
+}
+
+func TestDaysBetween(t *testing.T) {
+	from := time.Date(2021, 3, 1, 23, 59, 0, 0, time.Local)
+
+	assert.Equal(t, 0, todo.DaysBetween(from, time.Date(2021, 3, 1, 0, 0, 0, 0, time.Local)))
+	assert.Equal(t, 1, todo.DaysBetween(from, time.Date(2021, 3, 2, 0, 1, 0, 0, time.Local)))
+	assert.Equal(t, 31, todo.DaysBetween(from, time.Date(2021, 4, 1, 0, 0, 0, 0, time.Local)))
+	assert.Equal(t, -1, todo.DaysBetween(from, time.Date(2021, 2, 28, 12, 0, 0, 0, time.Local)))
+}
+
+func TestOverWrite_touch(t *testing.T) {
+	testutil.MockTimeNow(t, testutil.Now)
+
+	pathDirTmp := t.TempDir()
+	pathFileTask := filepath.Join(pathDirTmp, todo.NameFile)
+
+	require.NoError(t, os.WriteFile(pathFileTask, []byte(""+
+		"2020-12-01 edited touched:2020-12-02\n"+
+		"2020-12-01 kept touched:2020-12-03\n"+
+		"2020-12-01 done later\n"), 0o600))
+
+	obj, err := todo.New(pathDirTmp)
+	require.NoError(t, err)
+
+	tasks := *obj.TaskList
+
+	tasks[0].Todo = "edited!"
+	tasks[2].Complete()
+	tasks[0], tasks[1] = tasks[1], tasks[0] // 並べ替えは変更とみなさない
+
+	task, err := todotxt.ParseTask("added")
+	require.NoError(t, err)
+
+	obj.AddTask(task)
+
+	ui := cui.New()
+
+	require.NoError(t, obj.OverWrite(ui))
+
+	data, err := os.ReadFile(pathFileTask)
+	require.NoError(t, err)
+
+	completed := (*obj.TaskList)[2].CompletedDate.Format(todotxt.DateLayout)
+
+	assert.Equal(t, ""+
+		"2020-12-01 kept touched:2020-12-03\n"+
+		"2020-12-01 edited! touched:2021-01-06\n"+
+		"x "+completed+" 2020-12-01 done later touched:2021-01-06\n"+
+		"added touched:2021-01-06\n", string(data))
+
+	// 変更せずに保存し直しても変わらない
+	testutil.MockTimeNow(t, time.Date(2021, 2, 1, 0, 0, 0, 0, time.Local))
+
+	require.NoError(t, obj.OverWrite(ui))
+
+	dataAgain, err := os.ReadFile(pathFileTask)
+	require.NoError(t, err)
+
+	assert.Equal(t, string(data), string(dataAgain), "unchanged tasks should keep their touched: tag")
+}
@@ -0,0 +1,180 @@
+package todo
+
+import (
+	"os"
+
+	"github.com/1set/todotxt"
+	"github.com/KEINOS/go-utiles/util"
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/journal"
+	"github.com/Qithub-BOT/QiiTask/core/safefile"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// snapshot は保存前のタスク・ファイルの状態です。保存に失敗した場合の巻き戻しに
+// 使われます。
+type snapshot struct {
+	todo     *Todo
+	pathFile string // 保存先のファイルのパス
+	data     []byte // 保存前のファイルの内容
+	exists   bool   // 保存前にファイルが存在した場合 true
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Transfer は key のタスクを、ローカルとグローバルの間で移動します。isCopy が
+// true の場合は移動せずにコピーします。優先度やタグなどはそのまま維持されます。
+//
+// key は Resolve と同じ書式です。数字のみの場合は toGlobal の反対側（toGlobal が
+// true ならローカル）のタスクから検索します。移動先・移動元のファイルはどちらも
+// 保存され、いずれかの保存に失敗した場合は両方のファイルが保存前の状態に戻りま
+// す。戻り値は移動先に追加されたタスクです。
+func (s *Set) Transfer(ui *cui.UI, key string, toGlobal bool, isCopy bool) (*todotxt.Task, error) {
+	from, task, err := s.Resolve(key, !toGlobal)
+	if err != nil {
+		return nil, err
+	}
+
+	to := s.Local
+	if toGlobal {
+		to = s.Global
+	}
+
+	if from == to || isSameFile(from.PathSave(), to.PathSave()) {
+		return nil, errors.Errorf("移動元と移動先が同じです: %v", key)
+	}
+
+	// 元のタスクとメモリを共有しないように複製する
+	taskNew, err := todotxt.ParseTask(task.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to duplicate task")
+	}
+
+	to.AddTask(taskNew)
+	to.carried = append(to.carried, taskNew.String()) // "touched:" タグは移動元のまま
+
+	if isCopy {
+		return taskNew, saveAtomic(ui, to)
+	}
+
+	if err := from.RemoveTaskByID(task.ID); err != nil {
+		return nil, errors.Wrap(err, "failed to remove task")
+	}
+
+	// 移動先を先に保存する（失敗した場合にタスクが消えないように）
+	return taskNew, saveAtomic(ui, to, from)
+}
+
+// rollback は snapshot の状態にファイルとタスクを戻します。ファイルを戻した変更
+// もジャーナルに記録されます。
+func (s *snapshot) rollback() error {
+	after := []byte{}
+
+	if util.IsFile(s.pathFile) {
+		data, err := os.ReadFile(s.pathFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to read file to roll back")
+		}
+
+		after = data
+	}
+
+	if string(after) != string(s.data) || util.IsFile(s.pathFile) != s.exists {
+		if err := s.restoreFile(after); err != nil {
+			return err
+		}
+	}
+
+	if !s.exists {
+		return s.todo.loadTask(s.todo.Dir())
+	}
+
+	return s.todo.loadFile(s.pathFile)
+}
+
+// restoreFile はファイルを保存前の内容に戻し、その変更をジャーナルに記録します。
+func (s *snapshot) restoreFile(after []byte) error {
+	var err error
+
+	if s.exists {
+		err = safefile.WriteFile(s.pathFile, s.data, s.todo.NumBackup)
+	} else {
+		err = os.Remove(s.pathFile)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "failed to roll back file")
+	}
+
+	return errors.Wrap(journal.Record(s.pathFile, after, s.data), "failed to record journal")
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// saveAtomic は todos を順に保存します。いずれかの保存に失敗した場合は、保存済
+// みのファイルを保存前の内容に戻します。
+//
+// 保存の確認（新規作成や外部での変更の問い合わせ）は書き込みの前にすべて行われ
+// るため、中止された場合はファイル・バックアップ・ジャーナルのいずれも変更され
+// ません。
+func saveAtomic(ui *cui.UI, todos ...*Todo) error {
+	pathsNew := make([]string, len(todos))
+
+	for i, t := range todos {
+		pathFileNew, err := t.prepareSave(ui)
+		if err != nil {
+			return err
+		}
+
+		pathsNew[i] = pathFileNew
+	}
+
+	saved := []*snapshot{}
+
+	for i, t := range todos {
+		snap, err := takeSnapshot(t)
+		if err != nil {
+			return err
+		}
+
+		saved = append(saved, snap)
+
+		if errSave := t.write(pathsNew[i]); errSave != nil {
+			// 保存に失敗したファイル自体も途中まで書き込まれている可能性がある
+			for i := len(saved) - 1; i >= 0; i-- {
+				if err := saved[i].rollback(); err != nil {
+					return errors.Wrapf(errSave, "ロールバックにも失敗しました（%v）", err)
+				}
+			}
+
+			return errors.Wrap(errSave, "保存に失敗したため、変更を元に戻しました")
+		}
+	}
+
+	return nil
+}
+
+// takeSnapshot は t の保存先ファイルの、保存前の状態を返します。
+func takeSnapshot(t *Todo) (*snapshot, error) {
+	snap := &snapshot{todo: t, pathFile: t.PathSave(), data: []byte{}}
+
+	if util.IsFile(snap.pathFile) {
+		data, err := os.ReadFile(snap.pathFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read task file before saving")
+		}
+
+		snap.data = data
+		snap.exists = true
+	}
+
+	return snap, nil
+}
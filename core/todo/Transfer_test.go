@@ -0,0 +1,136 @@
+package todo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/cui"
+	"github.com/Qithub-BOT/QiiTask/core/journal"
+	"github.com/Qithub-BOT/QiiTask/core/safefile"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// prepareSet はローカルとグローバルにタスク・ファイルを作成し、読み込んだ Set
+// とそれぞれのファイルのパスを返します。
+func prepareSet(t *testing.T) (*todo.Set, string, string) {
+	t.Helper()
+
+	pathFileLocal := filepath.Join(t.TempDir(), todo.NameFile)
+	pathFileGlobal := filepath.Join(t.TempDir(), todo.NameFile)
+
+	require.NoError(t, os.WriteFile(pathFileLocal, []byte("(A) local 1 @ctx +proj\nlocal 2\n"), 0o600))
+	require.NoError(t, os.WriteFile(pathFileGlobal, []byte("global 1\n"), 0o600))
+
+	obj, err := todo.NewSet(filepath.Dir(pathFileLocal), filepath.Dir(pathFileGlobal), "")
+	require.NoError(t, err)
+
+	return obj, pathFileLocal, pathFileGlobal
+}
+
+func readFile(t *testing.T, pathFile string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(pathFile)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func TestSet_Transfer_move(t *testing.T) {
+	obj, pathFileLocal, pathFileGlobal := prepareSet(t)
+
+	task, err := obj.Transfer(cui.New(), "1", true, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, task.ID, "it should be appended to the destination")
+	assert.Equal(t, "local 2\n", readFile(t, pathFileLocal))
+	assert.Equal(t, "global 1\n(A) local 1 @ctx +proj\n", readFile(t, pathFileGlobal),
+		"priority and tags should be kept")
+}
+
+func TestSet_Transfer_copy(t *testing.T) {
+	obj, pathFileLocal, pathFileGlobal := prepareSet(t)
+
+	_, err := obj.Transfer(cui.New(), "G1", false, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, "(A) local 1 @ctx +proj\nlocal 2\nglobal 1\n", readFile(t, pathFileLocal))
+	assert.Equal(t, "global 1\n", readFile(t, pathFileGlobal))
+}
+
+func TestSet_Transfer_same_list(t *testing.T) {
+	obj, _, _ := prepareSet(t)
+
+	_, err := obj.Transfer(cui.New(), "G1", true, false)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "移動元と移動先が同じです")
+}
+
+func TestSet_Transfer_same_file(t *testing.T) {
+	obj, _, pathFileGlobal := prepareSet(t)
+
+	var err error
+
+	obj.Local, err = todo.Open(pathFileGlobal)
+	require.NoError(t, err)
+
+	_, err = obj.Transfer(cui.New(), "1", true, false)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "移動元と移動先が同じです")
+	assert.Equal(t, "global 1\n", readFile(t, pathFileGlobal))
+	assert.Empty(t, safefile.ListBackup(pathFileGlobal))
+}
+
+func TestSet_Transfer_cancel_conflict(t *testing.T) {
+	obj, pathFileLocal, pathFileGlobal := prepareSet(t)
+
+	// Modify the source file after loading
+	require.NoError(t, os.WriteFile(pathFileLocal, []byte("(A) local 1 @ctx +proj\nlocal 2\nlocal 3\n"), 0o600))
+
+	ui := cui.New()
+	ui.ForceString = todo.AnsAbort
+
+	_, err := obj.Transfer(ui, "L1", true, false)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "保存を中止しました")
+
+	assert.Equal(t, "global 1\n", readFile(t, pathFileGlobal), "the destination should not be saved")
+	assert.Empty(t, safefile.ListBackup(pathFileGlobal), "no backup should be created")
+	assert.NoFileExists(t, journal.PathJournal(pathFileGlobal), "no journal should be recorded")
+}
+
+func TestSet_Transfer_rollback(t *testing.T) {
+	obj, pathFileLocal, pathFileGlobal := prepareSet(t)
+
+	// Backup and defer restoration
+	oldOsRename := safefile.OsRename
+	defer func() {
+		safefile.OsRename = oldOsRename
+	}()
+
+	// Mock to fail saving the source (local) file only
+	safefile.OsRename = func(oldpath, newpath string) error {
+		if newpath == pathFileLocal {
+			return errors.New("forced error")
+		}
+
+		return oldOsRename(oldpath, newpath)
+	}
+
+	_, err := obj.Transfer(cui.New(), "L1", true, false)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "変更を元に戻しました")
+
+	assert.Equal(t, "(A) local 1 @ctx +proj\nlocal 2\n", readFile(t, pathFileLocal))
+	assert.Equal(t, "global 1\n", readFile(t, pathFileGlobal), "the destination should be rolled back")
+	assert.Equal(t, 2, obj.Local.Len(), "the tasks in memory should be rolled back as well")
+	assert.Equal(t, 1, obj.Global.Len(), "the tasks in memory should be rolled back as well")
+}
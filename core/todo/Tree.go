@@ -0,0 +1,197 @@
+package todo
+
+import (
+	"sort"
+
+	"github.com/1set/todotxt"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+const (
+	// TagID はタスクを識別するためのタグ名です。（例: "id:epic1"）
+	TagID = "id"
+	// TagParent は親タスクの id を指定するタグ名です。（例: "parent:epic1"）
+	TagParent = "parent"
+)
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Node はタスクの親子関係（木構造）の 1 ノードです。
+type Node struct {
+	Task     *todotxt.Task // ノードのタスク（Todo.TaskList の要素を指します）
+	Children []*Node       // 子タスクのノード
+	Depth    int           // 木の深さ（ルートは 0）
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Descendants は task の子孫のタスクを、親から順（深さ優先）に返します。task は
+// GetTask などで取得した TaskList の要素である必要があります。
+func (t *Todo) Descendants(task *todotxt.Task) []*todotxt.Task {
+	result := []*todotxt.Task{}
+
+	for _, node := range Flatten(t.Tree()) {
+		if node.Task != task {
+			continue
+		}
+
+		for _, child := range Flatten(node.Children) {
+			result = append(result, child.Task)
+		}
+	}
+
+	return result
+}
+
+// SortTree は親子関係を保ったままタスクをソートします。
+//
+// 比較は同じ親を持つタスク（兄弟）の間でのみ行われ、異なる親のタスクや親子の
+// タスクが比較されることはありません。ソート後のタスクは、親の直後に子が並ぶ順
+// 序（深さ優先）になります。親子関係のないタスクはすべてルートの兄弟として扱わ
+// れるため、CustomSort と同じ結果になります。
+//
+// "dep:" タグの依存関係は比較より優先され、依存先のタスクは常に依存元より前に並
+// びます。依存関係が決まっているタスク同士で isALessThanB が呼ばれることはあり
+// ません。依存関係が循環している場合は、ソートせずにエラーを返します。
+func (t *Todo) SortTree(isALessThanB func(a, b *todotxt.Task) bool) error {
+	roots := t.Tree()
+	graph := newDepGraph(t.taskPointers())
+
+	if err := graph.checkCycle(); err != nil {
+		return err
+	}
+
+	var sortNodes func(nodes []*Node)
+
+	sortNodes = func(nodes []*Node) {
+		sort.SliceStable(nodes, func(i, j int) bool {
+			switch {
+			case graph.nodeDependsOn(nodes[i], nodes[j]):
+				return false
+			case graph.nodeDependsOn(nodes[j], nodes[i]):
+				return true
+			}
+
+			return isALessThanB(nodes[i].Task, nodes[j].Task)
+		})
+
+		// 比較の回答が依存関係と矛盾していても、依存先が先に並ぶようにする
+		graph.order(nodes)
+
+		for _, node := range nodes {
+			sortNodes(node.Children)
+		}
+	}
+
+	sortNodes(roots)
+
+	sorted := make(todotxt.TaskList, 0, t.Len())
+
+	for _, node := range Flatten(roots) {
+		sorted = append(sorted, *node.Task)
+	}
+
+	*t.TaskList = sorted
+
+	return nil
+}
+
+// Tree はタスクを "id:" と "parent:" タグの親子関係で木構造にした、ルートのノー
+// ドの一覧を返します。ノードの順序は TaskList の順序です。
+//
+// "parent:" の id を持つタスクが存在しない場合や、親子関係が循環している場合は、
+// そのタスクをルートとして扱います。
+func (t *Todo) Tree() []*Node {
+	tasks := []todotxt.Task(*t.TaskList)
+	nodes := make([]*Node, len(tasks))
+	byID := map[string]*Node{}
+
+	for i := range tasks {
+		nodes[i] = &Node{Task: &tasks[i], Children: []*Node{}}
+
+		if id := tasks[i].AdditionalTags[TagID]; id != "" {
+			if _, ok := byID[id]; !ok {
+				byID[id] = nodes[i]
+			}
+		}
+	}
+
+	parentOf := func(node *Node) *Node {
+		return byID[node.Task.AdditionalTags[TagParent]]
+	}
+
+	roots := []*Node{}
+
+	for _, node := range nodes {
+		parent := parentOf(node)
+
+		if parent == nil || isInCycle(node, parentOf) {
+			roots = append(roots, node)
+
+			continue
+		}
+
+		parent.Children = append(parent.Children, node)
+	}
+
+	setDepth(roots, 0)
+
+	return roots
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// Flatten は nodes とその子孫のノードを、親の直後に子が並ぶ順序（深さ優先）の
+// スライスで返します。
+func Flatten(nodes []*Node) []*Node {
+	result := []*Node{}
+
+	for _, node := range nodes {
+		result = append(result, node)
+		result = append(result, Flatten(node.Children)...)
+	}
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// isInCycle は node から親をたどった際に node 自身に戻る（循環している）場合に
+// true を返します。
+func isInCycle(node *Node, parentOf func(*Node) *Node) bool {
+	seen := map[*Node]bool{}
+
+	for current := parentOf(node); current != nil; current = parentOf(current) {
+		if current == node {
+			return true
+		}
+
+		if seen[current] {
+			return false // node を含まない循環
+		}
+
+		seen[current] = true
+	}
+
+	return false
+}
+
+// setDepth は nodes とその子孫のノードに木の深さをセットします。
+func setDepth(nodes []*Node, depth int) {
+	for _, node := range nodes {
+		node.Depth = depth
+
+		setDepth(node.Children, depth+1)
+	}
+}
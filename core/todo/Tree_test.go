@@ -0,0 +1,103 @@
+package todo_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/1set/todotxt"
+	"github.com/Qithub-BOT/QiiTask/core/todo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dataTree = "epic B id:b\n" +
+	"step B-2 parent:b\n" +
+	"standalone\n" +
+	"step A-1 parent:a id:a1\n" +
+	"epic A id:a\n" +
+	"step B-1 parent:b\n" +
+	"step A-1-x parent:a1\n"
+
+// todos はノードのタスクを "深さ:Todo" の書式で返します。
+func todos(nodes []*todo.Node) string {
+	result := []string{}
+
+	for _, node := range todo.Flatten(nodes) {
+		result = append(result, strings.Repeat("-", node.Depth)+node.Task.Todo)
+	}
+
+	return strings.Join(result, "\n")
+}
+
+func TestTree(t *testing.T) {
+	obj := openLossless(t, dataTree)
+
+	expect := strings.Join([]string{
+		"epic B",
+		"-step B-2",
+		"-step B-1",
+		"standalone",
+		"epic A",
+		"-step A-1",
+		"--step A-1-x",
+	}, "\n")
+
+	assert.Equal(t, expect, todos(obj.Tree()))
+}
+
+func TestTree_cycle_and_missing_parent(t *testing.T) {
+	obj := openLossless(t, "task 1 id:x parent:y\ntask 2 id:y parent:x\ntask 3 parent:unknown\ntask 4 parent:x\n")
+
+	expect := strings.Join([]string{
+		"task 1",
+		"-task 4",
+		"task 2",
+		"task 3",
+	}, "\n")
+
+	assert.Equal(t, expect, todos(obj.Tree()), "tasks in the cycle or with missing parent should be roots")
+}
+
+func TestDescendants(t *testing.T) {
+	obj := openLossless(t, dataTree)
+
+	task, err := obj.GetTask(5) // epic A
+	require.NoError(t, err)
+
+	result := []string{}
+
+	for _, child := range obj.Descendants(task) {
+		result = append(result, child.Todo)
+	}
+
+	assert.Equal(t, []string{"step A-1", "step A-1-x"}, result)
+}
+
+func TestSortTree(t *testing.T) {
+	obj := openLossless(t, dataTree)
+
+	compared := []string{}
+
+	// Sort by the Todo text in ascending order
+	err := obj.SortTree(func(a, b *todotxt.Task) bool {
+		compared = append(compared, a.Todo+" <> "+b.Todo)
+
+		return a.Todo < b.Todo
+	})
+	require.NoError(t, err)
+
+	expect := "epic A id:a\n" +
+		"step A-1 parent:a id:a1\n" +
+		"step A-1-x parent:a1\n" +
+		"epic B id:b\n" +
+		"step B-1 parent:b\n" +
+		"step B-2 parent:b\n" +
+		"standalone\n"
+
+	assert.Equal(t, expect, obj.String())
+
+	for _, pair := range compared {
+		assert.False(t, strings.Contains(pair, "epic") && strings.Contains(pair, "step"),
+			"epics and steps should never be compared: %v", pair)
+	}
+}
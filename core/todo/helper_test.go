@@ -4,7 +4,9 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/1set/todotxt"
 	"github.com/KEINOS/go-utiles/util"
+	"github.com/stretchr/testify/require"
 )
 
 // ----------------------------------------------------------------------------
@@ -28,3 +30,19 @@ func GetPathFromRoot(t *testing.T, path string) string {
 
 	return pathTarget
 }
+
+// parseTasks は lines の各行をパースしたタスクを返します。
+func parseTasks(t *testing.T, lines ...string) []*todotxt.Task {
+	t.Helper()
+
+	tasks := make([]*todotxt.Task, len(lines))
+
+	for i, line := range lines {
+		task, err := todotxt.ParseTask(line)
+		require.NoError(t, err)
+
+		tasks[i] = task
+	}
+
+	return tasks
+}
@@ -0,0 +1,97 @@
+package tui
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Input は 1 行のテキスト入力欄です。フィルターの入力やタスクの編集に使います。
+type Input struct {
+	runes  []rune
+	cursor int // カーソルの位置（runes のインデックス）
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// NewInput は value を初期値とし、カーソルを末尾に置いた Input の新規オブジェ
+// クトを返します。
+func NewInput(value string) *Input {
+	runes := []rune(value)
+
+	return &Input{
+		runes:  runes,
+		cursor: len(runes),
+	}
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// HandleKey は key に応じて入力欄を編集します。文字の挿入、Backspace・Delete
+// での削除、左右・Home・End でのカーソル移動に対応しています。key を処理した場
+// 合は true を返します。（Enter や Esc など、処理しないキーは false）
+func (in *Input) HandleKey(key Key) bool {
+	switch key.Code {
+	case KeyRune:
+		in.runes = append(in.runes[:in.cursor], append([]rune{key.Rune}, in.runes[in.cursor:]...)...)
+		in.cursor++
+	case KeyBackspace:
+		if in.cursor > 0 {
+			in.runes = append(in.runes[:in.cursor-1], in.runes[in.cursor:]...)
+			in.cursor--
+		}
+	case KeyDelete:
+		if in.cursor < len(in.runes) {
+			in.runes = append(in.runes[:in.cursor], in.runes[in.cursor+1:]...)
+		}
+	case KeyLeft:
+		if in.cursor > 0 {
+			in.cursor--
+		}
+	case KeyRight:
+		if in.cursor < len(in.runes) {
+			in.cursor++
+		}
+	case KeyHome:
+		in.cursor = 0
+	case KeyEnd:
+		in.cursor = len(in.runes)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// String は入力されている値を返します。
+func (in *Input) String() string {
+	return string(in.runes)
+}
+
+// View は表示幅 width に収まるように、カーソル位置を反転表示した入力欄を返しま
+// す。値が長い場合はカーソルが見えるように先頭を省略します。
+func (in *Input) View(width int) string {
+	if width < 1 {
+		return ""
+	}
+
+	before := in.runes[:in.cursor]
+	under := " "
+	after := ""
+
+	if in.cursor < len(in.runes) {
+		under = string(in.runes[in.cursor])
+		after = string(in.runes[in.cursor+1:])
+	}
+
+	// カーソルが収まるまで先頭を省略する
+	for len(before) > 0 && Width(string(before))+Width(under) > width {
+		before = before[1:]
+	}
+
+	rest := width - Width(string(before)) - Width(under)
+
+	return string(before) + Reverse(under) + Fit(after, rest)
+}
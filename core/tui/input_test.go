@@ -0,0 +1,62 @@
+package tui_test
+
+import (
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/tui"
+	"github.com/stretchr/testify/assert"
+)
+
+// typeKeys は keys を順に in に入力します。
+func typeKeys(in *tui.Input, keys ...tui.Key) {
+	for _, key := range keys {
+		in.HandleKey(key)
+	}
+}
+
+func TestInput_HandleKey(t *testing.T) {
+	in := tui.NewInput("task")
+
+	typeKeys(in,
+		tui.Key{Code: tui.KeyHome},
+		tui.Key{Code: tui.KeyRune, Rune: '('},
+		tui.Key{Code: tui.KeyRune, Rune: 'A'},
+		tui.Key{Code: tui.KeyRune, Rune: ')'},
+		tui.Key{Code: tui.KeyRune, Rune: ' '},
+		tui.Key{Code: tui.KeyEnd},
+		tui.Key{Code: tui.KeyBackspace},
+		tui.Key{Code: tui.KeyLeft},
+		tui.Key{Code: tui.KeyDelete},
+		tui.Key{Code: tui.KeyRight},
+		tui.Key{Code: tui.KeyRune, Rune: 'め'},
+	)
+
+	assert.Equal(t, "(A) taめ", in.String())
+	assert.False(t, in.HandleKey(tui.Key{Code: tui.KeyEnter}), "it should not handle the enter key")
+	assert.False(t, in.HandleKey(tui.Key{Code: tui.KeyEsc}), "it should not handle the esc key")
+}
+
+func TestInput_HandleKey_bounds(t *testing.T) {
+	in := tui.NewInput("")
+
+	typeKeys(in,
+		tui.Key{Code: tui.KeyBackspace},
+		tui.Key{Code: tui.KeyDelete},
+		tui.Key{Code: tui.KeyLeft},
+		tui.Key{Code: tui.KeyRight},
+	)
+
+	assert.Empty(t, in.String())
+}
+
+func TestInput_View(t *testing.T) {
+	in := tui.NewInput("abcdef")
+
+	assert.Equal(t, "abcdef"+tui.Reverse(" ")+"   ", in.View(10), "it should show the cursor at the end")
+	assert.Equal(t, "def"+tui.Reverse(" "), in.View(4), "it should omit the head to show the cursor")
+
+	in.HandleKey(tui.Key{Code: tui.KeyHome})
+
+	assert.Equal(t, tui.Reverse("a")+"bcd", in.View(4), "it should cut the tail")
+	assert.Empty(t, in.View(0))
+}
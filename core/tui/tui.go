@@ -0,0 +1,355 @@
+/*
+Package tui は全画面のターミナル UI（TUI）のための、画面の描画とキー入力をまと
+めたパッケージです。
+
+画面は golang.org/x/term でターミナルを raw モードにし、ANSI エスケープ・シーケ
+ンスで描画します。描画は行（文字列）の一覧を画面全体に書き込む方式で、部分的な
+更新は行いません。
+*/
+package tui
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// 画面サイズが取得できない場合のデフォルトのサイズ。
+const (
+	WidthDefault  = 80
+	HeightDefault = 24
+)
+
+// 描画に使う ANSI エスケープ・シーケンス。
+const (
+	ansiAltScreenOn  = "\x1b[?1049h" // 代替画面に切り替える
+	ansiAltScreenOff = "\x1b[?1049l" // 代替画面から戻る
+	ansiCursorHide   = "\x1b[?25l"   // カーソルを隠す
+	ansiCursorShow   = "\x1b[?25h"   // カーソルを表示する
+	ansiHome         = "\x1b[H"      // カーソルを左上に移動する
+	ansiClearLine    = "\x1b[K"      // カーソルから行末までを消去する
+	ansiClearBelow   = "\x1b[J"      // カーソルから画面の最後までを消去する
+)
+
+// KeyCode はキーの種類です。
+type KeyCode int
+
+// キーの種類。文字の入力は KeyRune で、文字は Key.Rune に入ります。
+const (
+	KeyNone KeyCode = iota // 未対応のキー
+	KeyRune
+	KeyEnter
+	KeyEsc
+	KeyTab
+	KeyBackspace
+	KeyDelete
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyPgUp
+	KeyPgDn
+	KeyCtrlC
+	KeyEOF // 入力の終わり
+)
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// escapes は CSI（"ESC [" もしくは "ESC O"）に続くシーケンスとキーの対応です。
+var escapes = map[string]KeyCode{
+	"A":  KeyUp,
+	"B":  KeyDown,
+	"C":  KeyRight,
+	"D":  KeyLeft,
+	"H":  KeyHome,
+	"F":  KeyEnd,
+	"1~": KeyHome,
+	"7~": KeyHome,
+	"4~": KeyEnd,
+	"8~": KeyEnd,
+	"3~": KeyDelete,
+	"5~": KeyPgUp,
+	"6~": KeyPgDn,
+}
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Key は入力されたキーです。
+type Key struct {
+	Code KeyCode // キーの種類
+	Rune rune    // Code が KeyRune の場合の文字
+}
+
+// Screen は全画面の描画先とキーの入力元です。
+type Screen struct {
+	Width   int // 画面の横幅（桁数）
+	Height  int // 画面の行数
+	in      *bufio.Reader
+	out     io.Writer
+	fdOut   int          // 画面サイズを取得するファイル・ディスクリプタ（-1 の場合は取得しない）
+	restore func() error // Open で変更したターミナルの状態を戻す関数
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は in からキーを読み込み、out に width x height の画面を描画する Screen
+// の新規オブジェクトを返します。ターミナルの状態は変更しません。（テストなど、
+// ターミナル以外で使う場合に利用します）
+func New(in io.Reader, out io.Writer, width, height int) *Screen {
+	return &Screen{
+		Width:  width,
+		Height: height,
+		in:     bufio.NewReader(in),
+		out:    out,
+		fdOut:  -1,
+	}
+}
+
+// Open は in と out のターミナルを raw モードの全画面（代替画面）に切り替えた
+// Screen の新規オブジェクトを返します。終了時は Close でターミナルの状態を戻す
+// 必要があります。in がターミナルでない場合はエラーを返します。
+func Open(in, out *os.File) (*Screen, error) {
+	fdIn := int(in.Fd())
+
+	if !term.IsTerminal(fdIn) {
+		return nil, errors.New("ターミナル以外では全画面表示できません")
+	}
+
+	state, err := term.MakeRaw(fdIn)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set terminal to raw mode")
+	}
+
+	screen := New(in, out, WidthDefault, HeightDefault)
+	screen.fdOut = int(out.Fd())
+	screen.resize()
+	screen.restore = func() error {
+		_, _ = io.WriteString(out, ansiCursorShow+ansiAltScreenOff)
+
+		return term.Restore(fdIn, state)
+	}
+
+	_, _ = io.WriteString(out, ansiAltScreenOn+ansiCursorHide)
+
+	return screen, nil
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Close は Open で変更したターミナルの状態を戻します。New で生成した場合は何も
+// しません。
+func (s *Screen) Close() error {
+	if s.restore == nil {
+		return nil
+	}
+
+	restore := s.restore
+	s.restore = nil
+
+	return errors.Wrap(restore(), "failed to restore terminal")
+}
+
+// Draw は lines を画面の先頭行から描画します。画面の行数を超える行は描画されず、
+// 足りない行は空行になります。各行は Fit などで画面幅に収めておく必要がありま
+// す。
+func (s *Screen) Draw(lines []string) error {
+	s.resize()
+
+	if len(lines) > s.Height {
+		lines = lines[:s.Height]
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString(ansiHome)
+
+	for i, line := range lines {
+		if i > 0 {
+			builder.WriteString("\r\n")
+		}
+
+		builder.WriteString(line + ansiClearLine)
+	}
+
+	builder.WriteString(ansiClearBelow)
+
+	_, err := io.WriteString(s.out, builder.String())
+
+	return errors.Wrap(err, "failed to draw screen")
+}
+
+// ReadKey は入力されたキーを 1 つ読み込んで返します。入力が終わった場合は
+// KeyEOF を返します。
+func (s *Screen) ReadKey() (Key, error) {
+	r, _, err := s.in.ReadRune()
+	if err == io.EOF {
+		return Key{Code: KeyEOF}, nil
+	}
+
+	if err != nil {
+		return Key{}, errors.Wrap(err, "failed to read key")
+	}
+
+	switch r {
+	case '\r', '\n':
+		return Key{Code: KeyEnter}, nil
+	case '\t':
+		return Key{Code: KeyTab}, nil
+	case 0x7f, '\b':
+		return Key{Code: KeyBackspace}, nil
+	case 0x03:
+		return Key{Code: KeyCtrlC}, nil
+	case 0x1b:
+		return s.readEscape(), nil
+	}
+
+	if r < ' ' {
+		return Key{Code: KeyNone}, nil
+	}
+
+	return Key{Code: KeyRune, Rune: r}, nil
+}
+
+// readEscape は ESC に続くエスケープ・シーケンスを読み込んでキーを返します。
+// 続く入力がない場合は KeyEsc を返します。
+func (s *Screen) readEscape() Key {
+	// ターミナルはシーケンスを一度に送るため、続きがなければ単独の ESC
+	if s.in.Buffered() == 0 {
+		return Key{Code: KeyEsc}
+	}
+
+	r, _, err := s.in.ReadRune()
+	if err != nil {
+		return Key{Code: KeyEsc}
+	}
+
+	if r != '[' && r != 'O' {
+		_ = s.in.UnreadRune()
+
+		return Key{Code: KeyEsc}
+	}
+
+	sequence := ""
+
+	for s.in.Buffered() > 0 {
+		r, _, err := s.in.ReadRune()
+		if err != nil {
+			break
+		}
+
+		sequence += string(r)
+
+		// 0x40〜0x7E が終端の文字
+		if r >= 0x40 && r <= 0x7e {
+			break
+		}
+	}
+
+	if code, ok := escapes[sequence]; ok {
+		return Key{Code: code}
+	}
+
+	return Key{Code: KeyNone}
+}
+
+// resize はターミナルの画面サイズを取得し直します。
+func (s *Screen) resize() {
+	if s.fdOut < 0 {
+		return
+	}
+
+	if width, height, err := term.GetSize(s.fdOut); err == nil && width > 0 && height > 0 {
+		s.Width, s.Height = width, height
+	}
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// Fit は str を表示幅 width に収まるように切り詰め、足りない場合は空白で埋めて
+// 返します。全角文字は 2 桁として数えます。str はエスケープ・シーケンスを含ま
+// ない必要があります。
+func Fit(str string, width int) string {
+	var builder strings.Builder
+
+	size := 0
+
+	for _, r := range str {
+		if r == '\t' || r == '\n' || r == '\r' {
+			r = ' '
+		}
+
+		widthRune := text.RuneWidth(r)
+		if size+widthRune > width {
+			break
+		}
+
+		builder.WriteRune(r)
+
+		size += widthRune
+	}
+
+	return builder.String() + strings.Repeat(" ", width-size)
+}
+
+// Reverse は str を反転表示するエスケープ・シーケンスで囲んで返します。
+func Reverse(str string) string {
+	return "\x1b[7m" + str + "\x1b[27m"
+}
+
+// Width は str の表示幅を返します。全角文字は 2 桁として数えます。
+func Width(str string) int {
+	return text.RuneCount(str)
+}
+
+// Wrap は str を表示幅 width ごとに折り返した行の一覧を返します。全角文字は 2
+// 桁として数えます。str はエスケープ・シーケンスを含まない必要があります。
+func Wrap(str string, width int) []string {
+	result := []string{}
+
+	if width < 1 {
+		return result
+	}
+
+	var builder strings.Builder
+
+	size := 0
+
+	for _, r := range str {
+		widthRune := text.RuneWidth(r)
+
+		if size+widthRune > width && size > 0 {
+			result = append(result, builder.String())
+
+			builder.Reset()
+
+			size = 0
+		}
+
+		builder.WriteRune(r)
+
+		size += widthRune
+	}
+
+	return append(result, builder.String())
+}
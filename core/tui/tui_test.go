@@ -0,0 +1,114 @@
+package tui_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/tui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadKey(t *testing.T) {
+	input := "a漢\r\n\t\x7f\x03\x1b[A\x1b[B\x1bOC\x1b[D\x1b[5~\x1b[6~\x1b[3~\x1b[H\x1b[4~\x1b[99~\x1bq\x01"
+	screen := tui.New(strings.NewReader(input), &bytes.Buffer{}, 80, 24)
+
+	expect := []tui.Key{
+		{Code: tui.KeyRune, Rune: 'a'},
+		{Code: tui.KeyRune, Rune: '漢'},
+		{Code: tui.KeyEnter},
+		{Code: tui.KeyEnter},
+		{Code: tui.KeyTab},
+		{Code: tui.KeyBackspace},
+		{Code: tui.KeyCtrlC},
+		{Code: tui.KeyUp},
+		{Code: tui.KeyDown},
+		{Code: tui.KeyRight},
+		{Code: tui.KeyLeft},
+		{Code: tui.KeyPgUp},
+		{Code: tui.KeyPgDn},
+		{Code: tui.KeyDelete},
+		{Code: tui.KeyHome},
+		{Code: tui.KeyEnd},
+		{Code: tui.KeyNone},
+		{Code: tui.KeyEsc},
+		{Code: tui.KeyRune, Rune: 'q'},
+		{Code: tui.KeyNone},
+		{Code: tui.KeyEOF},
+	}
+
+	for i, want := range expect {
+		key, err := screen.ReadKey()
+
+		require.NoError(t, err)
+		assert.Equal(t, want, key, "key #%d", i)
+	}
+}
+
+func TestReadKey_esc_alone(t *testing.T) {
+	screen := tui.New(strings.NewReader("\x1b"), &bytes.Buffer{}, 80, 24)
+
+	key, err := screen.ReadKey()
+
+	require.NoError(t, err)
+	assert.Equal(t, tui.KeyEsc, key.Code)
+}
+
+func TestDraw(t *testing.T) {
+	var buf bytes.Buffer
+
+	screen := tui.New(strings.NewReader(""), &buf, 10, 2)
+
+	require.NoError(t, screen.Draw([]string{"first", "second", "hidden"}))
+
+	assert.Equal(t, "\x1b[Hfirst\x1b[K\r\nsecond\x1b[K\x1b[J", buf.String())
+	assert.NoError(t, screen.Close(), "it should do nothing if not opened")
+}
+
+func TestOpen_not_terminal(t *testing.T) {
+	pathFile := t.TempDir() + "/input.txt"
+
+	require.NoError(t, os.WriteFile(pathFile, []byte("q"), 0o600))
+
+	file, err := os.Open(pathFile)
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	screen, err := tui.Open(file, os.Stdout)
+
+	require.Error(t, err)
+	assert.Nil(t, screen)
+	assert.Contains(t, err.Error(), "ターミナル以外では全画面表示できません")
+}
+
+func TestFit(t *testing.T) {
+	for _, test := range []struct {
+		input  string
+		width  int
+		expect string
+	}{
+		{"abc", 5, "abc  "},
+		{"abcdef", 4, "abcd"},
+		{"日本語", 5, "日本 "},
+		{"日本語", 6, "日本語"},
+		{"a\tb", 3, "a b"},
+		{"abc", 0, ""},
+	} {
+		assert.Equal(t, test.expect, tui.Fit(test.input, test.width), "input: %q, width: %d", test.input, test.width)
+	}
+}
+
+func TestWidth(t *testing.T) {
+	assert.Equal(t, 7, tui.Width("aタスク"))
+	assert.Equal(t, 3, tui.Width(tui.Reverse("abc")), "it should ignore escape sequences")
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, []string{"abc", "def", "g"}, tui.Wrap("abcdefg", 3))
+	assert.Equal(t, []string{"日本", "語a"}, tui.Wrap("日本語a", 5))
+	assert.Equal(t, []string{""}, tui.Wrap("", 5))
+	assert.Empty(t, tui.Wrap("abc", 0))
+}
@@ -0,0 +1,226 @@
+/*
+Package watcher はファイルの変更を監視するパッケージです。
+
+OS のファイル監視（Linux の inotify など）でファイルのあるディレクトリを監視しま
+す。ファイル監視が利用できない場合は、ファイルの更新日時とサイズを定期的に確認
+するポーリングで監視します。
+
+ファイルは一時ファイルからの置き換え（rename）で保存されることが多いため、ファ
+イルではなく、ファイルのあるディレクトリを監視しています。
+*/
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// IntervalDefault はポーリングで監視する場合のデフォルトの確認間隔です。
+const IntervalDefault = time.Second
+
+// delaySettle は変更を検知してから onChange を呼び出すまでの待ち時間です。保存
+// 時の連続した変更（書き込み・置き換えなど）を 1 回の変更としてまとめます。
+const delaySettle = 100 * time.Millisecond
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// NewNotify は fsnotify.NewWatcher のコピーです。テスト時にファイル監視が利用で
+// きない環境をモックする為に変数に代入しています。
+var NewNotify = fsnotify.NewWatcher
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Watcher は複数のファイルの変更を監視する型です。
+type Watcher struct {
+	Interval  time.Duration // ポーリングで監視する場合の確認間隔
+	IsPolling bool          // true の場合はファイル監視を使わずポーリングで監視する
+	paths     []string      // 監視するファイルの絶対パス
+}
+
+// ----------------------------------------------------------------------------
+//  Constructor
+// ----------------------------------------------------------------------------
+
+// New は paths のファイルを監視する Watcher の新規オブジェクトを返します。"" お
+// よび重複したパスは無視されます。ファイルは存在しなくても構いません。（作成さ
+// れた場合に変更として検知されます）
+func New(paths ...string) *Watcher {
+	obj := &Watcher{
+		Interval: IntervalDefault,
+		paths:    []string{},
+	}
+
+	seen := map[string]bool{}
+
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		if pathAbs, err := filepath.Abs(path); err == nil {
+			path = pathAbs
+		}
+
+		if !seen[path] {
+			seen[path] = true
+
+			obj.paths = append(obj.paths, path)
+		}
+	}
+
+	return obj
+}
+
+// ----------------------------------------------------------------------------
+//  Methods
+// ----------------------------------------------------------------------------
+
+// Paths は監視するファイルのパスの一覧を返します。
+func (w *Watcher) Paths() []string {
+	return append([]string{}, w.paths...)
+}
+
+// Watch は ctx がキャンセルされるまでファイルを監視し、変更を検知するたびに
+// onChange を呼び出します。
+//
+// ファイル監視が利用できない場合（監視するディレクトリが存在しない場合を含む）
+// は、ポーリングで監視します。onChange がエラーを返した場合は、監視を終了してそ
+// のエラーを返します。
+func (w *Watcher) Watch(ctx context.Context, onChange func() error) error {
+	if !w.IsPolling {
+		if notify, err := w.newNotify(); err == nil {
+			defer notify.Close()
+
+			return w.watchNotify(ctx, notify, onChange)
+		}
+	}
+
+	return w.watchPolling(ctx, onChange)
+}
+
+// isTarget は path が監視するファイルの場合に true を返します。
+func (w *Watcher) isTarget(path string) bool {
+	path = filepath.Clean(path)
+
+	for _, target := range w.paths {
+		if path == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newNotify は監視するファイルのあるディレクトリを登録した、ファイル監視のオブ
+// ジェクトを返します。
+func (w *Watcher) newNotify() (*fsnotify.Watcher, error) {
+	notify, err := NewNotify()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create file watcher")
+	}
+
+	for _, path := range w.paths {
+		if err := notify.Add(filepath.Dir(path)); err != nil {
+			notify.Close()
+
+			return nil, errors.Wrap(err, "failed to watch directory")
+		}
+	}
+
+	return notify, nil
+}
+
+// states は監視するファイルごとの更新日時とサイズ（存在しない場合は ""）を返し
+// ます。
+func (w *Watcher) states() map[string]string {
+	result := map[string]string{}
+
+	for _, path := range w.paths {
+		if info, err := os.Stat(path); err == nil {
+			result[path] = fmt.Sprintf("%v/%d", info.ModTime().UnixNano(), info.Size())
+		} else {
+			result[path] = ""
+		}
+	}
+
+	return result
+}
+
+// watchNotify はファイル監視のイベントでファイルを監視します。
+func (w *Watcher) watchNotify(ctx context.Context, notify *fsnotify.Watcher, onChange func() error) error {
+	var settle <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-notify.Events:
+			if !ok {
+				return nil
+			}
+
+			if event.Op != fsnotify.Chmod && w.isTarget(event.Name) {
+				settle = time.After(delaySettle)
+			}
+		case err, ok := <-notify.Errors:
+			if !ok {
+				return nil
+			}
+
+			return errors.Wrap(err, "failed to watch files")
+		case <-settle:
+			settle = nil
+
+			if err := onChange(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// watchPolling は w.Interval ごとに更新日時とサイズを比較してファイルを監視しま
+// す。
+func (w *Watcher) watchPolling(ctx context.Context, onChange func() error) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = IntervalDefault
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	before := w.states()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			after := w.states()
+
+			if fmt.Sprint(after) == fmt.Sprint(before) {
+				continue
+			}
+
+			before = after
+
+			if err := onChange(); err != nil {
+				return err
+			}
+		}
+	}
+}
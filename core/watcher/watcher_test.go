@@ -0,0 +1,143 @@
+package watcher_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Qithub-BOT/QiiTask/core/watcher"
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// watchChanges は obj の監視を開始して change を実行し、onChange が呼び出される
+// のを待ちます。onChange が呼び出された回数と Watch のエラーを返します。
+func watchChanges(t *testing.T, obj *watcher.Watcher, change func()) (int, error) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	count := 0
+	done := make(chan error, 1)
+
+	go func() {
+		done <- obj.Watch(ctx, func() error {
+			count++
+
+			cancel()
+
+			return nil
+		})
+	}()
+
+	time.Sleep(100 * time.Millisecond) // 監視の開始を待つ
+
+	change()
+
+	err := <-done
+
+	return count, err
+}
+
+func TestNew(t *testing.T) {
+	pathDir := t.TempDir()
+	pathFile := filepath.Join(pathDir, "todo.txt")
+
+	obj := watcher.New(pathFile, "", filepath.Join(pathDir, ".", "todo.txt"))
+
+	assert.Equal(t, []string{pathFile}, obj.Paths(), "it should ignore empty and duplicate paths")
+	assert.Equal(t, watcher.IntervalDefault, obj.Interval)
+	assert.False(t, obj.IsPolling)
+}
+
+func TestWatch_notify(t *testing.T) {
+	pathDir := t.TempDir()
+	pathFile := filepath.Join(pathDir, "todo.txt")
+
+	require.NoError(t, os.WriteFile(pathFile, []byte("task 1\n"), 0o600))
+
+	obj := watcher.New(pathFile)
+	obj.Interval = time.Hour // ポーリングでは検知できない間隔
+
+	count, err := watchChanges(t, obj, func() {
+		// 対象外のファイルの変更は無視される
+		require.NoError(t, os.WriteFile(filepath.Join(pathDir, "other.txt"), []byte("x"), 0o600))
+		require.NoError(t, os.WriteFile(pathFile, []byte("task 1\ntask 2\n"), 0o600))
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestWatch_polling(t *testing.T) {
+	pathFile := filepath.Join(t.TempDir(), "todo.txt")
+
+	obj := watcher.New(pathFile)
+	obj.IsPolling = true
+	obj.Interval = 10 * time.Millisecond
+
+	count, err := watchChanges(t, obj, func() {
+		require.NoError(t, os.WriteFile(pathFile, []byte("task 1\n"), 0o600))
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "it should detect the creation of the file")
+}
+
+func TestWatch_fallback_to_polling(t *testing.T) {
+	oldNewNotify := watcher.NewNotify
+	defer func() {
+		watcher.NewNotify = oldNewNotify
+	}()
+
+	watcher.NewNotify = func() (*fsnotify.Watcher, error) {
+		return nil, errors.New("forced error")
+	}
+
+	pathFile := filepath.Join(t.TempDir(), "todo.txt")
+
+	require.NoError(t, os.WriteFile(pathFile, []byte("task 1\n"), 0o600))
+
+	obj := watcher.New(pathFile)
+	obj.Interval = 10 * time.Millisecond
+
+	count, err := watchChanges(t, obj, func() {
+		require.NoError(t, os.WriteFile(pathFile, []byte("task 1\ntask 2\n"), 0o600))
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestWatch_error_from_onChange(t *testing.T) {
+	pathFile := filepath.Join(t.TempDir(), "todo.txt")
+
+	obj := watcher.New(pathFile)
+	obj.IsPolling = true
+	obj.Interval = 10 * time.Millisecond
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- obj.Watch(context.Background(), func() error {
+			return errors.New("forced error")
+		})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	require.NoError(t, os.WriteFile(pathFile, []byte("task 1\n"), 0o600))
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "forced error")
+	case <-time.After(5 * time.Second):
+		t.Fatal("it should stop watching when onChange returns an error")
+	}
+}
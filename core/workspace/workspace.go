@@ -0,0 +1,141 @@
+/*
+Package workspace はタスクや設定ファイルを探すためのワークスペースのルートを、
+git のように親ディレクトリを遡って検索するパッケージです。
+
+検索は開始ディレクトリから親ディレクトリに向かって行われ、以下のいずれかに該当
+するディレクトリで終了します。
+
+ 1. 目印となるファイル（"todo.txt" など）が存在する
+ 2. ".qiitask" ディレクトリが存在する
+ 3. バージョン管理システムのルート（".git" などのディレクトリが存在する）
+ 4. ユーザのホームディレクトリの直下（ホームディレクトリは検索しない）
+ 5. ファイル・システムのルート
+
+ホームディレクトリのタスクはグローバル・タスクのため、ホームディレクトリ下から
+の検索ではホームディレクトリに遡りません。
+*/
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/KEINOS/go-utiles/util"
+)
+
+// ----------------------------------------------------------------------------
+//  Constants
+// ----------------------------------------------------------------------------
+
+// NameDirConf はワークスペースのアプリ用ディレクトリ名です。
+const NameDirConf = ".qiitask"
+
+// ----------------------------------------------------------------------------
+//  Global Variables
+// ----------------------------------------------------------------------------
+
+// NamesDirVCS はバージョン管理システムのルートの目印となるディレクトリ名です。
+var NamesDirVCS = []string{".git", ".hg", ".svn", ".bzr"}
+
+// OsUserHomeDir は os.UserHomeDir のコピーです。テスト時に os.UserHomeDir の動作
+// をモックする為に変数に代入しています。
+var OsUserHomeDir = os.UserHomeDir
+
+// ----------------------------------------------------------------------------
+//  型の定義
+// ----------------------------------------------------------------------------
+
+// Step は検索した 1 ディレクトリ分の記録です。
+type Step struct {
+	Dir    string // 検索したディレクトリ
+	Reason string // 検索を終了した理由（検索を続行した場合は ""）
+}
+
+// Result は検索結果です。
+type Result struct {
+	Root  string // 検索を終了したディレクトリ（ワークスペースのルート）
+	Chain []Step // 検索したディレクトリの経路（開始ディレクトリから順）
+}
+
+// ----------------------------------------------------------------------------
+//  Functions
+// ----------------------------------------------------------------------------
+
+// Search は pathDirStart から親ディレクトリを遡ってワークスペースのルートを検索
+// します。
+//
+// markers は目印となるファイル名です。各ディレクトリ直下およびその ".qiitask"
+// ディレクトリ下に markers のいずれかが存在した場合に検索を終了します。
+func Search(pathDirStart string, markers ...string) *Result {
+	result := new(Result)
+
+	pathDir, err := filepath.Abs(pathDirStart)
+	if err != nil {
+		pathDir = filepath.Clean(pathDirStart)
+	}
+
+	pathDirHome, err := OsUserHomeDir()
+	if err != nil {
+		pathDirHome = ""
+	} else {
+		pathDirHome = filepath.Clean(pathDirHome)
+	}
+
+	for {
+		reason := stopReason(pathDir, pathDirHome, markers)
+
+		result.Chain = append(result.Chain, Step{Dir: pathDir, Reason: reason})
+
+		if reason != "" {
+			break
+		}
+
+		pathDir = filepath.Dir(pathDir)
+	}
+
+	result.Root = pathDir
+
+	return result
+}
+
+// ----------------------------------------------------------------------------
+//  Private Functions
+// ----------------------------------------------------------------------------
+
+// stopReason は pathDir で検索を終了する場合に、その理由を返します。検索を続行
+// する場合は "" を返します。pathDirHome はユーザのホームディレクトリです。
+func stopReason(pathDir, pathDirHome string, markers []string) string {
+	for _, marker := range markers {
+		for _, pathFile := range []string{
+			filepath.Join(pathDir, marker),
+			filepath.Join(pathDir, NameDirConf, marker),
+		} {
+			if util.IsFile(pathFile) {
+				return fmt.Sprintf("found %v", pathFile)
+			}
+		}
+	}
+
+	if util.IsDir(filepath.Join(pathDir, NameDirConf)) {
+		return fmt.Sprintf("found %v directory", NameDirConf)
+	}
+
+	for _, nameVCS := range NamesDirVCS {
+		// git の worktree や submodule では ".git" はファイルになります
+		if pathVCS := filepath.Join(pathDir, nameVCS); util.IsDir(pathVCS) || util.IsFile(pathVCS) {
+			return fmt.Sprintf("VCS root (%v)", nameVCS)
+		}
+	}
+
+	// ホームディレクトリはグローバル・タスクの場所のため遡らない
+	if pathDirHome != "" && pathDir != pathDirHome && filepath.Dir(pathDir) == pathDirHome {
+		return "below home directory"
+	}
+
+	if filepath.Dir(pathDir) == pathDir {
+		return "filesystem root"
+	}
+
+	return ""
+}
@@ -0,0 +1,96 @@
+package workspace_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Qithub-BOT/QiiTask/core/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	pathDirRoot := t.TempDir()
+	pathDirSub := filepath.Join(pathDirRoot, "foo", "bar")
+
+	require.NoError(t, os.MkdirAll(pathDirSub, 0o755))
+
+	for _, test := range []struct {
+		msgErr       string
+		prepare      func(t *testing.T)
+		expectReason string
+	}{
+		{
+			"it should stop at the VCS root",
+			func(t *testing.T) {
+				t.Helper()
+				require.NoError(t, os.Mkdir(filepath.Join(pathDirRoot, ".git"), 0o755))
+			},
+			"VCS root (.git)",
+		},
+		{
+			"it should stop at the dir with .qiitask dir",
+			func(t *testing.T) {
+				t.Helper()
+				require.NoError(t, os.Mkdir(filepath.Join(pathDirRoot, "foo", ".qiitask"), 0o755))
+			},
+			"found .qiitask directory",
+		},
+		{
+			"it should stop at the dir with the marker file",
+			func(t *testing.T) {
+				t.Helper()
+				require.NoError(t, os.WriteFile(filepath.Join(pathDirRoot, "foo", "todo.txt"), []byte{}, 0o600))
+			},
+			"found " + filepath.Join(pathDirRoot, "foo", "todo.txt"),
+		},
+	} {
+		test.prepare(t)
+
+		result := workspace.Search(pathDirSub, "todo.txt")
+
+		require.NotEmpty(t, result.Chain)
+		assert.Equal(t, pathDirSub, result.Chain[0].Dir, "the chain should start from the given dir")
+
+		last := result.Chain[len(result.Chain)-1]
+
+		assert.Equal(t, result.Root, last.Dir, "the last step should be the root")
+		assert.Equal(t, test.expectReason, last.Reason, test.msgErr)
+	}
+}
+
+func TestSearch_filesystem_root(t *testing.T) {
+	result := workspace.Search(string(filepath.Separator))
+
+	require.Len(t, result.Chain, 1)
+	assert.Equal(t, "filesystem root", result.Chain[0].Reason)
+}
+
+func TestSearch_stop_below_home(t *testing.T) {
+	pathDirHome := t.TempDir()
+	pathDirSub := filepath.Join(pathDirHome, "proj", "sub")
+
+	require.NoError(t, os.MkdirAll(pathDirSub, 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(pathDirHome, ".qiitask"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(pathDirHome, ".qiitask", "todo.txt"), []byte{}, 0o600))
+
+	oldOsUserHomeDir := workspace.OsUserHomeDir
+	defer func() {
+		workspace.OsUserHomeDir = oldOsUserHomeDir
+	}()
+
+	workspace.OsUserHomeDir = func() (string, error) {
+		return pathDirHome, nil
+	}
+
+	result := workspace.Search(pathDirSub, "todo.txt")
+
+	assert.Equal(t, filepath.Join(pathDirHome, "proj"), result.Root, "it should not search the home directory")
+	assert.Equal(t, "below home directory", result.Chain[len(result.Chain)-1].Reason)
+
+	// The home directory itself is searched if it is the start
+	result = workspace.Search(pathDirHome, "todo.txt")
+
+	assert.Equal(t, pathDirHome, result.Root)
+}
@@ -7,6 +7,7 @@ require (
 	github.com/1set/todotxt v0.0.4
 	github.com/AlecAivazis/survey/v2 v2.3.2
 	github.com/KEINOS/go-utiles v1.5.2
+	github.com/fsnotify/fsnotify v1.5.1
 	github.com/jedib0t/go-pretty/v6 v6.2.4
 	github.com/kami-zh/go-capturer v0.0.0-20171211120116-e492ea43421d
 	github.com/mitchellh/mapstructure v1.4.3
@@ -15,4 +16,6 @@ require (
 	github.com/spf13/viper v1.10.0
 	github.com/stretchr/testify v1.7.0
 	golang.org/x/term v0.0.0-20210503060354-a79de5458b56
+	golang.org/x/text v0.3.7
+	gopkg.in/yaml.v2 v2.4.0
 )